@@ -29,6 +29,7 @@ func (r RomePayloadAttributes) MarshalJSON() ([]byte, error) {
 		NoTxPool              bool                `json:"noTxPool,omitempty" gencodec:"optional"`
 		GasLimit              *hexutil.Uint64     `json:"gasLimit,omitempty" gencodec:"optional"`
 		TxFootprints          []string            `json:"tx_footprints,omitempty" gencodec:"optional"`
+		SolanaBlockhashes     []SolanaBlockhash   `json:"solanaBlockhashes,omitempty" gencodec:"optional"`
 	}
 	var enc RomePayloadAttributes
 	enc.Timestamp = hexutil.Uint64(r.Timestamp)
@@ -52,6 +53,7 @@ func (r RomePayloadAttributes) MarshalJSON() ([]byte, error) {
 	}
 	enc.NoTxPool = r.NoTxPool
 	enc.GasLimit = (*hexutil.Uint64)(r.GasLimit)
+	enc.SolanaBlockhashes = r.SolanaBlockhashes
 	return json.Marshal(&enc)
 }
 
@@ -71,6 +73,7 @@ func (r *RomePayloadAttributes) UnmarshalJSON(input []byte) error {
 		NoTxPool              *bool               `json:"noTxPool,omitempty" gencodec:"optional"`
 		GasLimit              *hexutil.Uint64     `json:"gasLimit,omitempty" gencodec:"optional"`
 		TxFootprints          []string 			  `json:"txFootprints,omitempty" gencodec:"optional"`
+		SolanaBlockhashes     []SolanaBlockhash   `json:"solanaBlockhashes,omitempty" gencodec:"optional"`
 	}
 	var dec RomePayloadAttributes
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -121,6 +124,9 @@ func (r *RomePayloadAttributes) UnmarshalJSON(input []byte) error {
 	}
 
 	r.TxFootprints = dec.TxFootprints
+	if dec.SolanaBlockhashes != nil {
+		r.SolanaBlockhashes = dec.SolanaBlockhashes
+	}
 
 	return nil
 }
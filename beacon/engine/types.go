@@ -67,6 +67,14 @@ type RomePayloadAttributes struct {
 	GasLimit *uint64 `json:"gasLimit,omitempty" gencodec:"optional"`
 	// TxFootprints is a field which allows Rome indexer to push hash of rome-evm state for comparison with evm.
 	TxFootprints []string `json:"txFootprints,omitempty" gencodec:"optional"`
+	// SolanaBlockhashes carries the blockhashes of the recent Solana slots, served to BLOCKHASH.
+	SolanaBlockhashes []SolanaBlockhash `json:"solanaBlockhashes,omitempty" gencodec:"optional"`
+}
+
+// SolanaBlockhash is the blockhash of a Solana slot.
+type SolanaBlockhash struct {
+	Slot hexutil.Uint64 `json:"slot"`
+	Hash common.Hash    `json:"hash"`
 }
 
 // JSON type overrides for PayloadAttributes.
@@ -141,6 +149,7 @@ type RomeExecutableData struct {
 	TxFootprints      []string            `json:"txFootprints,omitempty" gencodec:"optional"`
 	RomeGasPrice      []uint64            `json:"romeGasPrice"   gencodec:"required"`
 	SolanaBlockNumber *hexutil.Uint64     `json:"solanaBlockNumber,omitempty" gencodec:"optional"`
+	SolanaBlockhashes []SolanaBlockhash   `json:"solanaBlockhashes,omitempty" gencodec:"optional"`
 }
 
 // JSON type overrides for RomeExecutableData.
@@ -367,3 +376,25 @@ type ExecutionPayloadBodyV1 struct {
 	TransactionData []hexutil.Bytes     `json:"transactions"`
 	Withdrawals     []*types.Withdrawal `json:"withdrawals"`
 }
+
+// RomePayloadBodyV1 is used in the response to GetRomePayloadBodiesByHashV1 and
+// GetRomePayloadBodiesByRangeV1. Next to the block body it carries the Rome
+// execution inputs and Solana metadata the block was imported with, in the same
+// encoding as RomeExecutableData, so that it can be replayed on another node.
+type RomePayloadBodyV1 struct {
+	TransactionData   []hexutil.Bytes     `json:"transactions"`
+	Withdrawals       []*types.Withdrawal `json:"withdrawals"`
+	RomeGasUsed       []uint64            `json:"romeGasUsed"`
+	RomeGasPrice      []uint64            `json:"romeGasPrice"`
+	TxFootprints      []string            `json:"txFootprints"`
+	SolanaBlockNumber *hexutil.Uint64     `json:"solanaBlockNumber,omitempty"`
+	SolanaTxs         []*SolanaTxMetadata `json:"solanaTxs"`
+	SolanaBlockhashes []SolanaBlockhash   `json:"solanaBlockhashes"`
+}
+
+// SolanaTxMetadata is the Solana slot and timestamp a transaction was executed
+// at.
+type SolanaTxMetadata struct {
+	Slot      hexutil.Uint64 `json:"slot"`
+	Timestamp hexutil.Uint64 `json:"timestamp"`
+}
@@ -23,6 +23,7 @@ import (
 	"os"
 	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -43,6 +44,7 @@ import (
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/tracing"
 	"github.com/naoina/toml"
 	"github.com/urfave/cli/v2"
 )
@@ -95,6 +97,7 @@ type gethConfig struct {
 	Node     node.Config
 	Ethstats ethstatsConfig
 	Metrics  metrics.Config
+	Tracing  tracing.Config
 }
 
 func loadConfig(file string, cfg *gethConfig) error {
@@ -131,6 +134,7 @@ func loadBaseConfig(ctx *cli.Context) gethConfig {
 		Eth:     ethconfig.Defaults,
 		Node:    defaultNodeConfig(),
 		Metrics: metrics.DefaultConfig,
+		Tracing: tracing.DefaultConfig,
 	}
 
 	// Load config file.
@@ -162,6 +166,7 @@ func makeConfigNode(ctx *cli.Context) (*node.Node, gethConfig) {
 		cfg.Ethstats.URL = ctx.String(utils.EthStatsURLFlag.Name)
 	}
 	applyMetricConfig(ctx, &cfg)
+	applyTracingConfig(ctx, &cfg)
 
 	return stack, cfg
 }
@@ -217,6 +222,10 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 	if ctx.IsSet(utils.GraphQLEnabledFlag.Name) {
 		utils.RegisterGraphQLService(stack, backend, filterSystem, &cfg.Node)
 	}
+	// Export OpenTelemetry traces if requested.
+	if cfg.Tracing.Enabled {
+		utils.RegisterTracingService(stack, &cfg.Tracing)
+	}
 	// Add the Ethereum Stats daemon if requested.
 	if cfg.Ethstats.URL != "" {
 		utils.RegisterEthStatsService(stack, backend, cfg.Ethstats.URL)
@@ -236,6 +245,7 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 		if err != nil {
 			utils.Fatalf("failed to register dev mode catalyst service: %v", err)
 		}
+		simBeacon.SetRomeMode(ctx.Bool(utils.DeveloperRomeFlag.Name))
 		catalyst.RegisterSimulatedBeaconAPIs(stack, simBeacon)
 		stack.RegisterLifecycle(simBeacon)
 	} else {
@@ -321,6 +331,39 @@ func applyMetricConfig(ctx *cli.Context, cfg *gethConfig) {
 	}
 }
 
+func applyTracingConfig(ctx *cli.Context, cfg *gethConfig) {
+	// Honour the environment variables tracing used to be configured with
+	if enabled, err := strconv.ParseBool(os.Getenv("ENABLE_OTEL_TRACING")); err == nil && enabled {
+		log.Warn("The ENABLE_OTEL_TRACING environment variable is deprecated, please use --tracing")
+		cfg.Tracing.Enabled = true
+		cfg.Tracing.Insecure = true
+		if endpoint := os.Getenv("OTLP_RECEIVER_URL"); endpoint != "" {
+			cfg.Tracing.Endpoint = endpoint
+		}
+	}
+	if ctx.IsSet(utils.TracingEnabledFlag.Name) {
+		cfg.Tracing.Enabled = ctx.Bool(utils.TracingEnabledFlag.Name)
+	}
+	if ctx.IsSet(utils.TracingEndpointFlag.Name) {
+		cfg.Tracing.Endpoint = ctx.String(utils.TracingEndpointFlag.Name)
+	}
+	if ctx.IsSet(utils.TracingServiceNameFlag.Name) {
+		cfg.Tracing.ServiceName = ctx.String(utils.TracingServiceNameFlag.Name)
+	}
+	if ctx.IsSet(utils.TracingSampleRatioFlag.Name) {
+		cfg.Tracing.SampleRatio = ctx.Float64(utils.TracingSampleRatioFlag.Name)
+	}
+	if ctx.IsSet(utils.TracingInsecureFlag.Name) {
+		cfg.Tracing.Insecure = ctx.Bool(utils.TracingInsecureFlag.Name)
+	}
+	if ctx.IsSet(utils.TracingCACertFlag.Name) {
+		cfg.Tracing.CACert = ctx.String(utils.TracingCACertFlag.Name)
+	}
+	if ctx.IsSet(utils.TracingHeadersFlag.Name) {
+		cfg.Tracing.Headers = utils.SplitTagsFlag(ctx.String(utils.TracingHeadersFlag.Name))
+	}
+}
+
 func deprecated(field string) bool {
 	switch field {
 	case "ethconfig.Config.EVMInterpreter":
@@ -83,6 +83,8 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolPrivateLifetimeFlag,
+		utils.TxPoolPrivateContentFlag,
 		utils.BlobPoolDataDirFlag,
 		utils.BlobPoolDataCapFlag,
 		utils.BlobPoolPriceBumpFlag,
@@ -94,6 +96,9 @@ var (
 		utils.TxLookupLimitFlag, // deprecated
 		utils.TransactionHistoryFlag,
 		utils.StateHistoryFlag,
+		utils.StateHistoryArchiveFlag,
+		utils.StateHistoryLookbackFlag,
+		utils.StateHistoryReadLimitFlag,
 		utils.LightServeFlag,    // deprecated
 		utils.LightIngressFlag,  // deprecated
 		utils.LightEgressFlag,   // deprecated
@@ -127,6 +132,8 @@ var (
 		utils.MinerExtraDataFlag,
 		utils.MinerRecommitIntervalFlag,
 		utils.MinerNewPayloadTimeout,
+		utils.MinerTxOrderingFlag,
+		utils.MinerTxOrderingSenderLimitFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV4Flag,
@@ -139,6 +146,7 @@ var (
 		utils.DeveloperFlag,
 		utils.DeveloperGasLimitFlag,
 		utils.DeveloperPeriodFlag,
+		utils.DeveloperRomeFlag,
 		utils.VMEnableDebugFlag,
 		utils.NetworkIdFlag,
 		utils.EthStatsURLFlag,
@@ -149,12 +157,26 @@ var (
 		utils.GpoIgnoreGasPriceFlag,
 		utils.GpoMinSuggestedPriorityFeeFlag,
 		utils.RollupSequencerHTTPFlag,
+		utils.RollupSequencerTimeoutFlag,
+		utils.RollupSequencerRetriesFlag,
+		utils.RollupSequencerRetryIntervalFlag,
+		utils.RollupSequencerQueueSizeFlag,
+		utils.RollupEnableTxPoolAdmissionFlag,
 		utils.RollupHistoricalRPCFlag,
 		utils.RollupHistoricalRPCTimeoutFlag,
 		utils.RollupDisableTxPoolGossipFlag,
 		utils.RollupComputePendingBlock,
 		utils.RollupHaltOnIncompatibleProtocolVersionFlag,
+		utils.RollupSequencerTxConditionalEnabledFlag,
+		utils.RollupSequencerTxConditionalCostRateLimitFlag,
 		utils.RollupSuperchainUpgradesFlag,
+		utils.RomeGasometerFlag,
+		utils.RomeGasometerTimeoutFlag,
+		utils.RomeGasometerRetriesFlag,
+		utils.RomeGasometerLocalFlag,
+		utils.RomeGasometerFallbackFlag,
+		utils.RomeGasometerSkipEmulationFlag,
+		utils.RomeFootprintPolicyFlag,
 		configFileFlag,
 		utils.LogDebugFlag,
 		utils.LogBacktraceAtFlag,
@@ -207,6 +229,13 @@ var (
 		utils.MetricsInfluxDBTokenFlag,
 		utils.MetricsInfluxDBBucketFlag,
 		utils.MetricsInfluxDBOrganizationFlag,
+		utils.TracingEnabledFlag,
+		utils.TracingEndpointFlag,
+		utils.TracingServiceNameFlag,
+		utils.TracingSampleRatioFlag,
+		utils.TracingInsecureFlag,
+		utils.TracingCACertFlag,
+		utils.TracingHeadersFlag,
 	}
 )
 
@@ -246,6 +275,8 @@ func init() {
 		snapshotCommand,
 		// See verkle.go
 		verkleCommand,
+		// See romecmd.go
+		romeCommand,
 	}
 	if logTestCommand != nil {
 		app.Commands = append(app.Commands, logTestCommand)
@@ -0,0 +1,459 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/footprint"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	replayFixtureFlag = &cli.StringFlag{
+		Name:  "fixture",
+		Usage: "Directory to write a t8n fixture of the first diverging transaction into",
+	}
+	romeCommand = &cli.Command{
+		Name:  "rome",
+		Usage: "A set of commands for inspecting Rome chains",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "replay",
+				Usage:     "Re-execute a block range with the stored Rome inputs",
+				ArgsUsage: "<first> [<last>]",
+				Action:    romeReplay,
+				Flags:     flags.Merge([]cli.Flag{replayFixtureFlag}, utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth rome replay <first> [<last>]
+
+Opens the database read-only and re-executes the given block range on top of
+the state of the parent of the first block, using the Rome gas vectors, Solana
+metadata and footprints stored at block insertion. The footprint, state root and
+receipt of every transaction are compared against the recorded ones and any
+difference is reported. Nothing is written to the database.
+
+If --fixture is set, the pre-state, block environment and transaction of the
+first diverging transaction are written into the given directory as the alloc,
+env and txs inputs of 'evm t8n', along with the Rome inputs of the transaction.`,
+			},
+		},
+	}
+)
+
+// replayContext is the chain context replayed blocks are executed in. It tracks
+// the footprints of the executed transactions in a throwaway manager, leaving
+// the chain itself untouched.
+type replayContext struct {
+	*core.BlockChain
+	manager *footprint.Manager
+}
+
+func (c *replayContext) GetFootprintManager() *footprint.Manager {
+	return c.manager
+}
+
+// txReplay is the outcome of re-executing a single transaction.
+type txReplay struct {
+	index             int
+	hash              common.Hash
+	expectedFootprint string
+	actualFootprint   string
+	root              common.Hash // Intermediate state root after the transaction
+	receiptDiffs      []string    // Differences from the stored receipt
+	pre               *state.StateDB
+}
+
+// diverged reports whether the re-execution of the transaction disagrees with
+// the recorded one.
+func (r *txReplay) diverged() bool {
+	return r.footprintMismatch() || len(r.receiptDiffs) > 0
+}
+
+// footprintMismatch reports whether the re-executed footprint differs from the
+// one recorded for the transaction. Transactions without a recorded footprint
+// are not compared.
+func (r *txReplay) footprintMismatch() bool {
+	expected := common.HexToHash(r.expectedFootprint)
+	return expected != (common.Hash{}) && expected != common.HexToHash(r.actualFootprint)
+}
+
+// blockReplay is the outcome of re-executing a block.
+type blockReplay struct {
+	block       *types.Block
+	txs         []*txReplay
+	root        common.Hash
+	receiptHash common.Hash
+}
+
+// diverged reports whether the re-execution of the block disagrees with the
+// recorded one.
+func (r *blockReplay) diverged() bool {
+	if r.root != r.block.Root() || r.receiptHash != r.block.ReceiptHash() {
+		return true
+	}
+	for _, tx := range r.txs {
+		if tx.diverged() {
+			return true
+		}
+	}
+	return false
+}
+
+func romeReplay(ctx *cli.Context) error {
+	if ctx.NArg() < 1 || ctx.NArg() > 2 {
+		return errors.New("expected a block number or a block range")
+	}
+	first, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid first block: %v", err)
+	}
+	last := first
+	if ctx.NArg() == 2 {
+		if last, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+			return fmt.Errorf("invalid last block: %v", err)
+		}
+	}
+	if first == 0 || last < first {
+		return fmt.Errorf("invalid block range %d-%d", first, last)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack, true)
+	defer db.Close()
+	defer chain.Stop()
+
+	fixture := ctx.String(replayFixtureFlag.Name)
+	diverged, err := replayBlocks(chain, first, last, fixture != "", func(res *blockReplay) error {
+		printBlockReplay(res)
+		if fixture == "" {
+			return nil
+		}
+		for _, tx := range res.txs {
+			if tx.diverged() {
+				if err := writeReplayFixture(fixture, chain, res.block, tx); err != nil {
+					return err
+				}
+				fmt.Printf("Wrote fixture of transaction %x to %s\n", tx.hash, fixture)
+				fixture = ""
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if diverged > 0 {
+		return fmt.Errorf("%d of %d blocks diverged", diverged, last-first+1)
+	}
+	fmt.Printf("Replayed %d blocks without differences\n", last-first+1)
+	return nil
+}
+
+// replayBlocks re-executes the canonical blocks in the given range on top of
+// the state of the parent of the first one, invoking the callback with the
+// outcome of every block. It returns the number of diverging blocks. If keepPre
+// is set, the pre-state of every transaction is retained in the results.
+func replayBlocks(chain *core.BlockChain, first, last uint64, keepPre bool, callback func(*blockReplay) error) (int, error) {
+	parent := chain.GetHeaderByNumber(first - 1)
+	if parent == nil {
+		return 0, fmt.Errorf("block %d not found", first-1)
+	}
+	statedb, err := chain.StateAt(parent.Root)
+	if err != nil {
+		return 0, fmt.Errorf("state of block %d unavailable: %v", first-1, err)
+	}
+	dir, err := os.MkdirTemp("", "rome-replay")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		rc       = &replayContext{BlockChain: chain, manager: footprint.NewManager(rawdb.NewMemoryDatabase(), dir, footprint.PolicyLog)}
+		diverged int
+	)
+	for number := first; number <= last; number++ {
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			return diverged, fmt.Errorf("block %d not found", number)
+		}
+		res, err := replayBlock(rc, block, statedb, keepPre)
+		if err != nil {
+			return diverged, fmt.Errorf("failed to replay block %d: %v", number, err)
+		}
+		if res.diverged() {
+			diverged++
+		}
+		if err := callback(res); err != nil {
+			return diverged, err
+		}
+	}
+	return diverged, nil
+}
+
+// replayBlock re-executes a block on the given state the way the state processor
+// does, with the Rome inputs stored for the block, comparing the outcome of each
+// transaction against the recorded one.
+func replayBlock(rc *replayContext, block *types.Block, statedb *state.StateDB, keepPre bool) (*blockReplay, error) {
+	var (
+		config   = rc.Config()
+		header   = block.Header()
+		usedGas  = new(uint64)
+		gp       = new(core.GasPool).AddGas(block.GasLimit())
+		romeData = rc.GetRomeBlockData(block.Hash(), block.NumberU64())
+		stored   = rc.GetReceiptsByHash(block.Hash())
+		receipts types.Receipts
+		res      = &blockReplay{block: block}
+	)
+	if config.DAOForkSupport && config.DAOForkBlock != nil && config.DAOForkBlock.Cmp(block.Number()) == 0 {
+		misc.ApplyDAOHardFork(statedb)
+	}
+	misc.EnsureCreate2Deployer(config, block.Time(), statedb)
+
+	if beaconRoot := block.BeaconRoot(); beaconRoot != nil {
+		context := core.NewEVMBlockContext(header, rc, nil, config, statedb)
+		vmenv := vm.NewEVM(context, vm.TxContext{}, statedb, config, vm.Config{})
+		core.ProcessBeaconBlockRoot(*beaconRoot, vmenv, statedb)
+	}
+	for i, tx := range block.Transactions() {
+		statedb.SetTxContext(tx.Hash(), i)
+
+		var (
+			slot      *uint64
+			timestamp *int64
+		)
+		if s, ts, ok := rc.GetSolanaTxMetadata(tx.Hash()); ok {
+			slot, timestamp = &s, &ts
+		}
+		txRes := &txReplay{index: i, hash: tx.Hash(), expectedFootprint: romeData.TxFootprint(i)}
+		if keepPre {
+			txRes.pre = statedb.Copy()
+		}
+		gasUsed, gasPrice := romeData.TxGas(i)
+		receipt, err := core.ApplyTransactionWithSolana(context.Background(), config, rc, nil, gp, statedb, header, tx, usedGas, vm.Config{}, gasUsed, txRes.expectedFootprint, gasPrice, slot, timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+		if entry, ok := rc.manager.Get(tx.Hash()); ok {
+			txRes.actualFootprint = entry.ActualFootprint
+		}
+		txRes.root = statedb.IntermediateRoot(config.IsEIP158(block.Number()))
+		if i < len(stored) {
+			txRes.receiptDiffs = diffReceipts(stored[i], receipt)
+		} else if len(stored) > 0 {
+			txRes.receiptDiffs = []string{"no stored receipt"}
+		}
+		receipts = append(receipts, receipt)
+		res.txs = append(res.txs, txRes)
+	}
+	rc.Engine().Finalize(rc, header, statedb, block.Transactions(), block.Uncles(), block.Withdrawals())
+
+	res.root = statedb.IntermediateRoot(config.IsEIP158(block.Number()))
+	res.receiptHash = types.DeriveSha(receipts, trie.NewStackTrie(nil))
+	return res, nil
+}
+
+// diffReceipts lists the consensus fields of a re-executed receipt which differ
+// from the stored one.
+func diffReceipts(stored, replayed *types.Receipt) []string {
+	var diffs []string
+	if stored.Status != replayed.Status {
+		diffs = append(diffs, fmt.Sprintf("status: have %d, want %d", replayed.Status, stored.Status))
+	}
+	if stored.CumulativeGasUsed != replayed.CumulativeGasUsed {
+		diffs = append(diffs, fmt.Sprintf("cumulative gas used: have %d, want %d", replayed.CumulativeGasUsed, stored.CumulativeGasUsed))
+	}
+	if len(stored.Logs) != len(replayed.Logs) {
+		diffs = append(diffs, fmt.Sprintf("logs: have %d, want %d", len(replayed.Logs), len(stored.Logs)))
+	}
+	if stored.Bloom != replayed.Bloom {
+		diffs = append(diffs, "logs bloom mismatch")
+	}
+	if len(stored.PostState) > 0 && common.BytesToHash(stored.PostState) != common.BytesToHash(replayed.PostState) {
+		diffs = append(diffs, fmt.Sprintf("post state: have %x, want %x", replayed.PostState, stored.PostState))
+	}
+	return diffs
+}
+
+// printBlockReplay reports the outcome of a replayed block.
+func printBlockReplay(res *blockReplay) {
+	if !res.diverged() {
+		fmt.Printf("Block %d [%x]: ok (%d txs)\n", res.block.NumberU64(), res.block.Hash(), len(res.txs))
+		return
+	}
+	fmt.Printf("Block %d [%x]: diverged\n", res.block.NumberU64(), res.block.Hash())
+	if res.root != res.block.Root() {
+		fmt.Printf("  state root: have %x, want %x\n", res.root, res.block.Root())
+	}
+	if res.receiptHash != res.block.ReceiptHash() {
+		fmt.Printf("  receipt root: have %x, want %x\n", res.receiptHash, res.block.ReceiptHash())
+	}
+	for _, tx := range res.txs {
+		if !tx.diverged() {
+			continue
+		}
+		fmt.Printf("  tx %d [%x]: state root %x\n", tx.index, tx.hash, tx.root)
+		if tx.footprintMismatch() {
+			fmt.Printf("    footprint: have %s, want %s\n", tx.actualFootprint, tx.expectedFootprint)
+		}
+		for _, diff := range tx.receiptDiffs {
+			fmt.Printf("    receipt %s\n", diff)
+		}
+	}
+}
+
+// replayEnv is the block environment of a fixture, in the format of the env
+// input of 'evm t8n'.
+type replayEnv struct {
+	Coinbase              common.Address                      `json:"currentCoinbase"`
+	Difficulty            *math.HexOrDecimal256               `json:"currentDifficulty"`
+	Random                *math.HexOrDecimal256               `json:"currentRandom,omitempty"`
+	GasLimit              math.HexOrDecimal64                 `json:"currentGasLimit"`
+	Number                math.HexOrDecimal64                 `json:"currentNumber"`
+	Timestamp             math.HexOrDecimal64                 `json:"currentTimestamp"`
+	BaseFee               *math.HexOrDecimal256               `json:"currentBaseFee,omitempty"`
+	BlockHashes           map[math.HexOrDecimal64]common.Hash `json:"blockHashes,omitempty"`
+	ParentBeaconBlockRoot *common.Hash                        `json:"parentBeaconBlockRoot,omitempty"`
+}
+
+// replayRomeInputs are the Rome inputs of a fixture transaction, which 'evm t8n'
+// has no notion of.
+type replayRomeInputs struct {
+	TxHash            common.Hash `json:"txHash"`
+	GasUsed           uint64      `json:"gasUsed"`
+	GasPrice          uint64      `json:"gasPrice"`
+	ExpectedFootprint string      `json:"expectedFootprint"`
+	ActualFootprint   string      `json:"actualFootprint"`
+	FootprintVersion  uint64      `json:"footprintVersion"`
+	SolanaSlot        *uint64     `json:"solanaSlot,omitempty"`
+	SolanaTimestamp   *int64      `json:"solanaTimestamp,omitempty"`
+}
+
+// writeReplayFixture writes the inputs to re-execute a single transaction with
+// 'evm t8n' into the given directory: the pre-state of the accounts it touches,
+// the block environment and the transaction, along with its Rome inputs.
+func writeReplayFixture(dir string, chain *core.BlockChain, block *types.Block, tx *txReplay) error {
+	if tx.pre == nil {
+		return errors.New("pre-state not retained")
+	}
+	var (
+		config   = chain.Config()
+		header   = block.Header()
+		signed   = block.Transactions()[tx.index]
+		romeData = chain.GetRomeBlockData(block.Hash(), block.NumberU64())
+	)
+	gasUsed, gasPrice := romeData.TxGas(tx.index)
+	rome := &replayRomeInputs{
+		TxHash:            tx.hash,
+		GasUsed:           gasUsed,
+		GasPrice:          gasPrice,
+		ExpectedFootprint: tx.expectedFootprint,
+		ActualFootprint:   tx.actualFootprint,
+		FootprintVersion:  config.FootprintVersion(header.Time),
+	}
+	if slot, timestamp, ok := chain.GetSolanaTxMetadata(tx.hash); ok {
+		rome.SolanaSlot, rome.SolanaTimestamp = &slot, &timestamp
+	}
+	// Execute the transaction once more on a copy of its pre-state to find the
+	// accounts and storage slots it touches
+	msg, err := core.TransactionToMessage(signed, types.MakeSigner(config, header.Number, header.Time), header.BaseFee)
+	if err != nil {
+		return err
+	}
+	hasher := state.NewFootprintHasher(rome.FootprintVersion)
+	if hasher == nil {
+		return fmt.Errorf("unsupported footprint version %d", rome.FootprintVersion)
+	}
+	probe := tx.pre.Copy()
+	probe.SetTxContext(tx.hash, tx.index)
+
+	txContext := core.NewEVMTxContext(msg)
+	txContext.SolanaBlockNumber, txContext.SolanaTimestamp = rome.SolanaSlot, rome.SolanaTimestamp
+	evm := vm.NewEVM(core.NewEVMBlockContext(header, chain, nil, config, probe), txContext, probe, config, vm.Config{})
+
+	start := probe.JournalLength()
+	if _, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(header.GasLimit), gasUsed, gasPrice); err != nil {
+		return err
+	}
+	alloc := make(core.GenesisAlloc)
+	for _, account := range probe.TxFootprintAccounts(start, hasher) {
+		genesisAccount := core.GenesisAccount{
+			Code:    tx.pre.GetCode(account.Address),
+			Balance: tx.pre.GetBalance(account.Address),
+			Nonce:   tx.pre.GetNonce(account.Address),
+		}
+		if len(account.Slots) > 0 {
+			genesisAccount.Storage = make(map[common.Hash]common.Hash, len(account.Slots))
+			for _, slot := range account.Slots {
+				genesisAccount.Storage[slot.Key] = tx.pre.GetState(account.Address, slot.Key)
+			}
+		}
+		alloc[account.Address] = genesisAccount
+	}
+	env := &replayEnv{
+		Coinbase:              header.Coinbase,
+		Difficulty:            (*math.HexOrDecimal256)(header.Difficulty),
+		GasLimit:              math.HexOrDecimal64(header.GasLimit),
+		Number:                math.HexOrDecimal64(header.Number.Uint64()),
+		Timestamp:             math.HexOrDecimal64(header.Time),
+		BaseFee:               (*math.HexOrDecimal256)(header.BaseFee),
+		BlockHashes:           map[math.HexOrDecimal64]common.Hash{math.HexOrDecimal64(header.Number.Uint64() - 1): header.ParentHash},
+		ParentBeaconBlockRoot: header.ParentBeaconRoot,
+	}
+	if header.Difficulty.Sign() == 0 {
+		env.Random = (*math.HexOrDecimal256)(new(big.Int).SetBytes(header.MixDigest[:]))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for name, v := range map[string]interface{}{
+		"alloc.json": alloc,
+		"env.json":   env,
+		"txs.json":   []*types.Transaction{signed},
+		"rome.json":  rome,
+	} {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
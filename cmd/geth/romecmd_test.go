@@ -0,0 +1,130 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	replayKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	replayAddr    = crypto.PubkeyToAddress(replayKey.PublicKey)
+	replayGenesis = &core.Genesis{
+		Config:  params.TestChainConfig,
+		Alloc:   core.GenesisAlloc{replayAddr: {Balance: big.NewInt(params.Ether)}},
+		BaseFee: common.Big0,
+	}
+)
+
+// newReplayChain creates a chain with a single transfer in block 1, recorded
+// with the given header roots and footprint.
+func newReplayChain(t *testing.T, root, receiptHash common.Hash, footprint string) *core.BlockChain {
+	db := rawdb.NewMemoryDatabase()
+	chain, err := core.NewBlockChain(db, nil, replayGenesis, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	t.Cleanup(chain.Stop)
+
+	tx, err := types.SignNewTx(replayKey, types.LatestSigner(params.TestChainConfig), &types.LegacyTx{
+		To:       &common.Address{0xaa},
+		Value:    big.NewInt(1000),
+		Gas:      params.TxGas,
+		GasPrice: big.NewInt(1),
+	})
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	header := &types.Header{
+		ParentHash:  chain.Genesis().Hash(),
+		Coinbase:    common.Address{0xc0},
+		Root:        root,
+		TxHash:      types.DeriveSha(types.Transactions{tx}, trie.NewStackTrie(nil)),
+		ReceiptHash: receiptHash,
+		Difficulty:  big.NewInt(1),
+		Number:      big.NewInt(1),
+		GasLimit:    chain.Genesis().GasLimit(),
+		Time:        chain.Genesis().Time() + 1,
+		BaseFee:     common.Big0,
+	}
+	block := types.NewBlockWithHeader(header).WithBody(types.Transactions{tx}, nil)
+	rawdb.WriteBlock(db, block)
+	rawdb.WriteCanonicalHash(db, block.Hash(), 1)
+	chain.WriteRomeBlockData(block.Hash(), 1, &types.RomeBlockData{
+		GasUsed:    []uint64{params.TxGas},
+		GasPrice:   []uint64{1},
+		Footprints: []string{footprint},
+	})
+	return chain
+}
+
+// Tests that replaying a block reports footprint and state root divergences,
+// and nothing for a block matching its re-execution.
+func TestRomeReplay(t *testing.T) {
+	var res *blockReplay
+	collect := func(r *blockReplay) error { res = r; return nil }
+
+	chain := newReplayChain(t, common.Hash{}, types.EmptyReceiptsHash, "0x01")
+	diverged, err := replayBlocks(chain, 1, 1, true, collect)
+	if err != nil {
+		t.Fatalf("failed to replay: %v", err)
+	}
+	if diverged != 1 || len(res.txs) != 1 {
+		t.Fatalf("unexpected replay outcome: %d diverged, %d txs", diverged, len(res.txs))
+	}
+	if !res.txs[0].footprintMismatch() || res.root == (common.Hash{}) {
+		t.Fatalf("divergence not reported: footprint %s, root %x", res.txs[0].actualFootprint, res.root)
+	}
+	// Write a fixture of the diverging transaction
+	dir := t.TempDir()
+	if err := writeReplayFixture(dir, chain, res.block, res.txs[0]); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	var alloc core.GenesisAlloc
+	data, err := os.ReadFile(filepath.Join(dir, "alloc.json"))
+	if err != nil {
+		t.Fatalf("failed to read alloc: %v", err)
+	}
+	if err := json.Unmarshal(data, &alloc); err != nil {
+		t.Fatalf("failed to decode alloc: %v", err)
+	}
+	if alloc[replayAddr].Balance.Cmp(big.NewInt(params.Ether)) != 0 {
+		t.Errorf("sender pre-state mismatch: %+v", alloc[replayAddr])
+	}
+	if _, ok := alloc[common.Address{0xaa}]; !ok {
+		t.Error("recipient missing from pre-state")
+	}
+	// Record the block as re-executed, no divergence is expected
+	chain = newReplayChain(t, res.root, res.receiptHash, res.txs[0].actualFootprint)
+	if diverged, err := replayBlocks(chain, 1, 1, false, collect); err != nil || diverged != 0 {
+		t.Fatalf("unexpected replay outcome: %d diverged, err %v", diverged, err)
+	}
+}
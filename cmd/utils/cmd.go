@@ -164,6 +164,11 @@ func ImportChain(chain *core.BlockChain, fn string) error {
 
 	log.Info("Importing blockchain", "file", fn)
 
+	// Load the Rome execution inputs first, the blocks cannot be re-executed
+	// to the same state without them.
+	if err := importRomeData(chain, romeDataFile(fn)); err != nil {
+		return err
+	}
 	// Open the file handle and potentially unwrap the gzip stream
 	fh, err := os.Open(fn)
 	if err != nil {
@@ -267,6 +272,9 @@ func ExportChain(blockchain *core.BlockChain, fn string) error {
 	if err := blockchain.Export(writer); err != nil {
 		return err
 	}
+	if err := exportRomeData(blockchain, romeDataFile(fn), os.O_TRUNC, 0, blockchain.CurrentBlock().Number.Uint64()); err != nil {
+		return err
+	}
 	log.Info("Exported blockchain", "file", fn)
 
 	return nil
@@ -293,10 +301,79 @@ func ExportAppendChain(blockchain *core.BlockChain, fn string, first uint64, las
 	if err := blockchain.ExportN(writer, first, last); err != nil {
 		return err
 	}
+	if err := exportRomeData(blockchain, romeDataFile(fn), os.O_APPEND, first, last); err != nil {
+		return err
+	}
 	log.Info("Exported blockchain to", "file", fn)
 	return nil
 }
 
+// romeDataFile returns the name of the file holding the Rome execution inputs
+// that accompany an exported chain segment.
+func romeDataFile(fn string) string {
+	if strings.HasSuffix(fn, ".gz") {
+		return strings.TrimSuffix(fn, ".gz") + ".rome.gz"
+	}
+	return fn + ".rome"
+}
+
+// exportRomeData writes the Rome execution inputs of a chain segment into the
+// specified file, either truncating or appending to it based on mode.
+func exportRomeData(blockchain *core.BlockChain, fn string, mode int, first uint64, last uint64) error {
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|mode, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	if err := blockchain.ExportRomeN(writer, first, last); err != nil {
+		return err
+	}
+	log.Info("Exported Rome block data", "file", fn)
+	return nil
+}
+
+// importRomeData loads the Rome execution inputs of an exported chain segment
+// into the database. A missing file is not an error, in which case blocks are
+// imported without Rome data.
+func importRomeData(chain *core.BlockChain, fn string) error {
+	fh, err := os.Open(fn)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Warn("No Rome block data found for import", "file", fn)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+	}
+	stream := rlp.NewStream(reader, 0)
+
+	count := 0
+	for {
+		var entry core.RomeExportEntry
+		if err := stream.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("at Rome entry %d: %v", count, err)
+		}
+		chain.WriteRomeBlockData(entry.Hash, entry.Number, entry.Data)
+		count++
+	}
+	log.Info("Imported Rome block data", "file", fn, "blocks", count)
+	return nil
+}
+
 // ImportPreimages imports a batch of exported hash preimages into the database.
 // It's a part of the deprecated functionality, should be removed in the future.
 func ImportPreimages(db ethdb.Database, fn string) error {
@@ -39,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/fdlimit"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/footprint"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -49,6 +50,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/eth/gasometer"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -69,6 +71,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/netutil"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/tracing"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/trie/triedb/hashdb"
 	"github.com/ethereum/go-ethereum/trie/triedb/pathdb"
@@ -181,6 +184,11 @@ var (
 		Value:    11500000,
 		Category: flags.DevCategory,
 	}
+	DeveloperRomeFlag = &cli.BoolFlag{
+		Name:     "dev.rome",
+		Usage:    "Fill the Rome gas and footprint vectors of developer blocks from local execution",
+		Category: flags.DevCategory,
+	}
 
 	IdentityFlag = &cli.StringFlag{
 		Name:     "identity",
@@ -228,8 +236,9 @@ var (
 		Value: 0,
 	}
 
-	defaultSyncMode = ethconfig.Defaults.SyncMode
-	SnapshotFlag    = &cli.BoolFlag{
+	defaultSyncMode        = ethconfig.Defaults.SyncMode
+	defaultFootprintPolicy = ethconfig.Defaults.RomeFootprintPolicy
+	SnapshotFlag           = &cli.BoolFlag{
 		Name:     "snapshot",
 		Usage:    `Enables snapshot-database mode (default = enable)`,
 		Value:    true,
@@ -299,6 +308,23 @@ var (
 		Value:    ethconfig.Defaults.StateHistory,
 		Category: flags.StateCategory,
 	}
+	StateHistoryArchiveFlag = &cli.BoolFlag{
+		Name:     "history.state.archive",
+		Usage:    "Serve historical state from the state history in path scheme, for at most --history.state.lookback blocks below the persisted state (lookups scan every state history after the requested block)",
+		Category: flags.StateCategory,
+	}
+	StateHistoryLookbackFlag = &cli.Uint64Flag{
+		Name:     "history.state.lookback",
+		Usage:    "Number of state histories searched by a historical state lookup in archive mode (default = 90,000 blocks, 0 = all retained)",
+		Value:    ethconfig.Defaults.StateHistoryLookback,
+		Category: flags.StateCategory,
+	}
+	StateHistoryReadLimitFlag = &cli.Uint64Flag{
+		Name:     "history.state.readlimit",
+		Usage:    "Number of state histories an RPC request may search in archive mode before failing (0 = unlimited)",
+		Value:    ethconfig.Defaults.StateHistoryReadLimit,
+		Category: flags.StateCategory,
+	}
 	TransactionHistoryFlag = &cli.Uint64Flag{
 		Name:     "history.transactions",
 		Usage:    "Number of recent blocks to maintain transactions index for (default = about one year, 0 = entire chain)",
@@ -375,6 +401,17 @@ var (
 		Value:    ethconfig.Defaults.TxPool.Lifetime,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolPrivateLifetimeFlag = &cli.Uint64Flag{
+		Name:     "txpool.privatelifetime",
+		Usage:    "Number of blocks privately submitted transactions are retained for before being dropped",
+		Value:    ethconfig.Defaults.TxPool.PrivateLifetime,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolPrivateContentFlag = &cli.BoolFlag{
+		Name:     "txpool.privatecontent",
+		Usage:    "Includes privately submitted transactions in the txpool content APIs",
+		Category: flags.TxPoolCategory,
+	}
 	// Blob transaction pool settings
 	BlobPoolDataDirFlag = &cli.StringFlag{
 		Name:     "blobpool.datadir",
@@ -493,6 +530,18 @@ var (
 		Value:    ethconfig.Defaults.Miner.NewPayloadTimeout,
 		Category: flags.MinerCategory,
 	}
+	MinerTxOrderingFlag = &cli.StringFlag{
+		Name:     "miner.txordering",
+		Usage:    "Policy ordering the pending transactions into blocks (price, fifo, fair)",
+		Value:    ethconfig.Defaults.Miner.TxOrdering,
+		Category: flags.MinerCategory,
+	}
+	MinerTxOrderingSenderLimitFlag = &cli.IntFlag{
+		Name:     "miner.txordering.senderlimit",
+		Usage:    "Maximum number of transactions included per sender in a block with the fair ordering",
+		Value:    ethconfig.Defaults.Miner.TxOrderingSenderLimit,
+		Category: flags.MinerCategory,
+	}
 
 	// Account settings
 	UnlockedAccountFlag = &cli.StringFlag{
@@ -857,6 +906,31 @@ var (
 		Category: flags.RollupCategory,
 	}
 
+	RollupSequencerTimeoutFlag = &cli.DurationFlag{
+		Name:     "rollup.sequencertimeout",
+		Usage:    "Timeout for forwarding a transaction to the sequencer",
+		Value:    ethconfig.Defaults.RollupForwarding.Timeout,
+		Category: flags.RollupCategory,
+	}
+	RollupSequencerRetriesFlag = &cli.IntFlag{
+		Name:     "rollup.sequencerretries",
+		Usage:    "Number of retries of a transaction the sequencer could not be reached for",
+		Value:    ethconfig.Defaults.RollupForwarding.Retries,
+		Category: flags.RollupCategory,
+	}
+	RollupSequencerRetryIntervalFlag = &cli.DurationFlag{
+		Name:     "rollup.sequencerretryinterval",
+		Usage:    "Delay between the retries of forwarding a transaction to the sequencer",
+		Value:    ethconfig.Defaults.RollupForwarding.RetryInterval,
+		Category: flags.RollupCategory,
+	}
+	RollupSequencerQueueSizeFlag = &cli.IntFlag{
+		Name:     "rollup.sequencerqueuesize",
+		Usage:    "Maximum number of transactions queued for retrying the forwarding to the sequencer",
+		Value:    ethconfig.Defaults.RollupForwarding.QueueSize,
+		Category: flags.RollupCategory,
+	}
+
 	RollupHistoricalRPCFlag = &cli.StringFlag{
 		Name:     "rollup.historicalrpc",
 		Usage:    "RPC endpoint for historical data.",
@@ -880,6 +954,17 @@ var (
 		Usage:    "Add RPC-submitted transactions to the txpool (on by default if --rollup.sequencerhttp is not set).",
 		Category: flags.RollupCategory,
 	}
+	RollupSequencerTxConditionalEnabledFlag = &cli.BoolFlag{
+		Name:     "rollup.sequencertxconditionalenabled",
+		Usage:    "Serve the eth_sendRawTransactionConditional endpoint and apply the conditional constraints to transactions",
+		Category: flags.RollupCategory,
+	}
+	RollupSequencerTxConditionalCostRateLimitFlag = &cli.IntFlag{
+		Name:     "rollup.sequencertxconditionalcostratelimit",
+		Usage:    "Maximum number of conditional checks per second accepted through eth_sendRawTransactionConditional",
+		Value:    ethconfig.Defaults.RollupSequencerTxConditionalCostRateLimit,
+		Category: flags.RollupCategory,
+	}
 	RollupComputePendingBlock = &cli.BoolFlag{
 		Name:     "rollup.computependingblock",
 		Usage:    "By default the pending block equals the latest block to save resources and not leak txs from the tx-pool, this flag enables computing of the pending block from the tx-pool instead.",
@@ -897,6 +982,46 @@ var (
 		Category: flags.RollupCategory,
 		Value:    true,
 	}
+	RomeGasometerFlag = &cli.StringFlag{
+		Name:     "rome.gasometer",
+		Usage:    "RPC endpoint of the Rome gasometer (defaults to $ROME_GASOMETER_URL)",
+		Category: flags.RollupCategory,
+	}
+	RomeGasometerTimeoutFlag = &cli.DurationFlag{
+		Name:     "rome.gasometer.timeout",
+		Usage:    "Timeout of a single Rome gasometer request",
+		Value:    ethconfig.Defaults.Gasometer.Timeout,
+		Category: flags.RollupCategory,
+	}
+	RomeGasometerRetriesFlag = &cli.IntFlag{
+		Name:     "rome.gasometer.retries",
+		Usage:    "Number of retries of a Rome gasometer request failing to reach the gasometer",
+		Value:    ethconfig.Defaults.Gasometer.Retries,
+		Category: flags.RollupCategory,
+	}
+	RomeGasometerLocalFlag = &cli.BoolFlag{
+		Name:     "rome.gasometer.local",
+		Usage:    "Serve gasometer requests from local EVM execution instead of the Rome gasometer (default in developer mode)",
+		Category: flags.RollupCategory,
+	}
+	RomeGasometerFallbackFlag = &cli.BoolFlag{
+		Name:     "rome.gasometer.fallback",
+		Usage:    "Fall back to local gas pricing and estimation while the Rome gasometer is unavailable",
+		Value:    ethconfig.Defaults.Gasometer.Fallback,
+		Category: flags.RollupCategory,
+	}
+	RomeGasometerSkipEmulationFlag = &cli.BoolFlag{
+		Name:     "rome.gasometer.skipemulation",
+		Usage:    "Submit transactions without emulating them while the Rome gasometer is unavailable",
+		Value:    ethconfig.Defaults.Gasometer.SkipEmulation,
+		Category: flags.RollupCategory,
+	}
+	RomeFootprintPolicyFlag = &flags.TextMarshalerFlag{
+		Name:     "rome.footprint.policy",
+		Usage:    `Enforcement policy for state footprint mismatches ("log", "reject", "halt" or "alert")`,
+		Value:    &defaultFootprintPolicy,
+		Category: flags.RollupCategory,
+	}
 
 	// Metrics flags
 	MetricsEnabledFlag = &cli.BoolFlag{
@@ -992,6 +1117,46 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Value:    metrics.DefaultConfig.InfluxDBOrganization,
 		Category: flags.MetricsCategory,
 	}
+
+	// Tracing flags
+	TracingEnabledFlag = &cli.BoolFlag{
+		Name:     "tracing",
+		Usage:    "Enable the export of OpenTelemetry traces",
+		Category: flags.MetricsCategory,
+	}
+	TracingEndpointFlag = &cli.StringFlag{
+		Name:     "tracing.endpoint",
+		Usage:    "OTLP gRPC collector address to export traces to",
+		Value:    tracing.DefaultConfig.Endpoint,
+		Category: flags.MetricsCategory,
+	}
+	TracingServiceNameFlag = &cli.StringFlag{
+		Name:     "tracing.servicename",
+		Usage:    "Service name to report the traces under",
+		Value:    tracing.DefaultConfig.ServiceName,
+		Category: flags.MetricsCategory,
+	}
+	TracingSampleRatioFlag = &cli.Float64Flag{
+		Name:     "tracing.sampleratio",
+		Usage:    "Fraction of traces to sample, unless sampled by the caller already (0-1)",
+		Value:    tracing.DefaultConfig.SampleRatio,
+		Category: flags.MetricsCategory,
+	}
+	TracingInsecureFlag = &cli.BoolFlag{
+		Name:     "tracing.insecure",
+		Usage:    "Disable TLS towards the trace collector",
+		Category: flags.MetricsCategory,
+	}
+	TracingCACertFlag = &cli.StringFlag{
+		Name:     "tracing.tls.cacert",
+		Usage:    "CA certificate to verify the trace collector with (system roots if empty)",
+		Category: flags.MetricsCategory,
+	}
+	TracingHeadersFlag = &cli.StringFlag{
+		Name:     "tracing.headers",
+		Usage:    "Comma-separated headers sent along each trace export (e.g. \"authorization=Bearer token\")",
+		Category: flags.MetricsCategory,
+	}
 )
 
 var (
@@ -1558,6 +1723,32 @@ func setGPO(ctx *cli.Context, cfg *gasprice.Config) {
 	}
 }
 
+func setGasometer(ctx *cli.Context, cfg *gasometer.Config) {
+	if ctx.IsSet(RomeGasometerFlag.Name) {
+		cfg.URL = ctx.String(RomeGasometerFlag.Name)
+	} else if url := os.Getenv("ROME_GASOMETER_URL"); url != "" && cfg.URL == "" {
+		cfg.URL = url
+	}
+	if ctx.IsSet(RomeGasometerLocalFlag.Name) {
+		cfg.Local = ctx.Bool(RomeGasometerLocalFlag.Name)
+	} else if ctx.Bool(DeveloperFlag.Name) && cfg.URL == "" {
+		// Developer chains run without the Rome services
+		cfg.Local = true
+	}
+	if ctx.IsSet(RomeGasometerTimeoutFlag.Name) {
+		cfg.Timeout = ctx.Duration(RomeGasometerTimeoutFlag.Name)
+	}
+	if ctx.IsSet(RomeGasometerRetriesFlag.Name) {
+		cfg.Retries = ctx.Int(RomeGasometerRetriesFlag.Name)
+	}
+	if ctx.IsSet(RomeGasometerFallbackFlag.Name) {
+		cfg.Fallback = ctx.Bool(RomeGasometerFallbackFlag.Name)
+	}
+	if ctx.IsSet(RomeGasometerSkipEmulationFlag.Name) {
+		cfg.SkipEmulation = ctx.Bool(RomeGasometerSkipEmulationFlag.Name)
+	}
+}
+
 func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolLocalsFlag.Name) {
 		locals := strings.Split(ctx.String(TxPoolLocalsFlag.Name), ",")
@@ -1602,6 +1793,12 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.Duration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.IsSet(TxPoolPrivateLifetimeFlag.Name) {
+		cfg.PrivateLifetime = ctx.Uint64(TxPoolPrivateLifetimeFlag.Name)
+	}
+	if ctx.IsSet(TxPoolPrivateContentFlag.Name) {
+		cfg.PrivateContent = ctx.Bool(TxPoolPrivateContentFlag.Name)
+	}
 }
 
 func setMiner(ctx *cli.Context, cfg *miner.Config) {
@@ -1623,6 +1820,15 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.IsSet(RollupComputePendingBlock.Name) {
 		cfg.RollupComputePendingBlock = ctx.Bool(RollupComputePendingBlock.Name)
 	}
+	if ctx.IsSet(MinerTxOrderingFlag.Name) {
+		cfg.TxOrdering = ctx.String(MinerTxOrderingFlag.Name)
+	}
+	if ctx.IsSet(MinerTxOrderingSenderLimitFlag.Name) {
+		cfg.TxOrderingSenderLimit = ctx.Int(MinerTxOrderingSenderLimitFlag.Name)
+	}
+	if _, err := miner.NewTxOrderingPolicy(cfg.TxOrdering, cfg.TxOrderingSenderLimit); err != nil {
+		Fatalf("Invalid transaction ordering: %v", err)
+	}
 }
 
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1703,6 +1909,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	// Set configurations from CLI flags
 	setEtherbase(ctx, cfg)
 	setGPO(ctx, &cfg.GPO)
+	setGasometer(ctx, &cfg.Gasometer)
 	setTxPool(ctx, &cfg.TxPool)
 	setMiner(ctx, &cfg.Miner)
 	setRequiredBlocks(ctx, cfg)
@@ -1762,6 +1969,15 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(StateHistoryFlag.Name) {
 		cfg.StateHistory = ctx.Uint64(StateHistoryFlag.Name)
 	}
+	if ctx.IsSet(StateHistoryArchiveFlag.Name) {
+		cfg.StateHistoryArchive = ctx.Bool(StateHistoryArchiveFlag.Name)
+	}
+	if ctx.IsSet(StateHistoryLookbackFlag.Name) {
+		cfg.StateHistoryLookback = ctx.Uint64(StateHistoryLookbackFlag.Name)
+	}
+	if ctx.IsSet(StateHistoryReadLimitFlag.Name) {
+		cfg.StateHistoryReadLimit = ctx.Uint64(StateHistoryReadLimitFlag.Name)
+	}
 	if ctx.IsSet(StateSchemeFlag.Name) {
 		cfg.StateScheme = ctx.String(StateSchemeFlag.Name)
 	}
@@ -1847,13 +2063,35 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(RollupHistoricalRPCFlag.Name) {
 		cfg.RollupHistoricalRPC = ctx.String(RollupHistoricalRPCFlag.Name)
 	}
+	if ctx.IsSet(RollupSequencerTimeoutFlag.Name) {
+		cfg.RollupForwarding.Timeout = ctx.Duration(RollupSequencerTimeoutFlag.Name)
+	}
+	if ctx.IsSet(RollupSequencerRetriesFlag.Name) {
+		cfg.RollupForwarding.Retries = ctx.Int(RollupSequencerRetriesFlag.Name)
+	}
+	if ctx.IsSet(RollupSequencerRetryIntervalFlag.Name) {
+		cfg.RollupForwarding.RetryInterval = ctx.Duration(RollupSequencerRetryIntervalFlag.Name)
+	}
+	if ctx.IsSet(RollupSequencerQueueSizeFlag.Name) {
+		cfg.RollupForwarding.QueueSize = ctx.Int(RollupSequencerQueueSizeFlag.Name)
+	}
 	if ctx.IsSet(RollupHistoricalRPCTimeoutFlag.Name) {
 		cfg.RollupHistoricalRPCTimeout = ctx.Duration(RollupHistoricalRPCTimeoutFlag.Name)
 	}
 	cfg.RollupDisableTxPoolGossip = ctx.Bool(RollupDisableTxPoolGossipFlag.Name)
 	cfg.RollupDisableTxPoolAdmission = cfg.RollupSequencerHTTP != "" && !ctx.Bool(RollupEnableTxPoolAdmissionFlag.Name)
 	cfg.RollupHaltOnIncompatibleProtocolVersion = ctx.String(RollupHaltOnIncompatibleProtocolVersionFlag.Name)
+	cfg.RollupSequencerTxConditionalEnabled = ctx.Bool(RollupSequencerTxConditionalEnabledFlag.Name)
+	if ctx.IsSet(RollupSequencerTxConditionalCostRateLimitFlag.Name) {
+		cfg.RollupSequencerTxConditionalCostRateLimit = ctx.Int(RollupSequencerTxConditionalCostRateLimitFlag.Name)
+	}
 	cfg.ApplySuperchainUpgrades = ctx.Bool(RollupSuperchainUpgradesFlag.Name)
+	if ctx.IsSet(RomeFootprintPolicyFlag.Name) {
+		cfg.RomeFootprintPolicy = *flags.GlobalTextMarshaler(ctx, RomeFootprintPolicyFlag.Name).(*footprint.Policy)
+	} else if os.Getenv("GETH_FOOTPRINT_PANIC") == "true" {
+		log.Warn("The GETH_FOOTPRINT_PANIC environment variable is deprecated, use --rome.footprint.policy=halt instead")
+		cfg.RomeFootprintPolicy = footprint.PolicyHalt
+	}
 	// Override any default configs for hard coded networks.
 	switch {
 	case ctx.Bool(MainnetFlag.Name):
@@ -2015,6 +2253,14 @@ func RegisterGraphQLService(stack *node.Node, backend ethapi.Backend, filterSyst
 	}
 }
 
+// RegisterTracingService configures the export of OpenTelemetry traces and
+// adds it to the given node.
+func RegisterTracingService(stack *node.Node, cfg *tracing.Config) {
+	if _, err := tracing.New(stack, cfg); err != nil {
+		Fatalf("Failed to register the trace export service: %v", err)
+	}
+}
+
 // RegisterFilterAPI adds the eth log filtering RPC API to the node.
 func RegisterFilterAPI(stack *node.Node, backend ethapi.Backend, ethcfg *ethconfig.Config) *filters.FilterSystem {
 	filterSystem := filters.NewFilterSystem(backend, filters.Config{
@@ -2242,6 +2488,7 @@ func MakeChain(ctx *cli.Context, stack *node.Node, readonly bool) (*core.BlockCh
 		Preimages:           ctx.Bool(CachePreimagesFlag.Name),
 		StateScheme:         scheme,
 		StateHistory:        ctx.Uint64(StateHistoryFlag.Name),
+		HistoryLookback:     ctx.Uint64(StateHistoryLookbackFlag.Name),
 	}
 	if cache.TrieDirtyDisabled && !cache.Preimages {
 		cache.Preimages = true
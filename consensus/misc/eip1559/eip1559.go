@@ -96,3 +96,13 @@ func CalcBaseFee(config *params.ChainConfig, parent *types.Header, time uint64)
 		return math.BigMax(baseFee, common.Big0)
 	}
 }
+
+// RomeBaseFee returns the base fee of a Rome block with the given number to build.
+// Transactions on Rome pay the gas price of their Rome gas vector in full, so
+// London blocks carry a zero base fee instead of the EIP-1559 one.
+func RomeBaseFee(config *params.ChainConfig, number *big.Int) *big.Int {
+	if !config.IsLondon(number) {
+		return nil
+	}
+	return new(big.Int)
+}
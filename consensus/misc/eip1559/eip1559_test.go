@@ -175,3 +175,16 @@ func TestCalcBaseFeeOptimism(t *testing.T) {
 		}
 	}
 }
+
+// TestRomeBaseFee assumes Rome blocks carry a zero base fee from London on.
+func TestRomeBaseFee(t *testing.T) {
+	config := copyConfig(params.TestChainConfig)
+	config.LondonBlock = big.NewInt(2)
+
+	if fee := RomeBaseFee(config, big.NewInt(1)); fee != nil {
+		t.Errorf("pre-London base fee mismatch: have %v, want nil", fee)
+	}
+	if fee := RomeBaseFee(config, big.NewInt(2)); fee == nil || fee.Sign() != 0 {
+		t.Errorf("London base fee mismatch: have %v, want 0", fee)
+	}
+}
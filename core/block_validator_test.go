@@ -17,6 +17,7 @@
 package core
 
 import (
+	"context"
 	"math/big"
 	"testing"
 	"time"
@@ -209,7 +210,7 @@ func testHeaderVerificationForMerging(t *testing.T, isClique bool) {
 			t.Fatalf("post-block %d: unexpected result returned: %v", i, result)
 		case <-time.After(25 * time.Millisecond):
 		}
-		chain.InsertBlockWithoutSetHead(postBlocks[i], make([]uint64, 0), make([]string, 0), make([]uint64, 0))
+		chain.InsertBlockWithoutSetHead(context.Background(), postBlocks[i], make([]uint64, 0), make([]string, 0), make([]uint64, 0), false)
 	}
 
 	// Verify the blocks with pre-merge blocks and post-merge blocks
@@ -18,6 +18,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -102,6 +103,7 @@ const (
 	blockCacheLimit     = 256
 	receiptsCacheLimit  = 32
 	txLookupCacheLimit  = 1024
+	solanaCtxCacheLimit = 256
 	maxFutureBlocks     = 256
 	maxTimeFutureBlocks = 30
 	TriesInMemory       = 128
@@ -143,6 +145,7 @@ type CacheConfig struct {
 	SnapshotLimit       int           // Memory allowance (MB) to use for caching snapshot entries in memory
 	Preimages           bool          // Whether to store preimage of trie key to the disk
 	StateHistory        uint64        // Number of blocks from head whose state histories are reserved.
+	HistoryLookback     uint64        // Number of state histories searched to serve historical states (0 = all)
 	StateScheme         string        // Scheme used to store ethereum states and merkle tree nodes on top
 
 	SnapshotNoBuild bool // Whether the background generation is allowed
@@ -159,9 +162,10 @@ func (c *CacheConfig) triedbConfig() *trie.Config {
 	}
 	if c.StateScheme == rawdb.PathScheme {
 		config.PathDB = &pathdb.Config{
-			StateHistory:   c.StateHistory,
-			CleanCacheSize: c.TrieCleanLimit * 1024 * 1024,
-			DirtyCacheSize: c.TrieDirtyLimit * 1024 * 1024,
+			StateHistory:    c.StateHistory,
+			HistoryLookback: c.HistoryLookback,
+			CleanCacheSize:  c.TrieCleanLimit * 1024 * 1024,
+			DirtyCacheSize:  c.TrieDirtyLimit * 1024 * 1024,
 		}
 	}
 	return config
@@ -244,6 +248,7 @@ type BlockChain struct {
 	receiptsCache *lru.Cache[common.Hash, []*types.Receipt]
 	blockCache    *lru.Cache[common.Hash, *types.Block]
 	txLookupCache *lru.Cache[common.Hash, *rawdb.LegacyTxLookupEntry]
+	solanaCtxs    *lru.Cache[common.Hash, *SolanaTxContext]
 
 	// future blocks are blocks added for later processing
 	futureBlocks *lru.Cache[common.Hash, *types.Block]
@@ -261,6 +266,7 @@ type BlockChain struct {
 	vmConfig   vm.Config
 
 	footprintManager *footprint.Manager // Manages footprint caching and mismatch tracking
+	romeStage        *romeStage         // Rome inputs retrieved from the network for blocks yet to be imported
 }
 
 // NewBlockChain returns a fully initialised block chain using information
@@ -291,6 +297,13 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 	if chainConfig.IsOptimism() && chainConfig.RegolithTime == nil {
 		log.Warn("Optimism RegolithTime has not been set")
 	}
+	if chainConfig.Rome != nil {
+		for _, fork := range chainConfig.Rome.FootprintForks {
+			if state.NewFootprintHasher(fork.Version) == nil {
+				return nil, fmt.Errorf("unsupported footprint version %d scheduled at timestamp %d", fork.Version, fork.Time)
+			}
+		}
+	}
 
 	bc := &BlockChain{
 		chainConfig:   chainConfig,
@@ -305,9 +318,11 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 		receiptsCache: lru.NewCache[common.Hash, []*types.Receipt](receiptsCacheLimit),
 		blockCache:    lru.NewCache[common.Hash, *types.Block](blockCacheLimit),
 		txLookupCache: lru.NewCache[common.Hash, *rawdb.LegacyTxLookupEntry](txLookupCacheLimit),
+		solanaCtxs:    lru.NewCache[common.Hash, *SolanaTxContext](solanaCtxCacheLimit),
 		futureBlocks:  lru.NewCache[common.Hash, *types.Block](maxFutureBlocks),
 		engine:        engine,
 		vmConfig:      vmConfig,
+		romeStage:     newRomeStage(),
 	}
 	bc.flushInterval.Store(int64(cacheConfig.TrieTimeLimit))
 	bc.forker = NewForkChoice(bc, shouldPreserve)
@@ -761,6 +776,15 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Ha
 	}
 	// Rewind the header chain, deleting all block bodies until then
 	delFn := func(db ethdb.KeyValueWriter, hash common.Hash, num uint64) {
+		// Unindex the Solana slots of the rewound transactions before the body
+		// is gone, the index only covers the canonical chain
+		if body := rawdb.ReadBody(bc.db, hash, num); body != nil {
+			for _, tx := range body.Transactions {
+				if slot, _, ok := rawdb.ReadSolanaTxMetadata(bc.db, tx.Hash()); ok {
+					rawdb.DeleteSolanaSlotIndex(db, slot, tx.Hash())
+				}
+			}
+		}
 		// Ignore the error here since light client won't hit this path
 		frozen, _ := bc.db.Ancients()
 		if num+1 <= frozen {
@@ -778,6 +802,9 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Ha
 			rawdb.DeleteBody(db, hash, num)
 			rawdb.DeleteReceipts(db, hash, num)
 		}
+		// Rome execution inputs live in the active store even for frozen blocks
+		rawdb.DeleteRomeBlockData(db, hash, num)
+		rawdb.DeleteSolanaBlockContext(db, hash, num)
 		// Todo(rjl493456442) txlookup, bloombits, etc
 	}
 	// If SetHead was only called as a chain reparation method, try to skip
@@ -926,6 +953,36 @@ func (bc *BlockChain) ExportN(w io.Writer, first uint64, last uint64) error {
 	return nil
 }
 
+// RomeExportEntry is the export format of the Rome execution inputs of a
+// single block.
+type RomeExportEntry struct {
+	Hash   common.Hash
+	Number uint64
+	Data   *types.RomeBlockData
+}
+
+// ExportRomeN writes the Rome execution inputs of a subset of the active chain
+// to the given writer. Blocks without stored Rome data are skipped.
+func (bc *BlockChain) ExportRomeN(w io.Writer, first uint64, last uint64) error {
+	if first > last {
+		return fmt.Errorf("export failed: first (%d) is greater than last (%d)", first, last)
+	}
+	for nr := first; nr <= last; nr++ {
+		hash := rawdb.ReadCanonicalHash(bc.db, nr)
+		if hash == (common.Hash{}) {
+			return fmt.Errorf("export failed on #%d: not found", nr)
+		}
+		data := rawdb.ReadRomeBlockData(bc.db, hash, nr)
+		if data == nil {
+			continue
+		}
+		if err := rlp.Encode(w, &RomeExportEntry{Hash: hash, Number: nr, Data: data}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // writeHeadBlock injects a new head block into the current block chain. This method
 // assumes that the block is indeed a true head. It will also reset the head
 // header and the head snap sync block to this very same block if they are older
@@ -939,6 +996,7 @@ func (bc *BlockChain) writeHeadBlock(block *types.Block) {
 	rawdb.WriteHeadFastBlockHash(batch, block.Hash())
 	rawdb.WriteCanonicalHash(batch, block.Hash(), block.NumberU64())
 	rawdb.WriteTxLookupEntriesByBlock(batch, block)
+	bc.writeSolanaSlotIndexes(batch, block)
 	rawdb.WriteHeadBlockHash(batch, block.Hash())
 
 	// Flush the whole batch into the disk, exit the node if failed
@@ -995,9 +1053,164 @@ func (bc *BlockChain) WriteSolanaTxMetadata(txHash common.Hash, slot uint64, tim
 	return batch.Write()
 }
 
-// GetSolanaTxMetadata retrieves the solana slot and timestamp associated with a transaction hash.
+// GetSolanaTxMetadata retrieves the solana slot and timestamp associated with a
+// transaction hash, falling back to the ones staged for blocks being imported.
 func (bc *BlockChain) GetSolanaTxMetadata(txHash common.Hash) (uint64, int64, bool) {
-	return rawdb.ReadSolanaTxMetadata(bc.db, txHash)
+	if slot, timestamp, ok := rawdb.ReadSolanaTxMetadata(bc.db, txHash); ok {
+		return slot, timestamp, true
+	}
+	if context, ok := bc.romeStage.tx(txHash); ok {
+		return context.Slot, context.Timestamp, true
+	}
+	return 0, 0, false
+}
+
+// writeSolanaSlotIndexes indexes the transactions of a block with known Solana
+// metadata by the slot they were executed in. Only canonical blocks are indexed.
+func (bc *BlockChain) writeSolanaSlotIndexes(db ethdb.KeyValueWriter, block *types.Block) {
+	for i, tx := range block.Transactions() {
+		slot, _, ok := bc.GetSolanaTxMetadata(tx.Hash())
+		if !ok {
+			continue
+		}
+		rawdb.WriteSolanaSlotIndex(db, &rawdb.SolanaSlotEntry{
+			Slot:        slot,
+			TxHash:      tx.Hash(),
+			BlockNumber: block.NumberU64(),
+			BlockHash:   block.Hash(),
+			TxIndex:     uint64(i),
+		})
+	}
+}
+
+// writeSolanaBlockContext records the latest Solana slot and timestamp known at
+// a block, so that calls simulated on top of it resolve them without searching
+// its ancestors. Blocks without Rome transactions inherit them from the parent.
+func (bc *BlockChain) writeSolanaBlockContext(db ethdb.KeyValueWriter, block *types.Block) {
+	var context *SolanaTxContext
+	for _, tx := range block.Transactions() {
+		slot, timestamp, ok := bc.GetSolanaTxMetadata(tx.Hash())
+		if !ok {
+			continue
+		}
+		if context == nil {
+			context = &SolanaTxContext{Slot: slot, Timestamp: timestamp}
+			continue
+		}
+		context.Slot = max(context.Slot, slot)
+		context.Timestamp = max(context.Timestamp, timestamp)
+	}
+	if context == nil && block.NumberU64() > 0 {
+		if parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1); parent != nil {
+			context = bc.solanaBlockContext(parent)
+		}
+	}
+	bc.solanaCtxs.Add(block.Hash(), context)
+	if context != nil {
+		rawdb.WriteSolanaBlockContext(db, block.Hash(), block.NumberU64(), context.Slot, context.Timestamp)
+	}
+}
+
+// solanaBlockContext retrieves the latest Solana slot and timestamp known at a
+// written block, or nil if there are none.
+func (bc *BlockChain) solanaBlockContext(header *types.Header) *SolanaTxContext {
+	hash := header.Hash()
+	if context, ok := bc.solanaCtxs.Get(hash); ok {
+		return context
+	}
+	var context *SolanaTxContext
+	if slot, timestamp, ok := rawdb.ReadSolanaBlockContext(bc.db, hash, header.Number.Uint64()); ok {
+		context = &SolanaTxContext{Slot: slot, Timestamp: timestamp}
+	} else {
+		// Blocks written before the context was recorded per block, or having
+		// none at all, are resolved by searching their ancestors once
+		var txContext vm.TxContext
+		scanSolanaBlockContext(bc.db, header, &txContext)
+		if txContext.SolanaBlockNumber != nil {
+			context = &SolanaTxContext{Slot: *txContext.SolanaBlockNumber, Timestamp: *txContext.SolanaTimestamp}
+		}
+	}
+	bc.solanaCtxs.Add(hash, context)
+	return context
+}
+
+// GetSolanaBlockhash retrieves the blockhash of a Solana slot delivered by the
+// engine API or staged for a block being imported, or the zero hash if it is
+// not known.
+func (bc *BlockChain) GetSolanaBlockhash(slot uint64) common.Hash {
+	if hash := rawdb.ReadSolanaBlockhash(bc.db, slot); hash != (common.Hash{}) {
+		return hash
+	}
+	hash, _ := bc.romeStage.blockhash(slot)
+	return hash
+}
+
+// WriteSolanaBlockhashes stores the blockhashes of Solana slots, making them
+// available to the BLOCKHASH opcode. A blockhash differing from the one already
+// stored for a slot is rejected, leaving all of them unwritten.
+func (bc *BlockChain) WriteSolanaBlockhashes(hashes map[uint64]common.Hash) error {
+	batch := bc.db.NewBatch()
+	for slot, hash := range hashes {
+		if known := rawdb.ReadSolanaBlockhash(bc.db, slot); known != (common.Hash{}) {
+			if known != hash {
+				return fmt.Errorf("conflicting blockhash for solana slot %d: have %x, known %x", slot, hash, known)
+			}
+			continue
+		}
+		rawdb.WriteSolanaBlockhash(batch, slot, hash)
+	}
+	return batch.Write()
+}
+
+// GetRomeBlockData retrieves the Rome gas vectors and footprints a block was
+// originally executed with, or nil if they are not known.
+func (bc *BlockChain) GetRomeBlockData(hash common.Hash, number uint64) *types.RomeBlockData {
+	if data := rawdb.ReadRomeBlockData(bc.db, hash, number); data != nil {
+		return data
+	}
+	return bc.romeStage.data(hash)
+}
+
+// WriteRomeBlockData stores the Rome gas vectors and footprints of a block, so
+// that it can be re-executed without the original engine API payload.
+func (bc *BlockChain) WriteRomeBlockData(hash common.Hash, number uint64, data *types.RomeBlockData) {
+	rawdb.WriteRomeBlockData(bc.db, hash, number, data)
+}
+
+// StageRomeInputs makes the Rome inputs of a block retrieved from the network
+// available to its execution. The inputs are persisted along with the block if
+// it imports, inputs contradicting the known Solana blockhashes are rejected.
+func (bc *BlockChain) StageRomeInputs(hash common.Hash, inputs *RomeInputs) error {
+	return bc.romeStage.add(bc.db, hash, inputs)
+}
+
+// UnstageRomeInputs discards the Rome inputs staged for a block, if they were
+// not yet persisted by importing it.
+func (bc *BlockChain) UnstageRomeInputs(hash common.Hash) {
+	bc.romeStage.remove(hash)
+}
+
+// writeRomeInputs persists the Rome inputs staged for a block being written into
+// the given batch. Solana context and blockhashes that are already known are
+// left untouched.
+func (bc *BlockChain) writeRomeInputs(db ethdb.KeyValueWriter, block *types.Block) {
+	inputs := bc.romeStage.remove(block.Hash())
+	if inputs == nil {
+		return
+	}
+	if inputs.Data != nil {
+		rawdb.WriteRomeBlockData(db, block.Hash(), block.NumberU64(), inputs.Data)
+	}
+	for tx, context := range inputs.SolanaTxs {
+		if _, _, ok := rawdb.ReadSolanaTxMetadata(bc.db, tx); !ok {
+			rawdb.WriteSolanaTxMetadata(db, tx, context.Slot, context.Timestamp)
+		}
+	}
+	for slot, hash := range inputs.Blockhashes {
+		if rawdb.ReadSolanaBlockhash(bc.db, slot) == (common.Hash{}) {
+			rawdb.WriteSolanaBlockhash(db, slot, hash)
+		}
+	}
 }
 
 // SetFootprintManager sets the footprint manager for this blockchain
@@ -1229,6 +1442,8 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 			} else if rawdb.ReadTxIndexTail(bc.db) != nil {
 				rawdb.WriteTxLookupEntriesByBlock(batch, block)
 			}
+			bc.writeSolanaBlockContext(batch, block)
+			bc.writeRomeInputs(batch, block)
 			stats.processed++
 
 			if batch.ValueSize() > ethdb.IdealBatchSize || i == len(blockChain)-1 {
@@ -1269,6 +1484,8 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 			}
 			rawdb.DeleteCanonicalHash(batch, block.NumberU64())
 			rawdb.DeleteBlockWithoutNumber(batch, block.Hash(), block.NumberU64())
+			rawdb.DeleteRomeBlockData(batch, block.Hash(), block.NumberU64())
+			rawdb.DeleteSolanaBlockContext(batch, block.Hash(), block.NumberU64())
 		}
 		// Delete side chain hash-to-number mappings.
 		for _, nh := range rawdb.ReadAllHashesInRange(bc.db, first.NumberU64(), last.NumberU64()) {
@@ -1311,6 +1528,8 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 			rawdb.WriteBody(batch, block.Hash(), block.NumberU64(), block.Body())
 			rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), receiptChain[i])
 			rawdb.WriteTxLookupEntriesByBlock(batch, block) // Always write tx indices for live blocks, we assume they are needed
+			bc.writeSolanaBlockContext(batch, block)
+			bc.writeRomeInputs(batch, block)
 
 			// Write everything belongs to the blocks into the database. So that
 			// we can ensure all components of body is completed(body, receipts,
@@ -1432,6 +1651,8 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	rawdb.WriteBlock(blockBatch, block)
 	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
 	rawdb.WritePreimages(blockBatch, state.Preimages())
+	bc.writeSolanaBlockContext(blockBatch, block)
+	bc.writeRomeInputs(blockBatch, block)
 	if err := blockBatch.Write(); err != nil {
 		log.Crit("Failed to write block into disk", "err", err)
 	}
@@ -1609,7 +1830,7 @@ func (bc *BlockChain) InsertChain(chain types.Blocks) (int, error) {
 		return 0, errChainStopped
 	}
 	defer bc.chainmu.Unlock()
-	return bc.insertChain(chain, true, make([]uint64, 0), make([]string, 0), make([]uint64, 0))
+	return bc.insertChain(context.Background(), chain, true, make([]uint64, 0), make([]string, 0), make([]uint64, 0), false)
 }
 
 // insertChain is the internal implementation of InsertChain, which assumes that
@@ -1620,7 +1841,7 @@ func (bc *BlockChain) InsertChain(chain types.Blocks) (int, error) {
 // racey behaviour. If a sidechain import is in progress, and the historic state
 // is imported, but then new canon-head is added before the actual sidechain
 // completes, then the historic state could be pruned again
-func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool, romeGasUsed []uint64, footPrints []string, romeGasPrice []uint64) (int, error) {
+func (bc *BlockChain) insertChain(ctx context.Context, chain types.Blocks, setHead bool, romeGasUsed []uint64, footPrints []string, romeGasPrice []uint64, enforceFootprints bool) (int, error) {
 	// If the chain is terminating, don't even bother starting up.
 	if bc.insertStopped() {
 		return 0, nil
@@ -1836,7 +2057,9 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool, romeGasUsed
 
 		// Process block using the parent state as reference point
 		pstart := time.Now()
-		receipts, logs, usedGas, err := bc.processor.Process(block, statedb, bc.vmConfig, romeGasUsed, romeGasPrice, footPrints)
+		vmConfig := bc.vmConfig
+		vmConfig.EnforceFootprints = enforceFootprints
+		receipts, logs, usedGas, err := bc.processor.Process(ctx, block, statedb, vmConfig, romeGasUsed, romeGasPrice, footPrints)
 
 		if err != nil {
 			bc.reportBlock(block, receipts, err)
@@ -1870,6 +2093,11 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool, romeGasUsed
 		blockExecutionTimer.Update(ptime - trieRead)                    // The time spent on EVM processing
 		blockValidationTimer.Update(vtime - (triehash + trieUpdate))    // The time spent on block validation
 
+		// Persist the Rome execution inputs in the same batch as the block so that
+		// it can be re-executed later on (state regeneration, tracing, chain repair).
+		if data := types.NewRomeBlockData(romeGasUsed, romeGasPrice, footPrints); data != nil {
+			bc.romeStage.setData(block.Hash(), data)
+		}
 		// Write the block to the chain and get the status.
 		var (
 			wstart = time.Now()
@@ -1883,6 +2111,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool, romeGasUsed
 		}
 		followupInterrupt.Store(true)
 		if err != nil {
+			bc.romeStage.remove(block.Hash())
 			return it.index, err
 		}
 		// Update the metrics touched during block commit
@@ -2076,7 +2305,7 @@ func (bc *BlockChain) insertSideChain(block *types.Block, it *insertIterator) (i
 		// memory here.
 		if len(blocks) >= 2048 || memory > 64*1024*1024 {
 			log.Info("Importing heavy sidechain segment", "blocks", len(blocks), "start", blocks[0].NumberU64(), "end", block.NumberU64())
-			if _, err := bc.insertChain(blocks, true, make([]uint64, 0), make([]string, 0), make([]uint64, 0)); err != nil {
+			if _, err := bc.insertChain(context.Background(), blocks, true, make([]uint64, 0), make([]string, 0), make([]uint64, 0), false); err != nil {
 				return 0, err
 			}
 			blocks, memory = blocks[:0], 0
@@ -2090,7 +2319,7 @@ func (bc *BlockChain) insertSideChain(block *types.Block, it *insertIterator) (i
 	}
 	if len(blocks) > 0 {
 		log.Info("Importing sidechain segment", "start", blocks[0].NumberU64(), "end", blocks[len(blocks)-1].NumberU64())
-		return bc.insertChain(blocks, true, make([]uint64, 0), make([]string, 0), make([]uint64, 0))
+		return bc.insertChain(context.Background(), blocks, true, make([]uint64, 0), make([]string, 0), make([]uint64, 0), false)
 	}
 	return 0, nil
 }
@@ -2139,7 +2368,7 @@ func (bc *BlockChain) recoverAncestors(block *types.Block) (common.Hash, error)
 		} else {
 			b = bc.GetBlock(hashes[i], numbers[i])
 		}
-		if _, err := bc.insertChain(types.Blocks{b}, false, make([]uint64, 0), make([]string, 0), make([]uint64, 0)); err != nil {
+		if _, err := bc.insertChain(context.Background(), types.Blocks{b}, false, make([]uint64, 0), make([]string, 0), make([]uint64, 0), false); err != nil {
 			return b.ParentHash(), err
 		}
 	}
@@ -2277,6 +2506,9 @@ func (bc *BlockChain) reorg(oldHead *types.Header, newHead *types.Block) error {
 	indexesBatch := bc.db.NewBatch()
 	for _, tx := range types.HashDifference(deletedTxs, addedTxs) {
 		rawdb.DeleteTxLookupEntry(indexesBatch, tx)
+		if slot, _, ok := rawdb.ReadSolanaTxMetadata(bc.db, tx); ok {
+			rawdb.DeleteSolanaSlotIndex(indexesBatch, slot, tx)
+		}
 	}
 
 	// Delete all hash markers that are not part of the new canonical chain.
@@ -2341,14 +2573,16 @@ func (bc *BlockChain) reorg(oldHead *types.Header, newHead *types.Block) error {
 // upon it and then persist the block and the associate state into the database.
 // The key difference between the InsertChain is it won't do the canonical chain
 // updating. It relies on the additional SetCanonical call to finalize the entire
-// procedure.
-func (bc *BlockChain) InsertBlockWithoutSetHead(block *types.Block, gasUsed []uint64, footPrints []string, gasPrice []uint64) error {
+// procedure. The execution of the block is traced under the given context, the
+// footprint mismatch policy is applied to its transactions if enforceFootprints
+// is set.
+func (bc *BlockChain) InsertBlockWithoutSetHead(ctx context.Context, block *types.Block, gasUsed []uint64, footPrints []string, gasPrice []uint64, enforceFootprints bool) error {
 	if !bc.chainmu.TryLock() {
 		return errChainStopped
 	}
 	defer bc.chainmu.Unlock()
 
-	_, err := bc.insertChain(types.Blocks{block}, false, gasUsed, footPrints, gasPrice)
+	_, err := bc.insertChain(ctx, types.Blocks{block}, false, gasUsed, footPrints, gasPrice, enforceFootprints)
 	return err
 }
 
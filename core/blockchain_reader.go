@@ -337,6 +337,14 @@ func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
 	return state.New(root, bc.stateCache, bc.snaps)
 }
 
+// HistoricState returns a new mutable state based on a historical point in time,
+// which is no longer available in the live database, served from the retained
+// state histories. Reading it fails once more than limit state histories were
+// searched (0 = unlimited). It's only supported in the path-based scheme.
+func (bc *BlockChain) HistoricState(root common.Hash, limit uint64) (*state.StateDB, error) {
+	return state.New(root, state.NewHistoricDatabase(bc.stateCache, limit), nil)
+}
+
 // Config retrieves the chain's fork configuration.
 func (bc *BlockChain) Config() *params.ChainConfig { return bc.chainConfig }
 
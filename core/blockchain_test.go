@@ -17,6 +17,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -162,7 +163,7 @@ func testBlockChainImport(chain types.Blocks, blockchain *BlockChain) error {
 		if err != nil {
 			return err
 		}
-		receipts, _, usedGas, err := blockchain.processor.Process(block, statedb, vm.Config{}, make([]uint64, 0), make([]uint64, 0), make([]string, 0))
+		receipts, _, usedGas, err := blockchain.processor.Process(context.Background(), block, statedb, vm.Config{}, make([]uint64, 0), make([]uint64, 0), make([]string, 0))
 		if err != nil {
 			blockchain.reportBlock(block, receipts, err)
 			return err
@@ -3957,7 +3958,7 @@ func testSetCanonical(t *testing.T, scheme string) {
 		gen.AddTx(tx)
 	})
 	for _, block := range side {
-		err := chain.InsertBlockWithoutSetHead(block, make([]uint64, 0), make([]string, 0), make([]uint64, 0))
+		err := chain.InsertBlockWithoutSetHead(context.Background(), block, make([]uint64, 0), make([]string, 0), make([]uint64, 0), false)
 		if err != nil {
 			t.Fatalf("Failed to insert into chain: %v", err)
 		}
@@ -575,4 +575,8 @@ func (cm *chainMaker) GetTd(hash common.Hash, number uint64) *big.Int {
 
 func (cm *chainMaker) GetFootprintManager() *footprint.Manager {
 	return nil // not needed for chain generation
-}
\ No newline at end of file
+}
+
+func (cm *chainMaker) GetSolanaBlockhash(slot uint64) common.Hash {
+	return common.Hash{} // not supported
+}
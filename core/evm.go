@@ -24,9 +24,11 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core/footprint"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -42,6 +44,9 @@ type ChainContext interface {
 	// GetFootprintManager returns the footprint manager.
 	GetFootprintManager() *footprint.Manager
 
+	// GetSolanaBlockhash retrieves the blockhash of a Solana slot, or the zero
+	// hash if it is not known.
+	GetSolanaBlockhash(slot uint64) common.Hash
 }
 
 // NewEVMBlockContext creates a new context for use in the EVM.
@@ -69,18 +74,19 @@ func NewEVMBlockContext(header *types.Header, chain ChainContext, author *common
 		random = &header.MixDigest
 	}
 	return vm.BlockContext{
-		CanTransfer: CanTransfer,
-		Transfer:    Transfer,
-		GetHash:     GetHashFn(header, chain),
-		Coinbase:    beneficiary,
-		BlockNumber: new(big.Int).Set(header.Number),
-		Time:        header.Time,
-		Difficulty:  new(big.Int).Set(header.Difficulty),
-		BaseFee:     baseFee,
-		BlobBaseFee: blobBaseFee,
-		GasLimit:    header.GasLimit,
-		Random:      random,
-		L1CostFunc:  types.NewL1CostFunc(config, statedb),
+		CanTransfer:   CanTransfer,
+		Transfer:      Transfer,
+		GetHash:       GetHashFn(header, chain),
+		GetSolanaHash: chain.GetSolanaBlockhash,
+		Coinbase:      beneficiary,
+		BlockNumber:   new(big.Int).Set(header.Number),
+		Time:          header.Time,
+		Difficulty:    new(big.Int).Set(header.Difficulty),
+		BaseFee:       baseFee,
+		BlobBaseFee:   blobBaseFee,
+		GasLimit:      header.GasLimit,
+		Random:        random,
+		L1CostFunc:    types.NewL1CostFunc(config, statedb),
 	}
 }
 
@@ -98,6 +104,62 @@ func NewEVMTxContext(msg *Message) vm.TxContext {
 	return ctx
 }
 
+// solanaContextLookback is the number of blocks searched back for a transaction
+// with a known Solana context when importing a block whose parent was imported
+// before the context was recorded per block.
+const solanaContextLookback = 256
+
+// ReadSolanaTxContext fills the Solana slot and timestamp a mined transaction was
+// executed in into the transaction context, if they are known.
+func ReadSolanaTxContext(db ethdb.KeyValueReader, hash common.Hash, txContext *vm.TxContext) {
+	if slot, timestamp, ok := rawdb.ReadSolanaTxMetadata(db, hash); ok {
+		txContext.SolanaBlockNumber = &slot
+		txContext.SolanaTimestamp = &timestamp
+	}
+}
+
+// ReadSolanaBlockContext fills the latest Solana slot and timestamp known at the
+// given block into the transaction context, so that calls simulated on top of
+// the block observe the same NUMBER and TIMESTAMP as the chain did. The context
+// is recorded when the block is imported.
+func ReadSolanaBlockContext(db ethdb.KeyValueReader, header *types.Header, txContext *vm.TxContext) {
+	if slot, timestamp, ok := rawdb.ReadSolanaBlockContext(db, header.Hash(), header.Number.Uint64()); ok {
+		txContext.SolanaBlockNumber = &slot
+		txContext.SolanaTimestamp = &timestamp
+	}
+}
+
+// scanSolanaBlockContext resolves the latest Solana slot and timestamp known at
+// the given block from the transactions of the block and up to
+// solanaContextLookback ancestors.
+func scanSolanaBlockContext(db ethdb.Reader, header *types.Header, txContext *vm.TxContext) {
+	for i := 0; i < solanaContextLookback && header != nil; i++ {
+		var (
+			number = header.Number.Uint64()
+			found  bool
+		)
+		if body := rawdb.ReadBody(db, header.Hash(), number); body != nil {
+			for _, tx := range body.Transactions {
+				slot, timestamp, ok := rawdb.ReadSolanaTxMetadata(db, tx.Hash())
+				if !ok {
+					continue
+				}
+				if !found || slot > *txContext.SolanaBlockNumber {
+					txContext.SolanaBlockNumber = &slot
+				}
+				if !found || timestamp > *txContext.SolanaTimestamp {
+					txContext.SolanaTimestamp = &timestamp
+				}
+				found = true
+			}
+		}
+		if found || number == 0 {
+			return
+		}
+		header = rawdb.ReadHeader(db, header.ParentHash, number-1)
+	}
+}
+
 // GetHashFn returns a GetHashFunc which retrieves header hashes by number
 func GetHashFn(ref *types.Header, chain ChainContext) func(n uint64) common.Hash {
 	// Cache will initially contain [refHash.parent],
@@ -0,0 +1,156 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/binary"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that BLOCKHASH serves the Solana blockhashes delivered by the engine API
+// past the Rome blockhash fork, and the synthetic slot hash before it.
+func TestSolanaBlockhash(t *testing.T) {
+	var (
+		contract  = common.HexToAddress("0xb10c")
+		code      = common.FromHex("0x605f4060005500") // PUSH1 95 BLOCKHASH PUSH1 0 SSTORE STOP
+		blockhash = common.HexToHash("0x5ea1")
+		slot      = uint64(100)
+	)
+	var legacy [32]byte
+	binary.BigEndian.PutUint64(legacy[24:], 95)
+
+	for _, test := range []struct {
+		forkTime uint64
+		want     common.Hash
+	}{
+		{forkTime: 0, want: blockhash},
+		{forkTime: 1 << 40, want: crypto.Keccak256Hash(legacy[:])},
+	} {
+		config := *params.TestChainConfig
+		config.Rome = &params.RomeConfig{BlockhashTime: &test.forkTime}
+		var (
+			db    = rawdb.NewMemoryDatabase()
+			gspec = &Genesis{
+				Config: &config,
+				Alloc:  GenesisAlloc{contract: {Code: code, Balance: big.NewInt(0)}},
+			}
+		)
+		chain, err := NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+		if err != nil {
+			t.Fatalf("failed to create blockchain: %v", err)
+		}
+		if err := chain.WriteSolanaBlockhashes(map[uint64]common.Hash{95: blockhash}); err != nil {
+			t.Fatalf("failed to store Solana blockhashes: %v", err)
+		}
+		if err := chain.WriteSolanaBlockhashes(map[uint64]common.Hash{95: {0x01}}); err == nil {
+			t.Fatal("conflicting Solana blockhash stored")
+		}
+		statedb, err := chain.State()
+		if err != nil {
+			t.Fatalf("failed to get state: %v", err)
+		}
+		var (
+			header   = chain.CurrentBlock()
+			blockCtx = NewEVMBlockContext(header, chain, &common.Address{}, &config, statedb)
+			txCtx    = vm.TxContext{GasPrice: new(big.Int), SolanaBlockNumber: &slot}
+			evm      = vm.NewEVM(blockCtx, txCtx, statedb, &config, vm.Config{})
+		)
+		statedb.AddAddressToAccessList(contract)
+		if _, _, err := evm.Call(vm.AccountRef(common.Address{}), contract, nil, 100000, new(big.Int)); err != nil {
+			t.Fatalf("call failed: %v", err)
+		}
+		if have := statedb.GetState(contract, common.Hash{}); have != test.want {
+			t.Errorf("fork time %d: blockhash mismatch: have %x, want %x", test.forkTime, have, test.want)
+		}
+		chain.Stop()
+	}
+}
+
+// Tests that the Solana context of a block is recorded on import from its own or
+// its closest ancestor's transactions.
+func TestReadSolanaBlockContext(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.LatestSigner(params.TestChainConfig)
+		gspec  = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		}
+		db = rawdb.NewMemoryDatabase()
+	)
+	chain, err := NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	// Block 1 holds two Rome transactions, block 2 a transaction without a
+	// known Solana context and block 3 is empty.
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 3, func(i int, gen *BlockGen) {
+		txs := map[int]int{0: 2, 1: 1}[i]
+		for j := 0; j < txs; j++ {
+			tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr), common.Address{0x01}, big.NewInt(1), params.TxGas, gen.header.BaseFee, nil), signer, key)
+			gen.AddTxWithChain(chain, tx)
+		}
+	})
+	rawdb.WriteSolanaTxMetadata(db, blocks[0].Transactions()[0].Hash(), 120, 1700000002)
+	rawdb.WriteSolanaTxMetadata(db, blocks[0].Transactions()[1].Hash(), 110, 1700000001)
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	headers := []*types.Header{chain.Genesis().Header()}
+	for _, block := range blocks {
+		headers = append(headers, block.Header())
+	}
+	for i, test := range []struct {
+		slot      *uint64
+		timestamp *int64
+	}{
+		{nil, nil},
+		{newUint64(120), newInt64(1700000002)},
+		{newUint64(120), newInt64(1700000002)},
+		{newUint64(120), newInt64(1700000002)},
+	} {
+		var txContext vm.TxContext
+		ReadSolanaBlockContext(db, headers[i], &txContext)
+		if !reflect.DeepEqual(txContext.SolanaBlockNumber, test.slot) || !reflect.DeepEqual(txContext.SolanaTimestamp, test.timestamp) {
+			t.Errorf("block %d: Solana context mismatch: have %v/%v, want %v/%v", i, txContext.SolanaBlockNumber, txContext.SolanaTimestamp, test.slot, test.timestamp)
+		}
+	}
+	// Blocks imported before the context was recorded are resolved from their
+	// ancestors when their children are imported.
+	rawdb.DeleteSolanaBlockContext(db, blocks[2].Hash(), blocks[2].NumberU64())
+	chain.solanaCtxs.Purge()
+	if context := chain.solanaBlockContext(blocks[2].Header()); context == nil || context.Slot != 120 || context.Timestamp != 1700000002 {
+		t.Fatalf("unrecorded Solana context mismatch: have %v", context)
+	}
+}
+
+func newUint64(val uint64) *uint64 { return &val }
+func newInt64(val int64) *int64    { return &val }
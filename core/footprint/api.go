@@ -17,10 +17,29 @@
 package footprint
 
 import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
+const (
+	// defaultMismatchPageSize is the number of mismatches returned per page if
+	// no limit is requested.
+	defaultMismatchPageSize = 100
+
+	// maxMismatchPageSize is the maximum number of mismatches returned per page.
+	maxMismatchPageSize = 1000
+)
+
 // API provides RPC methods to query state footprints
 type API struct {
 	manager *Manager
@@ -47,6 +66,223 @@ func (api *API) GetFootprintStats() map[string]interface{} {
 	return api.manager.GetStats()
 }
 
+// Mismatch is the RPC representation of a recorded footprint mismatch.
+type Mismatch struct {
+	TxHash       common.Hash    `json:"txHash"`
+	BlockNumber  hexutil.Uint64 `json:"blockNumber"`
+	BlockHash    common.Hash    `json:"blockHash"`
+	Expected     string         `json:"expectedFootprint"`
+	Actual       string         `json:"actualFootprint"`
+	Accounts     []string       `json:"accounts"`
+	Time         hexutil.Uint64 `json:"time"`
+	Acknowledged bool           `json:"acknowledged"`
+	Whitelisted  bool           `json:"whitelisted"`
+}
+
+// MismatchCursor identifies the position of a mismatch to continue paging from.
+type MismatchCursor struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	TxHash      common.Hash    `json:"txHash"`
+}
+
+// MismatchFilter selects the recorded mismatches to return.
+type MismatchFilter struct {
+	FromBlock          *hexutil.Uint64 `json:"fromBlock"`
+	ToBlock            *hexutil.Uint64 `json:"toBlock"`
+	Cursor             *MismatchCursor `json:"cursor"`
+	Limit              *hexutil.Uint64 `json:"limit"`
+	UnacknowledgedOnly bool            `json:"unacknowledgedOnly"`
+}
+
+// MismatchPage is a page of recorded mismatches along with the cursor to fetch
+// the next page with, if any.
+type MismatchPage struct {
+	Mismatches []*Mismatch     `json:"mismatches"`
+	Next       *MismatchCursor `json:"next"`
+}
+
+// MismatchAPI provides RPC methods to triage recorded footprint mismatches.
+type MismatchAPI struct {
+	manager *Manager
+}
+
+// NewMismatchAPI creates a new footprint mismatch API.
+func NewMismatchAPI(manager *Manager) *MismatchAPI {
+	return &MismatchAPI{
+		manager: manager,
+	}
+}
+
+// GetFootprintMismatch returns the recorded footprint mismatch of a transaction.
+func (api *MismatchAPI) GetFootprintMismatch(txHash common.Hash) (*Mismatch, error) {
+	mismatch := api.manager.Mismatch(txHash)
+	if mismatch == nil {
+		return nil, nil
+	}
+	return api.toRPC(mismatch), nil
+}
+
+// GetFootprintMismatches returns a page of recorded footprint mismatches in
+// ascending block order.
+func (api *MismatchAPI) GetFootprintMismatches(filter MismatchFilter) (*MismatchPage, error) {
+	limit := defaultMismatchPageSize
+	if filter.Limit != nil {
+		if *filter.Limit == 0 || *filter.Limit > maxMismatchPageSize {
+			return nil, errors.New("invalid limit, must be between 1 and 1000")
+		}
+		limit = int(*filter.Limit)
+	}
+	from, to, err := filter.blockRange()
+	if err != nil {
+		return nil, err
+	}
+	var after *rawdb.FootprintMismatch
+	if filter.Cursor != nil {
+		after = &rawdb.FootprintMismatch{BlockNumber: uint64(filter.Cursor.BlockNumber), TxHash: filter.Cursor.TxHash}
+	}
+	mismatches, more := api.manager.Mismatches(from, to, after, limit, filter.UnacknowledgedOnly)
+
+	page := &MismatchPage{Mismatches: make([]*Mismatch, 0, len(mismatches))}
+	for _, mismatch := range mismatches {
+		page.Mismatches = append(page.Mismatches, api.toRPC(mismatch))
+	}
+	if more {
+		last := mismatches[len(mismatches)-1]
+		page.Next = &MismatchCursor{BlockNumber: hexutil.Uint64(last.BlockNumber), TxHash: last.TxHash}
+	}
+	return page, nil
+}
+
+// AcknowledgeFootprintMismatch marks a recorded footprint mismatch as triaged.
+func (api *MismatchAPI) AcknowledgeFootprintMismatch(txHash common.Hash) (bool, error) {
+	if err := api.manager.Acknowledge(txHash); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WhitelistFootprintMismatch marks a transaction as a known footprint mismatch.
+func (api *MismatchAPI) WhitelistFootprintMismatch(txHash common.Hash) bool {
+	api.manager.Whitelist(txHash)
+	return true
+}
+
+// RemoveFootprintWhitelist removes a transaction from the known footprint
+// mismatches.
+func (api *MismatchAPI) RemoveFootprintWhitelist(txHash common.Hash) bool {
+	api.manager.RemoveWhitelist(txHash)
+	return true
+}
+
+// ResumeSequencing lifts a sequencing halt caused by a footprint mismatch under
+// the halt policy. It returns false if sequencing was not halted.
+func (api *MismatchAPI) ResumeSequencing() bool {
+	return api.manager.Resume()
+}
+
+// FootprintMismatches creates a subscription that is notified of footprint
+// mismatches detected under the alert, reject or halt policies.
+func (api *MismatchAPI) FootprintMismatches(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan MismatchEvent)
+		sub := api.manager.SubscribeMismatchEvent(events)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				notifier.Notify(rpcSub.ID, api.toRPC(ev.Mismatch))
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// ExportFootprintMismatches writes all recorded footprint mismatches matching
+// the filter into a file as newline delimited JSON, compressed if the file name
+// ends with .gz. The limit and cursor of the filter are ignored. It returns the
+// number of exported mismatches.
+func (api *MismatchAPI) ExportFootprintMismatches(file string, filter MismatchFilter) (hexutil.Uint64, error) {
+	from, to, err := filter.blockRange()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := os.Stat(file); err == nil {
+		// File already exists. Allowing overwrite could be a DoS vector,
+		// since the 'file' may point to arbitrary paths on the drive.
+		return 0, errors.New("location would overwrite an existing file")
+	}
+	out, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	var writer io.Writer = out
+	if strings.HasSuffix(file, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	var (
+		enc   = json.NewEncoder(writer)
+		after *rawdb.FootprintMismatch
+		count uint64
+	)
+	for {
+		mismatches, more := api.manager.Mismatches(from, to, after, maxMismatchPageSize, filter.UnacknowledgedOnly)
+		for _, mismatch := range mismatches {
+			if err := enc.Encode(api.toRPC(mismatch)); err != nil {
+				return hexutil.Uint64(count), err
+			}
+			count++
+		}
+		if !more {
+			return hexutil.Uint64(count), nil
+		}
+		after = mismatches[len(mismatches)-1]
+	}
+}
+
+// toRPC converts a stored footprint mismatch into its RPC representation.
+func (api *MismatchAPI) toRPC(mismatch *rawdb.FootprintMismatch) *Mismatch {
+	return &Mismatch{
+		TxHash:       mismatch.TxHash,
+		BlockNumber:  hexutil.Uint64(mismatch.BlockNumber),
+		BlockHash:    mismatch.BlockHash,
+		Expected:     mismatch.Expected,
+		Actual:       mismatch.Actual,
+		Accounts:     mismatch.Accounts,
+		Time:         hexutil.Uint64(mismatch.Time),
+		Acknowledged: mismatch.Acknowledged,
+		Whitelisted:  api.manager.IsKnownMismatch(mismatch.TxHash),
+	}
+}
+
+// blockRange returns the inclusive block range selected by the filter.
+func (filter *MismatchFilter) blockRange() (uint64, uint64, error) {
+	from, to := uint64(0), ^uint64(0)
+	if filter.FromBlock != nil {
+		from = uint64(*filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		to = uint64(*filter.ToBlock)
+	}
+	if from > to {
+		return 0, 0, errors.New("invalid block range, fromBlock is greater than toBlock")
+	}
+	return from, to, nil
+}
+
 // GetAPIs returns the collection of RPC services offered by footprint manager
 func GetAPIs(manager *Manager) []rpc.API {
 	return []rpc.API{
@@ -55,6 +291,9 @@ func GetAPIs(manager *Manager) []rpc.API {
 			Service:   NewAPI(manager),
 			Public:    true,
 		},
+		{
+			Namespace: "rome",
+			Service:   NewMismatchAPI(manager),
+		},
 	}
 }
-
@@ -18,61 +18,107 @@ package footprint
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// legacyMismatchFile is the flat text file known mismatches used to be kept in
+// before they were moved into the chain database.
+const legacyMismatchFile = "known_footprint_mismatches.txt"
+
+// errUnknownMismatch is returned if no footprint mismatch is recorded for a
+// transaction.
+var errUnknownMismatch = errors.New("unknown footprint mismatch")
+
+var (
+	emulationMatchMeter    = metrics.NewRegisteredMeter("footprint/emulation/match", nil)
+	emulationMismatchMeter = metrics.NewRegisteredMeter("footprint/emulation/mismatch", nil)
 )
 
 // Entry represents a cached state footprint entry
 type Entry struct {
-	ExpectedFootprint string 
-	ActualFootprint   string 
+	ExpectedFootprint string
+	ActualFootprint   string
 	BlockNumber       uint64
-	Mismatch          bool  
+	Mismatch          bool
+	EmulatedFootprint string // Footprint expected by the emulation at submission, empty if not emulated
+	EmulationMismatch bool   // Whether the emulated footprint diverges from the actual one
 }
 
-// Manager handles both footprint caching and mismatch tracking
-type Manager struct {
-	mu              sync.RWMutex
-	cache           map[common.Hash]*Entry       
-	knownMismatches map[common.Hash]bool        
-	mismatchFile    string                       
-	maxCacheAge     uint64                       
+// emulation is the footprint a transaction emulation expects, retained until
+// the transaction is executed to compare against.
+type emulation struct {
+	footprint   string
+	blockNumber uint64 // Head block at the time of emulation
 }
 
-var (
-	globalManager     *Manager
-	globalManagerOnce sync.Once
-)
+// Manager handles both footprint caching and mismatch tracking. Recent
+// footprints are cached in memory, while mismatches are persisted in the chain
+// database so they survive restarts. Newly detected mismatches are handled
+// according to the configured enforcement policy.
+type Manager struct {
+	mu          sync.RWMutex
+	db          ethdb.KeyValueStore
+	cache       map[common.Hash]*Entry
+	emulations  map[common.Hash]*emulation
+	maxCacheAge uint64
+
+	recorded       int // Number of mismatches recorded in the database
+	unacknowledged int // Number of recorded mismatches not yet triaged
+	whitelisted    int // Number of mismatches whitelisted as known
+
+	policy Policy
+	feed   event.Feed
+	halted *rawdb.FootprintMismatch // Mismatch sequencing was halted on, nil if running
+}
 
-// GetManager returns the global footprint Manager.
-func GetManager(dataDir string) *Manager {
-	globalManagerOnce.Do(func() {
-		mismatchFile := filepath.Join(dataDir, "known_footprint_mismatches.txt")
-		globalManager = &Manager{
-			cache:           make(map[common.Hash]*Entry),
-			knownMismatches: make(map[common.Hash]bool),
-			mismatchFile:    mismatchFile,
-			maxCacheAge:     12,
+// NewManager creates a footprint manager persisting mismatches into db and
+// enforcing the given policy. Known mismatches from the legacy text file in
+// dataDir are imported on startup.
+func NewManager(db ethdb.KeyValueStore, dataDir string, policy Policy) *Manager {
+	m := &Manager{
+		db:          db,
+		cache:       make(map[common.Hash]*Entry),
+		emulations:  make(map[common.Hash]*emulation),
+		maxCacheAge: 12,
+		policy:      policy,
+	}
+	m.importLegacyMismatches(filepath.Join(dataDir, legacyMismatchFile))
+
+	// Count the persisted mismatches once, the counters are maintained along
+	// with the database afterwards.
+	rawdb.IterateFootprintMismatches(db, 0, func(mismatch *rawdb.FootprintMismatch) bool {
+		m.recorded++
+		if !mismatch.Acknowledged {
+			m.unacknowledged++
 		}
-		globalManager.loadKnownMismatches()
+		return true
 	})
-	return globalManager
+	m.whitelisted = len(rawdb.ReadFootprintWhitelist(db))
+	return m
 }
 
-// loadKnownMismatches reads known mismatch tx hashes from disk
-func (m *Manager) loadKnownMismatches() {
-	file, err := os.Open(m.mismatchFile)
+// importLegacyMismatches moves the known mismatch tx hashes of the legacy text
+// file into the database whitelist and renames the file afterwards.
+func (m *Manager) importLegacyMismatches(path string) {
+	file, err := os.Open(path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			log.Info("No known footprint mismatches file found, starting fresh", "path", m.mismatchFile)
-			return
+		if !os.IsNotExist(err) {
+			log.Warn("Failed to open known footprint mismatches file", "path", path, "error", err)
 		}
-		log.Warn("Failed to open known footprint mismatches file", "path", m.mismatchFile, "error", err)
 		return
 	}
 	defer file.Close()
@@ -84,56 +130,122 @@ func (m *Manager) loadKnownMismatches() {
 		if line == "" || line[0] == '#' {
 			continue
 		}
-		txHash := common.HexToHash(line)
-		m.knownMismatches[txHash] = true
+		rawdb.WriteFootprintWhitelist(m.db, common.HexToHash(line))
 		count++
 	}
-
 	if err := scanner.Err(); err != nil {
-		log.Warn("Error reading known footprint mismatches file", "path", m.mismatchFile, "error", err)
+		log.Warn("Error reading known footprint mismatches file", "path", path, "error", err)
 		return
 	}
-
-	log.Info("Loaded known footprint mismatches", "count", count, "path", m.mismatchFile)
+	if err := os.Rename(path, path+".imported"); err != nil {
+		log.Warn("Failed to rename imported footprint mismatches file", "path", path, "error", err)
+	}
+	log.Info("Imported known footprint mismatches", "count", count, "path", path)
 }
 
-// IsKnownMismatch checks if a transaction hash is in the known mismatches list
+// IsKnownMismatch checks if a transaction hash is whitelisted as a known
+// footprint mismatch. Mismatches merely recorded are not known.
 func (m *Manager) IsKnownMismatch(txHash common.Hash) bool {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	return m.knownMismatches[txHash]
+	return rawdb.HasFootprintWhitelist(m.db, txHash)
 }
 
-// RecordMismatch adds a new mismatch to the known list and persists to disk
-func (m *Manager) RecordMismatch(txHash common.Hash) error {
+// RecordMismatch persists a footprint mismatch. If the transaction already has
+// a recorded mismatch (e.g. the block is re-executed), its detection time and
+// acknowledgement are retained.
+func (m *Manager) RecordMismatch(mismatch *rawdb.FootprintMismatch) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Check if already known
-	if m.knownMismatches[txHash] {
-		return nil
+	if prev := rawdb.ReadFootprintMismatch(m.db, mismatch.TxHash); prev != nil {
+		mismatch.Time = prev.Time
+		mismatch.Acknowledged = prev.Acknowledged
+	} else {
+		mismatch.Time = uint64(time.Now().Unix())
+		m.recorded++
+		m.unacknowledged++
+		log.Info("Recorded new footprint mismatch", "tx", mismatch.TxHash.Hex(), "block", mismatch.BlockNumber)
 	}
+	rawdb.WriteFootprintMismatch(m.db, mismatch)
+}
 
-	// Add to in-memory map
-	m.knownMismatches[txHash] = true
+// Mismatch retrieves the recorded footprint mismatch of a transaction.
+func (m *Manager) Mismatch(txHash common.Hash) *rawdb.FootprintMismatch {
+	return rawdb.ReadFootprintMismatch(m.db, txHash)
+}
 
-	// Append to file
-	file, err := os.OpenFile(m.mismatchFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		log.Error("Failed to open known footprint mismatches file for writing", "path", m.mismatchFile, "error", err)
-		return err
+// Mismatches retrieves at most limit recorded footprint mismatches within the
+// block range [from, to] in ascending order. If after is set, only mismatches
+// strictly following it are returned. The second return value reports whether
+// more mismatches are available in the range.
+func (m *Manager) Mismatches(from, to uint64, after *rawdb.FootprintMismatch, limit int, unacknowledged bool) ([]*rawdb.FootprintMismatch, bool) {
+	var (
+		result []*rawdb.FootprintMismatch
+		more   bool
+	)
+	start := from
+	if after != nil && after.BlockNumber > start {
+		start = after.BlockNumber
 	}
-	defer file.Close()
+	rawdb.IterateFootprintMismatches(m.db, start, func(mismatch *rawdb.FootprintMismatch) bool {
+		if mismatch.BlockNumber > to {
+			return false
+		}
+		if after != nil && mismatch.BlockNumber == after.BlockNumber && bytes.Compare(mismatch.TxHash[:], after.TxHash[:]) <= 0 {
+			return true
+		}
+		if unacknowledged && mismatch.Acknowledged {
+			return true
+		}
+		if len(result) == limit {
+			more = true
+			return false
+		}
+		result = append(result, mismatch)
+		return true
+	})
+	return result, more
+}
 
-	if _, err := file.WriteString(txHash.Hex() + "\n"); err != nil {
-		log.Error("Failed to write to known footprint mismatches file", "path", m.mismatchFile, "error", err)
-		return err
-	}
+// Acknowledge marks the recorded footprint mismatch of a transaction as triaged.
+func (m *Manager) Acknowledge(txHash common.Hash) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	log.Info("Recorded new footprint mismatch", "tx", txHash.Hex(), "path", m.mismatchFile)
+	mismatch := rawdb.ReadFootprintMismatch(m.db, txHash)
+	if mismatch == nil {
+		return errUnknownMismatch
+	}
+	if !mismatch.Acknowledged {
+		m.unacknowledged--
+	}
+	mismatch.Acknowledged = true
+	rawdb.WriteFootprintMismatch(m.db, mismatch)
 	return nil
 }
 
+// Whitelist marks a transaction as a known footprint mismatch, which is then
+// only reported as a warning.
+func (m *Manager) Whitelist(txHash common.Hash) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !rawdb.HasFootprintWhitelist(m.db, txHash) {
+		m.whitelisted++
+	}
+	rawdb.WriteFootprintWhitelist(m.db, txHash)
+}
+
+// RemoveWhitelist removes a transaction from the known footprint mismatches.
+func (m *Manager) RemoveWhitelist(txHash common.Hash) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if rawdb.HasFootprintWhitelist(m.db, txHash) {
+		m.whitelisted--
+	}
+	rawdb.DeleteFootprintWhitelist(m.db, txHash)
+}
+
 // Store stores a footprint entry in the cache
 // It validates footprint strings to prevent DoS attacks via arbitrarily large payloads.
 func (m *Manager) Store(txHash common.Hash, expectedFootprint, actualFootprint string, blockNumber uint64, mismatch bool) {
@@ -148,12 +260,38 @@ func (m *Manager) Store(txHash common.Hash, expectedFootprint, actualFootprint s
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.cache[txHash] = &Entry{
+	entry := &Entry{
 		ExpectedFootprint: expectedFootprint,
 		ActualFootprint:   actualFootprint,
 		BlockNumber:       blockNumber,
 		Mismatch:          mismatch,
 	}
+	if em := m.emulations[txHash]; em != nil {
+		entry.EmulatedFootprint = em.footprint
+		entry.EmulationMismatch = common.HexToHash(em.footprint) != common.HexToHash(actualFootprint)
+		if entry.EmulationMismatch {
+			emulationMismatchMeter.Mark(1)
+			log.Warn("Emulated state footprint diverges from execution", "tx", txHash, "emulated", em.footprint, "actual", actualFootprint)
+		} else {
+			emulationMatchMeter.Mark(1)
+		}
+	}
+	m.cache[txHash] = entry
+}
+
+// RecordEmulation stores the footprint expected by the emulation of a submitted
+// transaction, to be compared against the footprint of its execution.
+func (m *Manager) RecordEmulation(txHash common.Hash, footprint string, blockNumber uint64) {
+	if footprint == "" || !isValidFootprint(footprint) {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.emulations == nil {
+		m.emulations = make(map[common.Hash]*emulation)
+	}
+	m.emulations[txHash] = &emulation{footprint: footprint, blockNumber: blockNumber}
 }
 
 // Get retrieves a footprint entry from the cache
@@ -180,6 +318,11 @@ func (m *Manager) EvictOldEntries(currentBlockNumber uint64) {
 			delete(m.cache, txHash)
 		}
 	}
+	for txHash, em := range m.emulations {
+		if em.blockNumber < minBlockNumber {
+			delete(m.emulations, txHash)
+		}
+	}
 }
 
 // GetStats returns statistics about the footprint manager
@@ -193,16 +336,20 @@ func (m *Manager) GetStats() map[string]interface{} {
 			mismatchCount++
 		}
 	}
-
 	return map[string]interface{}{
-		"cache_size":               len(m.cache),
-		"cache_mismatch_count":     mismatchCount,
-		"known_mismatches_count":   len(m.knownMismatches),
-		"max_cache_age_blocks":     m.maxCacheAge,
+		"cache_size":                    len(m.cache),
+		"cache_mismatch_count":          mismatchCount,
+		"pending_emulations_count":      len(m.emulations),
+		"recorded_mismatches_count":     m.recorded,
+		"unacknowledged_mismatch_count": m.unacknowledged,
+		"known_mismatches_count":        m.whitelisted,
+		"max_cache_age_blocks":          m.maxCacheAge,
+		"policy":                        m.policy.String(),
+		"sequencing_halted":             m.halted != nil,
 	}
 }
 
-// Clear removes all cache entries 
+// Clear removes all cache entries
 func (m *Manager) ClearCache() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -211,9 +358,81 @@ func (m *Manager) ClearCache() {
 	log.Info("Footprint cache cleared")
 }
 
-// ShouldPanic checks the environment variable to determine if we should panic on mismatch.
-func (m *Manager) ShouldPanic() bool {
-	return os.Getenv("GETH_FOOTPRINT_PANIC") == "true"
+// Policy returns the footprint mismatch enforcement policy.
+func (m *Manager) Policy() Policy {
+	return m.policy
+}
+
+// HandleMismatch records a footprint mismatch and applies the enforcement
+// policy to it. Whitelisted mismatches are only logged. Enforcement is skipped
+// if enforce is false, i.e. no footprint was submitted for the transaction. A
+// non-nil error is returned if the block containing the transaction must be
+// rejected.
+func (m *Manager) HandleMismatch(mismatch *rawdb.FootprintMismatch, enforce bool) error {
+	whitelisted := m.IsKnownMismatch(mismatch.TxHash)
+	if whitelisted {
+		log.Warn("State footprint mismatch", "tx", mismatch.TxHash, "expected", mismatch.Expected, "got", mismatch.Actual)
+	} else {
+		log.Error("State footprint mismatch", "tx", mismatch.TxHash, "expected", mismatch.Expected, "got", mismatch.Actual, "policy", m.policy)
+	}
+	m.RecordMismatch(mismatch)
+
+	if whitelisted {
+		knownMismatchMeter.Mark(1)
+		return nil
+	}
+	if !enforce {
+		logMismatchMeter.Mark(1)
+		return nil
+	}
+	m.policy.meter().Mark(1)
+	if m.policy != PolicyLog {
+		m.feed.Send(MismatchEvent{Mismatch: mismatch, Policy: m.policy})
+	}
+	switch m.policy {
+	case PolicyReject:
+		return fmt.Errorf("%w: tx %s expected %s, got %s", ErrFootprintMismatch, mismatch.TxHash.Hex(), mismatch.Expected, mismatch.Actual)
+	case PolicyHalt:
+		m.mu.Lock()
+		if m.halted == nil {
+			m.halted = mismatch
+			log.Error("Halting sequencing on state footprint mismatch", "tx", mismatch.TxHash, "block", mismatch.BlockNumber)
+		}
+		m.mu.Unlock()
+		return fmt.Errorf("%w: tx %s expected %s, got %s", ErrFootprintMismatch, mismatch.TxHash.Hex(), mismatch.Expected, mismatch.Actual)
+	}
+	return nil
+}
+
+// Halted returns a non-nil error if sequencing has been halted due to a
+// footprint mismatch under the halt policy.
+func (m *Manager) Halted() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.halted == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: tx %s in block %d", ErrSequencingHalted, m.halted.TxHash.Hex(), m.halted.BlockNumber)
+}
+
+// Resume lifts a sequencing halt. It returns false if sequencing was not halted.
+func (m *Manager) Resume() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.halted == nil {
+		return false
+	}
+	log.Warn("Resuming sequencing after state footprint mismatch", "tx", m.halted.TxHash, "block", m.halted.BlockNumber)
+	m.halted = nil
+	return true
+}
+
+// SubscribeMismatchEvent registers a subscription for footprint mismatches
+// detected under the alert, reject or halt policies.
+func (m *Manager) SubscribeMismatchEvent(ch chan<- MismatchEvent) event.Subscription {
+	return m.feed.Subscribe(ch)
 }
 
 // isValidFootprint validates that a footprint string is a valid fixed-length hex hash.
@@ -224,28 +443,28 @@ func isValidFootprint(footprint string) bool {
 	// Maximum reasonable length: "0x" prefix + 64 hex chars = 66 characters
 	// Allow some buffer for edge cases, but prevent DoS via huge strings
 	const maxLength = 100
-	
+
 	if len(footprint) > maxLength {
 		return false
 	}
-	
+
 	// Empty string is always valid
 	if footprint == "" {
 		return true
 	}
-	
+
 	// Check if it's a valid hex string (with optional "0x" prefix)
 	hexPart := footprint
 	if strings.HasPrefix(footprint, "0x") || strings.HasPrefix(footprint, "0X") {
 		hexPart = footprint[2:]
 	}
-	
+
 	// Validate all characters are hex digits
 	for _, r := range hexPart {
 		if !((r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')) {
 			return false
 		}
 	}
-	
+
 	return true
 }
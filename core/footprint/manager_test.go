@@ -0,0 +1,236 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package footprint
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+)
+
+// Tests that mismatches are persisted and survive a manager restart, and that
+// re-recording a mismatch retains its acknowledgement.
+func TestMismatchPersistence(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	dir := t.TempDir()
+
+	manager := NewManager(db, dir, PolicyLog)
+	tx := common.Hash{0x01}
+	if manager.IsKnownMismatch(tx) {
+		t.Fatal("unrecorded mismatch reported as known")
+	}
+	manager.RecordMismatch(&rawdb.FootprintMismatch{TxHash: tx, BlockNumber: 5, Expected: "0x1", Actual: "0x2", Accounts: []string{"Address: 0x00"}})
+
+	manager = NewManager(db, dir, PolicyLog)
+	if manager.Mismatch(tx) == nil {
+		t.Fatal("recorded mismatch not available after restart")
+	}
+	if manager.IsKnownMismatch(tx) {
+		t.Fatal("recorded mismatch reported as known without whitelisting")
+	}
+	if err := manager.Acknowledge(tx); err != nil {
+		t.Fatalf("failed to acknowledge mismatch: %v", err)
+	}
+	manager.RecordMismatch(&rawdb.FootprintMismatch{TxHash: tx, BlockNumber: 5, Expected: "0x1", Actual: "0x3"})
+	mismatch := manager.Mismatch(tx)
+	if mismatch == nil || !mismatch.Acknowledged || mismatch.Actual != "0x3" {
+		t.Fatalf("unexpected mismatch after re-recording: %+v", mismatch)
+	}
+	if err := manager.Acknowledge(common.Hash{0x02}); err != errUnknownMismatch {
+		t.Fatalf("acknowledge of unknown mismatch: have %v, want %v", err, errUnknownMismatch)
+	}
+}
+
+// Tests that known mismatches of the legacy text file are imported.
+func TestLegacyMismatchImport(t *testing.T) {
+	dir := t.TempDir()
+	tx := common.Hash{0xaa}
+	if err := os.WriteFile(filepath.Join(dir, legacyMismatchFile), []byte("# comment\n"+tx.Hex()+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManager(rawdb.NewMemoryDatabase(), dir, PolicyLog)
+	if !manager.IsKnownMismatch(tx) {
+		t.Fatal("legacy mismatch not imported")
+	}
+	if _, err := os.Stat(filepath.Join(dir, legacyMismatchFile)); !os.IsNotExist(err) {
+		t.Fatal("legacy mismatch file not renamed after import")
+	}
+}
+
+// Tests paging through mismatches of a block range.
+func TestMismatchPaging(t *testing.T) {
+	manager := NewManager(rawdb.NewMemoryDatabase(), t.TempDir(), PolicyLog)
+	for i := 0; i < 10; i++ {
+		manager.RecordMismatch(&rawdb.FootprintMismatch{TxHash: common.Hash{byte(i)}, BlockNumber: uint64(i)})
+	}
+	var (
+		api   = NewMismatchAPI(manager)
+		from  = hexutil.Uint64(2)
+		to    = hexutil.Uint64(8)
+		limit = hexutil.Uint64(3)
+		seen  []uint64
+	)
+	filter := MismatchFilter{FromBlock: &from, ToBlock: &to, Limit: &limit}
+	for {
+		page, err := api.GetFootprintMismatches(filter)
+		if err != nil {
+			t.Fatalf("failed to get mismatches: %v", err)
+		}
+		for _, mismatch := range page.Mismatches {
+			seen = append(seen, uint64(mismatch.BlockNumber))
+		}
+		if page.Next == nil {
+			break
+		}
+		filter.Cursor = page.Next
+	}
+	if len(seen) != 7 {
+		t.Fatalf("mismatch count mismatch: have %d, want %d (%v)", len(seen), 7, seen)
+	}
+	for i, number := range seen {
+		if number != uint64(i)+2 {
+			t.Fatalf("mismatch %d: have block %d, want %d", i, number, i+2)
+		}
+	}
+}
+
+// Tests that mismatches are handled according to the enforcement policy.
+func TestMismatchPolicy(t *testing.T) {
+	tests := []struct {
+		policy  Policy
+		enforce bool
+		reject  bool
+		halt    bool
+		event   bool
+	}{
+		{policy: PolicyLog, enforce: true},
+		{policy: PolicyAlert, enforce: true, event: true},
+		{policy: PolicyReject, enforce: true, reject: true, event: true},
+		{policy: PolicyHalt, enforce: true, reject: true, halt: true, event: true},
+		{policy: PolicyHalt, enforce: false},
+	}
+	for i, tt := range tests {
+		manager := NewManager(rawdb.NewMemoryDatabase(), t.TempDir(), tt.policy)
+		events := make(chan MismatchEvent, 1)
+		sub := manager.SubscribeMismatchEvent(events)
+
+		mismatch := &rawdb.FootprintMismatch{TxHash: common.Hash{0x01}, BlockNumber: 1, Expected: "0x1", Actual: "0x2"}
+		err := manager.HandleMismatch(mismatch, tt.enforce)
+		if reject := errors.Is(err, ErrFootprintMismatch); reject != tt.reject {
+			t.Errorf("test %d (%v): rejection mismatch: have %v, want %v", i, tt.policy, err, tt.reject)
+		}
+		if halted := manager.Halted() != nil; halted != tt.halt {
+			t.Errorf("test %d (%v): halt mismatch: have %v, want %v", i, tt.policy, halted, tt.halt)
+		}
+		select {
+		case <-events:
+			if !tt.event {
+				t.Errorf("test %d (%v): unexpected mismatch event", i, tt.policy)
+			}
+		default:
+			if tt.event {
+				t.Errorf("test %d (%v): missing mismatch event", i, tt.policy)
+			}
+		}
+		if manager.Mismatch(mismatch.TxHash) == nil {
+			t.Errorf("test %d (%v): mismatch not recorded", i, tt.policy)
+		}
+		// Rejected blocks must stay rejected on resubmission unless whitelisted
+		if tt.reject {
+			if err := manager.HandleMismatch(mismatch, true); !errors.Is(err, ErrFootprintMismatch) {
+				t.Errorf("test %d (%v): resubmitted mismatch not rejected: %v", i, tt.policy, err)
+			}
+			manager.Whitelist(mismatch.TxHash)
+			if err := manager.HandleMismatch(mismatch, true); err != nil {
+				t.Errorf("test %d (%v): whitelisted mismatch rejected: %v", i, tt.policy, err)
+			}
+		}
+		if tt.halt {
+			if !manager.Resume() || manager.Halted() != nil {
+				t.Errorf("test %d (%v): failed to resume sequencing", i, tt.policy)
+			}
+		}
+		sub.Unsubscribe()
+	}
+}
+
+// Tests that emulated footprints are compared against the executed ones without
+// being used as the expected footprint, and are evicted with the cache.
+func TestEmulationComparison(t *testing.T) {
+	manager := NewManager(rawdb.NewMemoryDatabase(), t.TempDir(), PolicyLog)
+
+	matching, diverging := common.Hash{0x01}, common.Hash{0x02}
+	manager.RecordEmulation(matching, "0x01", 10)
+	manager.RecordEmulation(diverging, "0x01", 10)
+
+	manager.Store(matching, "", "0x0000000000000000000000000000000000000000000000000000000000000001", 11, false)
+	manager.Store(diverging, "", "0x02", 11, false)
+
+	if entry, _ := manager.Get(matching); entry.ExpectedFootprint != "" || entry.EmulatedFootprint != "0x01" || entry.EmulationMismatch {
+		t.Errorf("unexpected entry for matching emulation: %+v", entry)
+	}
+	if entry, _ := manager.Get(diverging); !entry.EmulationMismatch {
+		t.Errorf("diverging emulation not flagged: %+v", entry)
+	}
+	manager.EvictOldEntries(10 + manager.maxCacheAge + 1)
+	if len(manager.emulations) != 0 {
+		t.Errorf("stale emulations not evicted: have %d", len(manager.emulations))
+	}
+}
+
+// Tests that the mismatch statistics follow the recorded, acknowledged and
+// whitelisted mismatches, including across a restart.
+func TestMismatchStats(t *testing.T) {
+	var (
+		db  = rawdb.NewMemoryDatabase()
+		dir = t.TempDir()
+	)
+	check := func(manager *Manager, recorded, unacknowledged, whitelisted int) {
+		t.Helper()
+
+		stats := manager.GetStats()
+		if have := stats["recorded_mismatches_count"]; have != recorded {
+			t.Errorf("recorded count mismatch: have %v, want %d", have, recorded)
+		}
+		if have := stats["unacknowledged_mismatch_count"]; have != unacknowledged {
+			t.Errorf("unacknowledged count mismatch: have %v, want %d", have, unacknowledged)
+		}
+		if have := stats["known_mismatches_count"]; have != whitelisted {
+			t.Errorf("whitelisted count mismatch: have %v, want %d", have, whitelisted)
+		}
+	}
+	manager := NewManager(db, dir, PolicyLog)
+	for i := 0; i < 3; i++ {
+		manager.RecordMismatch(&rawdb.FootprintMismatch{TxHash: common.Hash{byte(i)}, BlockNumber: uint64(i)})
+	}
+	manager.RecordMismatch(&rawdb.FootprintMismatch{TxHash: common.Hash{0x00}, BlockNumber: 0})
+	manager.Acknowledge(common.Hash{0x01})
+	manager.Acknowledge(common.Hash{0x01})
+	manager.Whitelist(common.Hash{0x02})
+	manager.Whitelist(common.Hash{0x02})
+	manager.Whitelist(common.Hash{0x03})
+	manager.RemoveWhitelist(common.Hash{0x03})
+	manager.RemoveWhitelist(common.Hash{0x04})
+	check(manager, 3, 2, 1)
+
+	check(NewManager(db, dir, PolicyLog), 3, 2, 1)
+}
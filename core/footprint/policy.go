@@ -0,0 +1,120 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package footprint
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	// ErrFootprintMismatch is returned if the state footprint of a transaction
+	// diverges from the one submitted by Rome and the enforcement policy
+	// requires the containing block to be rejected.
+	ErrFootprintMismatch = errors.New("state footprint mismatch")
+
+	// ErrSequencingHalted is returned when building a payload is attempted after
+	// sequencing has been halted due to a footprint mismatch.
+	ErrSequencingHalted = errors.New("sequencing halted on footprint mismatch")
+)
+
+// Policy represents how the node reacts to a state footprint mismatch that is
+// neither whitelisted nor previously recorded.
+type Policy uint32
+
+const (
+	PolicyLog    Policy = iota // Record and log the mismatch, keep processing
+	PolicyReject               // Reject the block containing the mismatching transaction
+	PolicyHalt                 // Reject the block and refuse building payloads until resumed
+	PolicyAlert                // Record the mismatch and publish it on the event feed, keep processing
+)
+
+var (
+	knownMismatchMeter  = metrics.NewRegisteredMeter("footprint/mismatch/known", nil)
+	logMismatchMeter    = metrics.NewRegisteredMeter("footprint/mismatch/log", nil)
+	rejectMismatchMeter = metrics.NewRegisteredMeter("footprint/mismatch/reject", nil)
+	haltMismatchMeter   = metrics.NewRegisteredMeter("footprint/mismatch/halt", nil)
+	alertMismatchMeter  = metrics.NewRegisteredMeter("footprint/mismatch/alert", nil)
+)
+
+// IsValid reports whether the policy is a known one.
+func (p Policy) IsValid() bool {
+	return p >= PolicyLog && p <= PolicyAlert
+}
+
+// String implements the stringer interface.
+func (p Policy) String() string {
+	switch p {
+	case PolicyLog:
+		return "log"
+	case PolicyReject:
+		return "reject"
+	case PolicyHalt:
+		return "halt"
+	case PolicyAlert:
+		return "alert"
+	default:
+		return "unknown"
+	}
+}
+
+func (p Policy) MarshalText() ([]byte, error) {
+	if !p.IsValid() {
+		return nil, fmt.Errorf("unknown footprint policy %d", p)
+	}
+	return []byte(p.String()), nil
+}
+
+func (p *Policy) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "log":
+		*p = PolicyLog
+	case "reject":
+		*p = PolicyReject
+	case "halt":
+		*p = PolicyHalt
+	case "alert":
+		*p = PolicyAlert
+	default:
+		return fmt.Errorf(`unknown footprint policy %q, want "log", "reject", "halt" or "alert"`, text)
+	}
+	return nil
+}
+
+// meter returns the metric counting the mismatches handled under the policy.
+func (p Policy) meter() metrics.Meter {
+	switch p {
+	case PolicyReject:
+		return rejectMismatchMeter
+	case PolicyHalt:
+		return haltMismatchMeter
+	case PolicyAlert:
+		return alertMismatchMeter
+	default:
+		return logMismatchMeter
+	}
+}
+
+// MismatchEvent is posted on the manager's event feed when a footprint mismatch
+// is detected under the alert, reject or halt policies.
+type MismatchEvent struct {
+	Mismatch *rawdb.FootprintMismatch
+	Policy   Policy
+}
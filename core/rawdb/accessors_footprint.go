@@ -0,0 +1,181 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	// footprintMismatchPrefix + num (uint64 big endian) + tx hash -> footprint mismatch
+	footprintMismatchPrefix = []byte("rome-fpm-")
+
+	// footprintMismatchLookupPrefix + tx hash -> num (uint64 big endian)
+	footprintMismatchLookupPrefix = []byte("rome-fpl-")
+
+	// footprintWhitelistPrefix + tx hash -> empty
+	footprintWhitelistPrefix = []byte("rome-fpw-")
+)
+
+// FootprintMismatch is a state footprint divergence between Rome-EVM and the
+// local EVM execution of a transaction, as persisted in the database.
+type FootprintMismatch struct {
+	TxHash       common.Hash
+	BlockNumber  uint64
+	BlockHash    common.Hash
+	Expected     string   // Footprint submitted by Rome
+	Actual       string   // Footprint computed by the local EVM
+	Accounts     []string // Per-account footprint preimage dump
+	Time         uint64   // Unix timestamp of the first detection
+	Acknowledged bool     // Whether the mismatch has been triaged
+}
+
+// footprintMismatchKey = footprintMismatchPrefix + num (uint64 big endian) + tx hash
+func footprintMismatchKey(number uint64, txHash common.Hash) []byte {
+	return append(append(append([]byte{}, footprintMismatchPrefix...), encodeBlockNumber(number)...), txHash.Bytes()...)
+}
+
+// footprintMismatchLookupKey = footprintMismatchLookupPrefix + tx hash
+func footprintMismatchLookupKey(txHash common.Hash) []byte {
+	return append(append([]byte{}, footprintMismatchLookupPrefix...), txHash.Bytes()...)
+}
+
+// footprintWhitelistKey = footprintWhitelistPrefix + tx hash
+func footprintWhitelistKey(txHash common.Hash) []byte {
+	return append(append([]byte{}, footprintWhitelistPrefix...), txHash.Bytes()...)
+}
+
+// ReadFootprintMismatch retrieves the footprint mismatch recorded for the given
+// transaction, or nil if there is none.
+func ReadFootprintMismatch(db ethdb.KeyValueReader, txHash common.Hash) *FootprintMismatch {
+	enc, err := db.Get(footprintMismatchLookupKey(txHash))
+	if err != nil || len(enc) != 8 {
+		return nil
+	}
+	data, err := db.Get(footprintMismatchKey(binary.BigEndian.Uint64(enc), txHash))
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	return decodeFootprintMismatch(data)
+}
+
+// WriteFootprintMismatch stores a footprint mismatch, replacing any previous
+// record of the same transaction.
+func WriteFootprintMismatch(db ethdb.KeyValueStore, mismatch *FootprintMismatch) {
+	if prev := ReadFootprintMismatch(db, mismatch.TxHash); prev != nil && prev.BlockNumber != mismatch.BlockNumber {
+		if err := db.Delete(footprintMismatchKey(prev.BlockNumber, prev.TxHash)); err != nil {
+			log.Crit("Failed to delete stale footprint mismatch", "err", err)
+		}
+	}
+	data, err := rlp.EncodeToBytes(mismatch)
+	if err != nil {
+		log.Crit("Failed to RLP encode footprint mismatch", "err", err)
+	}
+	if err := db.Put(footprintMismatchKey(mismatch.BlockNumber, mismatch.TxHash), data); err != nil {
+		log.Crit("Failed to store footprint mismatch", "err", err)
+	}
+	if err := db.Put(footprintMismatchLookupKey(mismatch.TxHash), encodeBlockNumber(mismatch.BlockNumber)); err != nil {
+		log.Crit("Failed to store footprint mismatch lookup", "err", err)
+	}
+}
+
+// DeleteFootprintMismatch removes the footprint mismatch recorded for the given
+// transaction.
+func DeleteFootprintMismatch(db ethdb.KeyValueStore, txHash common.Hash) {
+	prev := ReadFootprintMismatch(db, txHash)
+	if prev == nil {
+		return
+	}
+	if err := db.Delete(footprintMismatchKey(prev.BlockNumber, txHash)); err != nil {
+		log.Crit("Failed to delete footprint mismatch", "err", err)
+	}
+	if err := db.Delete(footprintMismatchLookupKey(txHash)); err != nil {
+		log.Crit("Failed to delete footprint mismatch lookup", "err", err)
+	}
+}
+
+// IterateFootprintMismatches calls fn for every stored footprint mismatch in
+// ascending block number order, starting at block from. The iteration stops
+// as soon as fn returns false.
+func IterateFootprintMismatches(db ethdb.Iteratee, from uint64, fn func(*FootprintMismatch) bool) {
+	it := db.NewIterator(footprintMismatchPrefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != len(footprintMismatchPrefix)+8+common.HashLength {
+			continue
+		}
+		mismatch := decodeFootprintMismatch(it.Value())
+		if mismatch == nil {
+			continue
+		}
+		if !fn(mismatch) {
+			return
+		}
+	}
+}
+
+// decodeFootprintMismatch decodes an RLP encoded footprint mismatch.
+func decodeFootprintMismatch(data []byte) *FootprintMismatch {
+	mismatch := new(FootprintMismatch)
+	if err := rlp.DecodeBytes(data, mismatch); err != nil {
+		log.Error("Invalid footprint mismatch RLP", "err", err)
+		return nil
+	}
+	return mismatch
+}
+
+// HasFootprintWhitelist reports whether a transaction has been whitelisted as
+// a known footprint mismatch.
+func HasFootprintWhitelist(db ethdb.KeyValueReader, txHash common.Hash) bool {
+	ok, _ := db.Has(footprintWhitelistKey(txHash))
+	return ok
+}
+
+// WriteFootprintWhitelist marks a transaction as a known footprint mismatch.
+func WriteFootprintWhitelist(db ethdb.KeyValueWriter, txHash common.Hash) {
+	if err := db.Put(footprintWhitelistKey(txHash), []byte{}); err != nil {
+		log.Crit("Failed to store footprint whitelist entry", "err", err)
+	}
+}
+
+// DeleteFootprintWhitelist removes a transaction from the known footprint
+// mismatches.
+func DeleteFootprintWhitelist(db ethdb.KeyValueWriter, txHash common.Hash) {
+	if err := db.Delete(footprintWhitelistKey(txHash)); err != nil {
+		log.Crit("Failed to delete footprint whitelist entry", "err", err)
+	}
+}
+
+// ReadFootprintWhitelist retrieves all whitelisted transaction hashes.
+func ReadFootprintWhitelist(db ethdb.Iteratee) []common.Hash {
+	it := db.NewIterator(footprintWhitelistPrefix, nil)
+	defer it.Release()
+
+	var hashes []common.Hash
+	for it.Next() {
+		if key := it.Key(); len(key) == len(footprintWhitelistPrefix)+common.HashLength {
+			hashes = append(hashes, common.BytesToHash(key[len(footprintWhitelistPrefix):]))
+		}
+	}
+	return hashes
+}
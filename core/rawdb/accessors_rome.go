@@ -0,0 +1,78 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// romeBlockDataPrefix + num (uint64 big endian) + hash -> rome block data
+//
+// Rome block data is kept in the key-value store for the whole lifetime of the
+// chain instead of being migrated into the chain freezer: adding a table to an
+// existing freezer would truncate all of its tables to zero items. The freezer
+// only deletes the block components it knows about, so the entries survive
+// ancient migration untouched.
+var romeBlockDataPrefix = []byte("rome-block-")
+
+// romeBlockDataKey = romeBlockDataPrefix + num (uint64 big endian) + hash
+func romeBlockDataKey(number uint64, hash common.Hash) []byte {
+	return append(append(append([]byte{}, romeBlockDataPrefix...), encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// ReadRomeBlockDataRLP retrieves the RLP encoded Rome execution inputs of a block.
+func ReadRomeBlockDataRLP(db ethdb.KeyValueReader, hash common.Hash, number uint64) rlp.RawValue {
+	data, _ := db.Get(romeBlockDataKey(number, hash))
+	return data
+}
+
+// ReadRomeBlockData retrieves the Rome gas vectors and footprints a block was
+// executed with, or nil if they are not stored.
+func ReadRomeBlockData(db ethdb.KeyValueReader, hash common.Hash, number uint64) *types.RomeBlockData {
+	data := ReadRomeBlockDataRLP(db, hash, number)
+	if len(data) == 0 {
+		return nil
+	}
+	romeData := new(types.RomeBlockData)
+	if err := rlp.DecodeBytes(data, romeData); err != nil {
+		log.Error("Invalid Rome block data RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return romeData
+}
+
+// WriteRomeBlockData stores the Rome gas vectors and footprints of a block.
+func WriteRomeBlockData(db ethdb.KeyValueWriter, hash common.Hash, number uint64, romeData *types.RomeBlockData) {
+	data, err := rlp.EncodeToBytes(romeData)
+	if err != nil {
+		log.Crit("Failed to RLP encode Rome block data", "err", err)
+	}
+	if err := db.Put(romeBlockDataKey(number, hash), data); err != nil {
+		log.Crit("Failed to store Rome block data", "err", err)
+	}
+}
+
+// DeleteRomeBlockData removes the Rome execution inputs associated with a block.
+func DeleteRomeBlockData(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(romeBlockDataKey(number, hash)); err != nil {
+		log.Crit("Failed to delete Rome block data", "err", err)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that Rome block data can be stored, retrieved and deleted.
+func TestRomeBlockDataStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	hash, number := common.Hash{0: 0x01}, uint64(42)
+	if entry := ReadRomeBlockData(db, hash, number); entry != nil {
+		t.Fatalf("Non existent Rome block data returned: %v", entry)
+	}
+	data := &types.RomeBlockData{
+		GasUsed:    []uint64{21000, 53000},
+		GasPrice:   []uint64{7, 11},
+		Footprints: []string{"0x0", common.Hash{0: 0xaa}.Hex()},
+	}
+	WriteRomeBlockData(db, hash, number, data)
+	if entry := ReadRomeBlockData(db, hash, number); entry == nil {
+		t.Fatalf("Stored Rome block data not found")
+	} else if !reflect.DeepEqual(entry, data) {
+		t.Fatalf("Retrieved Rome block data mismatch: have %v, want %v", entry, data)
+	}
+	if entry := ReadRomeBlockData(db, common.Hash{0: 0x02}, number); entry != nil {
+		t.Fatalf("Rome block data returned for unknown hash: %v", entry)
+	}
+	DeleteRomeBlockData(db, hash, number)
+	if entry := ReadRomeBlockData(db, hash, number); entry != nil {
+		t.Fatalf("Deleted Rome block data returned: %v", entry)
+	}
+}
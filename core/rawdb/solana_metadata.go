@@ -5,6 +5,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
 )
 
 var solanaTxMetadataPrefix = []byte("solana-tx-meta-")
@@ -35,3 +36,133 @@ func ReadSolanaTxMetadata(db ethdb.KeyValueReader, txHash common.Hash) (uint64,
 	timestamp := int64(binary.BigEndian.Uint64(enc[8:]))
 	return slot, timestamp, true
 }
+
+// solanaBlockContextPrefix + num (uint64 big endian) + hash -> latest solana slot and timestamp known at the block
+var solanaBlockContextPrefix = []byte("solana-block-ctx-")
+
+// solanaBlockContextKey = solanaBlockContextPrefix + num (uint64 big endian) + hash
+func solanaBlockContextKey(number uint64, hash common.Hash) []byte {
+	return append(append(append([]byte{}, solanaBlockContextPrefix...), encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// WriteSolanaBlockContext stores the latest Solana slot and timestamp known at
+// a block, taken from its own transactions or inherited from its ancestors.
+func WriteSolanaBlockContext(db ethdb.KeyValueWriter, hash common.Hash, number uint64, slot uint64, timestamp int64) {
+	var enc [16]byte
+	binary.BigEndian.PutUint64(enc[:8], slot)
+	binary.BigEndian.PutUint64(enc[8:], uint64(timestamp))
+	if err := db.Put(solanaBlockContextKey(number, hash), enc[:]); err != nil {
+		log.Crit("Failed to store solana block context", "err", err)
+	}
+}
+
+// ReadSolanaBlockContext retrieves the latest Solana slot and timestamp known at
+// a block.
+func ReadSolanaBlockContext(db ethdb.KeyValueReader, hash common.Hash, number uint64) (uint64, int64, bool) {
+	enc, err := db.Get(solanaBlockContextKey(number, hash))
+	if err != nil || len(enc) != 16 {
+		return 0, 0, false
+	}
+	return binary.BigEndian.Uint64(enc[:8]), int64(binary.BigEndian.Uint64(enc[8:])), true
+}
+
+// DeleteSolanaBlockContext removes the Solana slot and timestamp known at a block.
+func DeleteSolanaBlockContext(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(solanaBlockContextKey(number, hash)); err != nil {
+		log.Crit("Failed to delete solana block context", "err", err)
+	}
+}
+
+// solanaSlotIndexPrefix + slot (uint64 big endian) + tx hash -> solana slot index entry
+var solanaSlotIndexPrefix = []byte("solana-slot-")
+
+// SolanaSlotEntry is a transaction executed in a Solana slot, as recorded in
+// the slot index.
+type SolanaSlotEntry struct {
+	Slot        uint64
+	TxHash      common.Hash
+	BlockNumber uint64
+	BlockHash   common.Hash
+	TxIndex     uint64
+}
+
+// solanaSlotIndexKey = solanaSlotIndexPrefix + slot (uint64 big endian) + tx hash
+func solanaSlotIndexKey(slot uint64, txHash common.Hash) []byte {
+	return append(append(append([]byte{}, solanaSlotIndexPrefix...), encodeBlockNumber(slot)...), txHash.Bytes()...)
+}
+
+// WriteSolanaSlotIndex stores the block position of a transaction executed in
+// the given Solana slot, replacing any previous position of it.
+func WriteSolanaSlotIndex(db ethdb.KeyValueWriter, entry *SolanaSlotEntry) {
+	enc := make([]byte, 8+common.HashLength+8)
+	binary.BigEndian.PutUint64(enc[:8], entry.BlockNumber)
+	copy(enc[8:], entry.BlockHash.Bytes())
+	binary.BigEndian.PutUint64(enc[8+common.HashLength:], entry.TxIndex)
+	if err := db.Put(solanaSlotIndexKey(entry.Slot, entry.TxHash), enc); err != nil {
+		log.Crit("Failed to store solana slot index", "err", err)
+	}
+}
+
+// DeleteSolanaSlotIndex removes a transaction from the Solana slot index.
+func DeleteSolanaSlotIndex(db ethdb.KeyValueWriter, slot uint64, txHash common.Hash) {
+	if err := db.Delete(solanaSlotIndexKey(slot, txHash)); err != nil {
+		log.Crit("Failed to delete solana slot index", "err", err)
+	}
+}
+
+// IterateSolanaSlotIndex calls fn for every indexed transaction executed in the
+// Solana slots [from, to], in ascending slot order. Transactions of blocks that
+// are no longer canonical are skipped. The iteration stops as soon as fn
+// returns false.
+func IterateSolanaSlotIndex(db ethdb.Database, from, to uint64, fn func(*SolanaSlotEntry) bool) {
+	it := db.NewIterator(solanaSlotIndexPrefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	for it.Next() {
+		key, enc := it.Key(), it.Value()
+		if len(key) != len(solanaSlotIndexPrefix)+8+common.HashLength || len(enc) != 8+common.HashLength+8 {
+			continue
+		}
+		entry := &SolanaSlotEntry{
+			Slot:        binary.BigEndian.Uint64(key[len(solanaSlotIndexPrefix):]),
+			TxHash:      common.BytesToHash(key[len(solanaSlotIndexPrefix)+8:]),
+			BlockNumber: binary.BigEndian.Uint64(enc[:8]),
+			BlockHash:   common.BytesToHash(enc[8 : 8+common.HashLength]),
+			TxIndex:     binary.BigEndian.Uint64(enc[8+common.HashLength:]),
+		}
+		if entry.Slot > to {
+			return
+		}
+		if ReadCanonicalHash(db, entry.BlockNumber) != entry.BlockHash {
+			continue
+		}
+		if !fn(entry) {
+			return
+		}
+	}
+}
+
+// solanaBlockhashPrefix + slot (uint64 big endian) -> solana blockhash
+var solanaBlockhashPrefix = []byte("solana-bh-")
+
+// solanaBlockhashKey = solanaBlockhashPrefix + slot (uint64 big endian)
+func solanaBlockhashKey(slot uint64) []byte {
+	return append(append([]byte{}, solanaBlockhashPrefix...), encodeBlockNumber(slot)...)
+}
+
+// ReadSolanaBlockhash retrieves the blockhash of a Solana slot, or the zero hash
+// if it is not known.
+func ReadSolanaBlockhash(db ethdb.KeyValueReader, slot uint64) common.Hash {
+	data, _ := db.Get(solanaBlockhashKey(slot))
+	if len(data) != common.HashLength {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteSolanaBlockhash stores the blockhash of a Solana slot.
+func WriteSolanaBlockhash(db ethdb.KeyValueWriter, slot uint64, hash common.Hash) {
+	if err := db.Put(solanaBlockhashKey(slot), hash.Bytes()); err != nil {
+		log.Crit("Failed to store solana blockhash", "err", err)
+	}
+}
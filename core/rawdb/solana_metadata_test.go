@@ -0,0 +1,64 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests that the Solana slot index can be stored, iterated and deleted, and
+// that entries of non-canonical blocks are skipped.
+func TestSolanaSlotIndex(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	canonical := []*SolanaSlotEntry{
+		{Slot: 300, TxHash: common.Hash{0x03}, BlockNumber: 3, BlockHash: common.Hash{0xb3}, TxIndex: 0},
+		{Slot: 100, TxHash: common.Hash{0x01}, BlockNumber: 1, BlockHash: common.Hash{0xb1}, TxIndex: 0},
+		{Slot: 100, TxHash: common.Hash{0x02}, BlockNumber: 1, BlockHash: common.Hash{0xb1}, TxIndex: 1},
+		{Slot: 256, TxHash: common.Hash{0x04}, BlockNumber: 2, BlockHash: common.Hash{0xb2}, TxIndex: 0},
+	}
+	for _, entry := range canonical {
+		WriteCanonicalHash(db, entry.BlockHash, entry.BlockNumber)
+		WriteSolanaSlotIndex(db, entry)
+	}
+	WriteSolanaSlotIndex(db, &SolanaSlotEntry{Slot: 256, TxHash: common.Hash{0x05}, BlockNumber: 2, BlockHash: common.Hash{0xc2}})
+
+	collect := func(from, to uint64) []*SolanaSlotEntry {
+		var entries []*SolanaSlotEntry
+		IterateSolanaSlotIndex(db, from, to, func(entry *SolanaSlotEntry) bool {
+			entries = append(entries, entry)
+			return true
+		})
+		return entries
+	}
+	if have, want := collect(0, 1000), []*SolanaSlotEntry{canonical[1], canonical[2], canonical[3], canonical[0]}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("full range mismatch: have %v, want %v", have, want)
+	}
+	if have, want := collect(101, 299), []*SolanaSlotEntry{canonical[3]}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("partial range mismatch: have %v, want %v", have, want)
+	}
+	if have := collect(301, 1000); len(have) != 0 {
+		t.Fatalf("empty range returned entries: %v", have)
+	}
+	DeleteSolanaSlotIndex(db, 100, common.Hash{0x01})
+	if have, want := collect(100, 100), []*SolanaSlotEntry{canonical[2]}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("range after deletion mismatch: have %v, want %v", have, want)
+	}
+}
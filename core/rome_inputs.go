@@ -0,0 +1,172 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// errRomeInputsStaged is returned if the Rome inputs of a block are staged while
+// a different set is already staged for it.
+var errRomeInputsStaged = errors.New("rome inputs already staged")
+
+// RomeInputs are the execution inputs of a Rome block that are not part of the
+// block itself, as retrieved from the network for a block yet to be imported.
+type RomeInputs struct {
+	Data        *types.RomeBlockData            // Rome gas vectors and footprints
+	SolanaTxs   map[common.Hash]SolanaTxContext // Solana context of the transactions that have one
+	Blockhashes map[uint64]common.Hash          // Solana blockhashes in reach of BLOCKHASH
+}
+
+// SolanaTxContext is the Solana slot and timestamp a transaction was executed at.
+type SolanaTxContext struct {
+	Slot      uint64
+	Timestamp int64
+}
+
+// romeStage holds the Rome inputs of blocks yet to be imported. The inputs are
+// served to the execution of the blocks, but only persisted once a block is
+// imported, so that inputs failing to reproduce the state root never reach the
+// database.
+type romeStage struct {
+	blocks   map[common.Hash]*RomeInputs     // Staged inputs by block hash
+	txs      map[common.Hash]SolanaTxContext // Solana context of the staged transactions
+	txRefs   map[common.Hash]int             // Number of staged blocks with each transaction
+	hashes   map[uint64]common.Hash          // Staged Solana blockhashes
+	hashRefs map[uint64]int                  // Number of staged blocks with each blockhash
+	lock     sync.RWMutex
+}
+
+func newRomeStage() *romeStage {
+	return &romeStage{
+		blocks:   make(map[common.Hash]*RomeInputs),
+		txs:      make(map[common.Hash]SolanaTxContext),
+		txRefs:   make(map[common.Hash]int),
+		hashes:   make(map[uint64]common.Hash),
+		hashRefs: make(map[uint64]int),
+	}
+}
+
+// add stages the inputs of a block. Inputs contradicting the persisted Solana
+// blockhashes or the ones staged for other blocks are rejected.
+func (s *romeStage) add(db ethdb.KeyValueReader, hash common.Hash, inputs *RomeInputs) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.blocks[hash]; ok {
+		return errRomeInputsStaged
+	}
+	for slot, blockhash := range inputs.Blockhashes {
+		if known := rawdb.ReadSolanaBlockhash(db, slot); known != (common.Hash{}) && known != blockhash {
+			return fmt.Errorf("conflicting blockhash for solana slot %d: have %x, known %x", slot, blockhash, known)
+		}
+		if staged, ok := s.hashes[slot]; ok && staged != blockhash {
+			return fmt.Errorf("conflicting blockhash for solana slot %d: have %x, staged %x", slot, blockhash, staged)
+		}
+	}
+	for tx, context := range inputs.SolanaTxs {
+		if staged, ok := s.txs[tx]; ok && staged != context {
+			return fmt.Errorf("conflicting solana context for transaction %x", tx)
+		}
+	}
+	s.blocks[hash] = inputs
+	for tx, context := range inputs.SolanaTxs {
+		s.txs[tx] = context
+		s.txRefs[tx]++
+	}
+	for slot, blockhash := range inputs.Blockhashes {
+		s.hashes[slot] = blockhash
+		s.hashRefs[slot]++
+	}
+	return nil
+}
+
+// remove drops the staged inputs of a block, returning them if there were any.
+func (s *romeStage) remove(hash common.Hash) *RomeInputs {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	inputs, ok := s.blocks[hash]
+	if !ok {
+		return nil
+	}
+	delete(s.blocks, hash)
+	for tx := range inputs.SolanaTxs {
+		if s.txRefs[tx]--; s.txRefs[tx] == 0 {
+			delete(s.txs, tx)
+			delete(s.txRefs, tx)
+		}
+	}
+	for slot := range inputs.Blockhashes {
+		if s.hashRefs[slot]--; s.hashRefs[slot] == 0 {
+			delete(s.hashes, slot)
+			delete(s.hashRefs, slot)
+		}
+	}
+	return inputs
+}
+
+// setData stages the Rome gas vectors and footprints a block is executed with,
+// replacing the ones retrieved from the network, if any.
+func (s *romeStage) setData(hash common.Hash, data *types.RomeBlockData) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if inputs, ok := s.blocks[hash]; ok {
+		staged := *inputs
+		staged.Data = data
+		s.blocks[hash] = &staged
+		return
+	}
+	s.blocks[hash] = &RomeInputs{Data: data}
+}
+
+// data retrieves the staged Rome gas vectors and footprints of a block.
+func (s *romeStage) data(hash common.Hash) *types.RomeBlockData {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if inputs, ok := s.blocks[hash]; ok {
+		return inputs.Data
+	}
+	return nil
+}
+
+// tx retrieves the staged Solana context of a transaction.
+func (s *romeStage) tx(hash common.Hash) (SolanaTxContext, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	context, ok := s.txs[hash]
+	return context, ok
+}
+
+// blockhash retrieves a staged Solana blockhash.
+func (s *romeStage) blockhash(slot uint64) (common.Hash, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	hash, ok := s.hashes[slot]
+	return hash, ok
+}
@@ -0,0 +1,193 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that staged Rome inputs are served to the import of their block and
+// only persisted once it imports.
+func TestStageRomeInputs(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.LatestSigner(params.TestChainConfig)
+		gspec  = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		}
+	)
+	db := rawdb.NewMemoryDatabase()
+	chain, err := NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 2, func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr), common.Address{0x01}, big.NewInt(1), params.TxGas, gen.header.BaseFee, nil), signer, key)
+		gen.AddTxWithChain(chain, tx)
+	})
+
+	tx := blocks[0].Transactions()[0].Hash()
+	if err := chain.StageRomeInputs(blocks[0].Hash(), &RomeInputs{
+		SolanaTxs:   map[common.Hash]SolanaTxContext{tx: {Slot: 300, Timestamp: 1700000000}},
+		Blockhashes: map[uint64]common.Hash{300: {0x03}},
+	}); err != nil {
+		t.Fatalf("failed to stage inputs: %v", err)
+	}
+	if err := chain.StageRomeInputs(blocks[1].Hash(), &RomeInputs{
+		Blockhashes: map[uint64]common.Hash{300: {0x04}},
+	}); err == nil {
+		t.Fatal("conflicting blockhash staged")
+	}
+	if _, _, ok := rawdb.ReadSolanaTxMetadata(db, tx); ok {
+		t.Fatal("staged solana context persisted before import")
+	}
+	if slot, _, ok := chain.GetSolanaTxMetadata(tx); !ok || slot != 300 {
+		t.Fatalf("staged solana context not served: have %d/%v", slot, ok)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	if slot, timestamp, ok := rawdb.ReadSolanaTxMetadata(db, tx); !ok || slot != 300 || timestamp != 1700000000 {
+		t.Fatalf("solana context not persisted: have %d/%d/%v", slot, timestamp, ok)
+	}
+	if hash := rawdb.ReadSolanaBlockhash(db, 300); hash != (common.Hash{0x03}) {
+		t.Fatalf("blockhash not persisted: have %x", hash)
+	}
+	if len(chain.romeStage.blocks) != 0 || len(chain.romeStage.hashes) != 0 {
+		t.Fatal("persisted inputs left staged")
+	}
+}
+
+// Tests that the Rome execution inputs of a block are persisted along with it and
+// deleted when the chain is rewound below it.
+func TestRomeBlockDataLifecycle(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.LatestSigner(params.TestChainConfig)
+		gspec  = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		}
+	)
+	db := rawdb.NewMemoryDatabase()
+	chain, err := NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 1, func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr), common.Address{0x01}, big.NewInt(1), params.TxGas, gen.header.BaseFee, nil), signer, key)
+		gen.AddTxWithChain(chain, tx)
+	})
+	block := blocks[0]
+	if err := chain.InsertBlockWithoutSetHead(context.Background(), block, []uint64{0}, []string{"00"}, []uint64{0}, false); err != nil {
+		t.Fatalf("failed to insert block: %v", err)
+	}
+	if data := rawdb.ReadRomeBlockData(db, block.Hash(), block.NumberU64()); data == nil || len(data.Footprints) != 1 {
+		t.Fatalf("rome block data not persisted: have %v", data)
+	}
+	if len(chain.romeStage.blocks) != 0 {
+		t.Fatal("persisted rome block data left staged")
+	}
+	if _, err := chain.SetCanonical(block); err != nil {
+		t.Fatalf("failed to set canonical head: %v", err)
+	}
+	if err := chain.SetHead(0); err != nil {
+		t.Fatalf("failed to rewind chain: %v", err)
+	}
+	if data := rawdb.ReadRomeBlockData(db, block.Hash(), block.NumberU64()); data != nil {
+		t.Fatalf("rome block data left after rewind: have %v", data)
+	}
+}
+
+// Tests that the Solana slot index only covers the canonical chain, written when
+// a block becomes canonical and deleted when the chain is rewound below it.
+func TestSolanaSlotIndexLifecycle(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.LatestSigner(params.TestChainConfig)
+		gspec  = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		}
+	)
+	db := rawdb.NewMemoryDatabase()
+	chain, err := NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 1, func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr), common.Address{0x01}, big.NewInt(1), params.TxGas, gen.header.BaseFee, nil), signer, key)
+		gen.AddTxWithChain(chain, tx)
+	})
+	block := blocks[0]
+	rawdb.WriteSolanaTxMetadata(db, block.Transactions()[0].Hash(), 300, 1700000000)
+
+	// Count the raw index entries, stale ones are skipped by the iterator
+	entries := func() int {
+		it := db.NewIterator([]byte("solana-slot-"), nil)
+		defer it.Release()
+
+		var count int
+		for it.Next() {
+			count++
+		}
+		return count
+	}
+	if err := chain.InsertBlockWithoutSetHead(context.Background(), block, []uint64{0}, []string{"00"}, []uint64{0}, false); err != nil {
+		t.Fatalf("failed to insert block: %v", err)
+	}
+	if have := entries(); have != 0 {
+		t.Fatalf("non-canonical block indexed: have %d entries", have)
+	}
+	if _, err := chain.SetCanonical(block); err != nil {
+		t.Fatalf("failed to set canonical head: %v", err)
+	}
+	var indexed []*rawdb.SolanaSlotEntry
+	rawdb.IterateSolanaSlotIndex(db, 0, 1000, func(entry *rawdb.SolanaSlotEntry) bool {
+		indexed = append(indexed, entry)
+		return true
+	})
+	if len(indexed) != 1 || indexed[0].Slot != 300 || indexed[0].BlockHash != block.Hash() {
+		t.Fatalf("canonical block index mismatch: have %v", indexed)
+	}
+	if err := chain.SetHead(0); err != nil {
+		t.Fatalf("failed to rewind chain: %v", err)
+	}
+	if have := entries(); have != 0 {
+		t.Fatalf("slot index left after rewind: have %d entries", have)
+	}
+}
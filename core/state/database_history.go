@@ -0,0 +1,142 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/triedb/pathdb"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+)
+
+// errHistoricState is returned if a historical state is attempted to be
+// mutated, hashed or proven.
+var errHistoricState = errors.New("not supported by historical state")
+
+// historicDB is a state database serving the historical states, which are no
+// longer available in the path-based trie database, from the state histories.
+// The historical states can be read and executed on top of, but not committed.
+type historicDB struct {
+	Database
+	limit uint64 // Maximum number of state histories read per opened state
+}
+
+// NewHistoricDatabase wraps the given state database, opening the states from
+// the state histories of its path-based trie database. Each opened state reads
+// at most limit state histories (0 = unlimited).
+func NewHistoricDatabase(db Database, limit uint64) Database {
+	return &historicDB{Database: db, limit: limit}
+}
+
+// OpenTrie opens the historical state with the given root.
+func (db *historicDB) OpenTrie(root common.Hash) (Trie, error) {
+	reader, err := db.TrieDB().HistoricReader(root, db.limit)
+	if err != nil {
+		return nil, err
+	}
+	return &historicTrie{root: root, reader: reader, triedb: db.TrieDB()}, nil
+}
+
+// OpenStorageTrie returns the historical state itself, which serves the storage
+// of all accounts.
+func (db *historicDB) OpenStorageTrie(stateRoot common.Hash, address common.Address, root common.Hash, self Trie) (Trie, error) {
+	if self == nil {
+		return db.OpenTrie(stateRoot)
+	}
+	return self, nil
+}
+
+// CopyTrie returns the given historical state, which is immutable.
+func (db *historicDB) CopyTrie(t Trie) Trie {
+	return t
+}
+
+// historicTrie implements the read accesses of the Trie interface over the
+// flat historical state.
+type historicTrie struct {
+	root   common.Hash
+	reader *pathdb.HistoricReader
+	triedb *trie.Database
+}
+
+// GetKey returns the preimage of a hashed key.
+func (t *historicTrie) GetKey(key []byte) []byte {
+	return t.triedb.Preimage(common.BytesToHash(key))
+}
+
+// GetAccount retrieves the account with the given address in the historical
+// state. Nil is returned if the account was not present.
+func (t *historicTrie) GetAccount(address common.Address) (*types.StateAccount, error) {
+	blob, err := t.reader.Account(address)
+	if len(blob) == 0 || err != nil {
+		return nil, err
+	}
+	return types.FullAccount(blob)
+}
+
+// GetStorage retrieves the storage slot of the account in the historical state.
+func (t *historicTrie) GetStorage(addr common.Address, key []byte) ([]byte, error) {
+	blob, err := t.reader.Storage(addr, crypto.Keccak256Hash(key))
+	if len(blob) == 0 || err != nil {
+		return nil, err
+	}
+	_, content, _, err := rlp.Split(blob)
+	return content, err
+}
+
+func (t *historicTrie) UpdateAccount(address common.Address, account *types.StateAccount) error {
+	return errHistoricState
+}
+
+func (t *historicTrie) UpdateStorage(addr common.Address, key, value []byte) error {
+	return errHistoricState
+}
+
+func (t *historicTrie) DeleteAccount(address common.Address) error {
+	return errHistoricState
+}
+
+func (t *historicTrie) DeleteStorage(addr common.Address, key []byte) error {
+	return errHistoricState
+}
+
+func (t *historicTrie) UpdateContractCode(address common.Address, codeHash common.Hash, code []byte) error {
+	return errHistoricState
+}
+
+// Hash returns the root of the historical state, which can not be mutated.
+func (t *historicTrie) Hash() common.Hash {
+	return t.root
+}
+
+func (t *historicTrie) Commit(collectLeaf bool) (common.Hash, *trienode.NodeSet, error) {
+	return common.Hash{}, nil, errHistoricState
+}
+
+func (t *historicTrie) NodeIterator(startKey []byte) (trie.NodeIterator, error) {
+	return nil, errHistoricState
+}
+
+func (t *historicTrie) Prove(key []byte, proofDb ethdb.KeyValueWriter) error {
+	return errHistoricState
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/triedb/pathdb"
+)
+
+// Tests that the historical states, flushed out of the path-based trie database,
+// are served from the state histories.
+func TestHistoricState(t *testing.T) {
+	disk, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer disk.Close()
+
+	var (
+		tdb   = trie.NewDatabase(disk, &trie.Config{PathDB: pathdb.Defaults})
+		sdb   = NewDatabaseWithNodeDB(disk, tdb)
+		addrs = []common.Address{{0x01}, {0x02}, {0x03}}
+		slot  = common.Hash{0xaa}
+		roots []common.Hash
+		root  = types.EmptyRootHash
+	)
+	defer tdb.Close()
+
+	// Mutate the accounts in every block, destructing the second one in the
+	// middle and resurrecting it afterwards.
+	for block := uint64(1); block <= 20; block++ {
+		state, err := New(root, sdb, nil)
+		if err != nil {
+			t.Fatalf("failed to open state %d: %v", block, err)
+		}
+		for i, addr := range addrs {
+			if i == 1 && block >= 8 && block < 12 {
+				if block == 8 {
+					state.SelfDestruct(addr)
+				}
+				continue
+			}
+			state.SetBalance(addr, big.NewInt(int64(block*10)+int64(i)))
+			state.SetState(addr, slot, common.BigToHash(big.NewInt(int64(block))))
+		}
+		if root, err = state.Commit(block, true); err != nil {
+			t.Fatalf("failed to commit state %d: %v", block, err)
+		}
+		roots = append(roots, root)
+	}
+	// Flush all states into the disk, leaving the old ones only available
+	// from the state histories.
+	if err := tdb.Commit(root, false); err != nil {
+		t.Fatalf("failed to flush states: %v", err)
+	}
+	if _, err := New(roots[0], sdb, nil); err == nil {
+		t.Fatal("historical state available in live database")
+	}
+	historic := NewHistoricDatabase(sdb, 0)
+	for i, root := range roots {
+		block := uint64(i + 1)
+		state, err := New(root, historic, nil)
+		if err != nil {
+			t.Fatalf("failed to open historical state %d: %v", block, err)
+		}
+		for j, addr := range addrs {
+			var (
+				balance = big.NewInt(int64(block*10) + int64(j))
+				value   = common.BigToHash(big.NewInt(int64(block)))
+			)
+			if j == 1 && block >= 8 && block < 12 {
+				balance, value = new(big.Int), common.Hash{}
+				if state.Exist(addr) {
+					t.Errorf("state %d: destructed account %x exists", block, addr)
+				}
+			}
+			if have := state.GetBalance(addr); have.Cmp(balance) != 0 {
+				t.Errorf("state %d: account %x balance mismatch: have %v, want %v", block, addr, have, balance)
+			}
+			if have := state.GetState(addr, slot); have != value {
+				t.Errorf("state %d: account %x slot mismatch: have %x, want %x", block, addr, have, value)
+			}
+		}
+		if err := state.Error(); err != nil {
+			t.Fatalf("state %d: unexpected error: %v", block, err)
+		}
+	}
+	// Reading beyond the state history limit of a historical state fails it
+	limited, err := New(roots[0], NewHistoricDatabase(sdb, 1), nil)
+	if err != nil {
+		t.Fatalf("failed to open limited historical state: %v", err)
+	}
+	limited.GetBalance(addrs[0])
+	limited.GetState(addrs[0], slot)
+	if err := limited.Error(); err == nil {
+		t.Fatal("historical state read beyond its limit")
+	}
+	// Historical states can't be committed
+	state, _ := New(roots[0], historic, nil)
+	state.SetBalance(addrs[0], big.NewInt(1))
+	if _, err := state.Commit(21, true); err == nil {
+		t.Fatal("historical state committed")
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// FootprintSlot is a storage slot included in an account footprint.
+type FootprintSlot struct {
+	Key   common.Hash
+	Value common.Hash
+}
+
+// FootprintAccount is the footprint of a single account touched by a
+// transaction, along with the components its hash was computed from.
+type FootprintAccount struct {
+	Address    common.Address
+	Nonce      uint64
+	Balance    *big.Int
+	CodeHash   common.Hash
+	CodeLength int
+	Slots      []FootprintSlot // Touched storage slots, sorted by key
+	Hash       common.Hash     // Hash of the account footprint preimage
+}
+
+// String returns the textual dump of the account footprint.
+func (a *FootprintAccount) String() string {
+	var (
+		b  strings.Builder
+		nb [8]byte
+		bb [32]byte
+	)
+	binary.LittleEndian.PutUint64(nb[:], a.Nonce)
+	a.Balance.FillBytes(bb[:])
+
+	b.WriteString(fmt.Sprintf("Address: %s\n", a.Address.Hex()))
+	b.WriteString(fmt.Sprintf("  Nonce: %d => %x\n", a.Nonce, nb))
+	b.WriteString(fmt.Sprintf("  Balance: %s => %x\n", a.Balance.String(), bb))
+	b.WriteString(fmt.Sprintf("  Code Length: %d\n", a.CodeLength))
+	b.WriteString(fmt.Sprintf("  Storage Slots (%d):\n", len(a.Slots)))
+	for _, slot := range a.Slots {
+		b.WriteString(fmt.Sprintf("    %s: %x\n", slot.Key.Hex(), slot.Value))
+	}
+	b.WriteString(fmt.Sprintf("  Account Hash: %x\n", a.Hash))
+	return b.String()
+}
+
+// FootprintHasher is a versioned scheme deriving the state footprint of a
+// transaction from the accounts and storage slots it touched. The scheme must
+// match the one of Rome-EVM, upgrades are scheduled through the chain config.
+type FootprintHasher interface {
+	// Version returns the version of the footprint scheme.
+	Version() uint64
+
+	// Excluded reports whether the account is left out of the footprint.
+	Excluded(addr common.Address) bool
+
+	// HashAccount computes the footprint of a single account given its code.
+	// The touched storage slots of the account are sorted by key.
+	HashAccount(account *FootprintAccount, code []byte) common.Hash
+
+	// Fold folds the account footprints, sorted by address, into the
+	// transaction footprint.
+	Fold(accounts []*FootprintAccount) common.Hash
+}
+
+// footprintHashers contains the supported footprint schemes by version.
+var footprintHashers = map[uint64]FootprintHasher{
+	1: footprintV1{},
+}
+
+// NewFootprintHasher returns the footprint scheme of the given version, or nil
+// if the version is not supported.
+func NewFootprintHasher(version uint64) FootprintHasher {
+	return footprintHashers[version]
+}
+
+// footprintV1 is the initial footprint scheme. The footprint of an account is
+// the keccak256 hash of its address, little endian nonce, 32 byte big endian
+// balance, code and touched storage values, precompiles and the hardhat and
+// foundry cheat code addresses excluded. The transaction footprint is the hash
+// of the account footprints concatenated.
+type footprintV1 struct{}
+
+func (footprintV1) Version() uint64 { return 1 }
+
+func (footprintV1) Excluded(addr common.Address) bool {
+	return IsMagicAddress(addr)
+}
+
+func (footprintV1) HashAccount(account *FootprintAccount, code []byte) common.Hash {
+	var pre []byte
+	pre = append(pre, account.Address.Bytes()...)
+	pre = binary.LittleEndian.AppendUint64(pre, account.Nonce)
+
+	var bb [32]byte
+	account.Balance.FillBytes(bb[:])
+	pre = append(pre, bb[:]...)
+	pre = append(pre, code...)
+
+	for _, slot := range account.Slots {
+		pre = append(pre, slot.Value[:]...)
+	}
+	return crypto.Keccak256Hash(pre)
+}
+
+func (footprintV1) Fold(accounts []*FootprintAccount) common.Hash {
+	fh := crypto.NewKeccakState()
+	for _, account := range accounts {
+		fh.Write(account.Hash[:])
+	}
+	var sum common.Hash
+	fh.Read(sum[:])
+	return sum
+}
+
+// TxFootprintAccounts computes the footprints of all accounts touched by the
+// journal entries from the provided start index (inclusive) to the current end,
+// plus the current transaction's touchedSlots. Accounts are sorted by address.
+func (s *StateDB) TxFootprintAccounts(start int, hasher FootprintHasher) []*FootprintAccount {
+	// 1) collect touched addresses from touchedSlots and journal entries since start
+	touched := make(map[common.Address]struct{}, len(s.touchedSlots))
+
+	// a) from touchedSlots (storage-only touches in this tx)
+	for addr := range s.touchedSlots {
+		if !hasher.Excluded(addr) {
+			touched[addr] = struct{}{}
+		}
+	}
+	// b) from relevant entry types in [start:]
+	if start < 0 {
+		start = 0
+	}
+	if start > len(s.journal.entries) {
+		start = len(s.journal.entries)
+	}
+	for i := start; i < len(s.journal.entries); i++ {
+		switch c := s.journal.entries[i].(type) {
+		case createObjectChange:
+			touched[*c.account] = struct{}{}
+		case resetObjectChange:
+			touched[*c.account] = struct{}{}
+		case selfDestructChange:
+			touched[*c.account] = struct{}{}
+		case balanceChange:
+			touched[*c.account] = struct{}{}
+		case nonceChange:
+			touched[*c.account] = struct{}{}
+		case storageChange:
+			touched[*c.account] = struct{}{}
+		case codeChange:
+			touched[*c.account] = struct{}{}
+		case touchChange:
+			touched[*c.account] = struct{}{}
+		}
+	}
+
+	// build and sort address list
+	addresses := make([]common.Address, 0, len(touched))
+	for addr := range touched {
+		if !hasher.Excluded(addr) {
+			addresses = append(addresses, addr)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+
+	// 2) build slot-sets from touchedSlots and journal entries since start
+	slots := make(map[common.Address]map[common.Hash]struct{}, len(addresses))
+	for addr, m := range s.touchedSlots {
+		if hasher.Excluded(addr) {
+			continue
+		}
+		cmap := make(map[common.Hash]struct{}, len(m))
+		for k := range m {
+			cmap[k] = struct{}{}
+		}
+		slots[addr] = cmap
+	}
+	for i := start; i < len(s.journal.entries); i++ {
+		switch c := s.journal.entries[i].(type) {
+		case storageChange:
+			addr := *c.account
+			if hasher.Excluded(addr) {
+				continue
+			}
+			if slots[addr] == nil {
+				slots[addr] = make(map[common.Hash]struct{})
+			}
+			slots[addr][c.key] = struct{}{}
+		case resetObjectChange:
+			addr := *c.account
+			if hasher.Excluded(addr) {
+				continue
+			}
+			if slots[addr] == nil {
+				slots[addr] = make(map[common.Hash]struct{})
+			}
+			for k := range c.prevStorage {
+				slots[addr][k] = struct{}{}
+			}
+		}
+	}
+
+	// 3) per-account hashing in parallel
+	accounts := make([]*FootprintAccount, len(addresses))
+	in := make(chan int, len(addresses))
+
+	var wg sync.WaitGroup
+	const workers = 10
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range in {
+				accounts[i] = s.footprintAccount(addresses[i], slots[addresses[i]], hasher)
+			}
+		}()
+	}
+	for i := range addresses {
+		in <- i
+	}
+	close(in)
+	wg.Wait()
+
+	return accounts
+}
+
+// footprintAccount computes the footprint of a single account over the given
+// set of storage slots.
+func (s *StateDB) footprintAccount(addr common.Address, slots map[common.Hash]struct{}, hasher FootprintHasher) *FootprintAccount {
+	account := &FootprintAccount{
+		Address: addr,
+		Nonce:   s.GetNonce(addr),
+		Balance: new(big.Int).Set(s.GetBalance(addr)),
+	}
+	code := s.GetCode(addr)
+	account.CodeHash = crypto.Keccak256Hash(code)
+	account.CodeLength = len(code)
+
+	keys := make([]common.Hash, 0, len(slots))
+	for k := range slots {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i][:], keys[j][:]) < 0
+	})
+	account.Slots = make([]FootprintSlot, 0, len(keys))
+	for _, k := range keys {
+		account.Slots = append(account.Slots, FootprintSlot{Key: k, Value: s.GetState(addr, k)})
+	}
+	account.Hash = hasher.HashAccount(account, code)
+	return account
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the structured footprint breakdown matches the footprint hash and
+// covers the touched accounts and slots only.
+func TestTxFootprintAccounts(t *testing.T) {
+	statedb, _ := New(types.EmptyRootHash, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	var (
+		a = common.Address{0xaa}
+		b = common.Address{0xbb}
+	)
+	start := statedb.JournalLength()
+	statedb.SetState(b, common.Hash{2}, common.Hash{0x22})
+	statedb.SetState(b, common.Hash{1}, common.Hash{0x11})
+	statedb.AddBalance(a, big.NewInt(1000))
+	statedb.SetNonce(a, 3)
+	statedb.AddBalance(common.BytesToAddress([]byte{1}), big.NewInt(1)) // ecrecover precompile, excluded
+
+	hasher := NewFootprintHasher(1)
+	accounts := statedb.TxFootprintAccounts(start, hasher)
+	if len(accounts) != 2 {
+		t.Fatalf("touched account count mismatch: have %d, want 2", len(accounts))
+	}
+	if accounts[0].Address != a || accounts[1].Address != b {
+		t.Fatalf("accounts not sorted by address: %v, %v", accounts[0].Address, accounts[1].Address)
+	}
+	if slots := accounts[1].Slots; len(slots) != 2 || slots[0].Key != (common.Hash{1}) || slots[1].Value != (common.Hash{0x22}) {
+		t.Fatalf("unexpected slots: %v", slots)
+	}
+	// Recompute the preimage of the first account by hand
+	var pre []byte
+	pre = append(pre, a.Bytes()...)
+	pre = binary.LittleEndian.AppendUint64(pre, 3)
+	pre = append(pre, common.BigToHash(big.NewInt(1000)).Bytes()...)
+	if want := crypto.Keccak256Hash(pre); accounts[0].Hash != want {
+		t.Fatalf("account hash mismatch: have %x, want %x", accounts[0].Hash, want)
+	}
+	hash, logs := statedb.CalculateTxFootPrint(start, hasher)
+	if hash != hasher.Fold(accounts) {
+		t.Fatalf("footprint mismatch: have %x, want %x", hash, hasher.Fold(accounts))
+	}
+	if len(logs) != 2 || logs[0] != accounts[0].String() {
+		t.Fatalf("unexpected footprint dump: %v", logs)
+	}
+}
+
+type footprintVector struct {
+	Version  uint64           `json:"version"`
+	Excluded []common.Address `json:"excluded"`
+	Included []common.Address `json:"included"`
+	Accounts []struct {
+		Name    string          `json:"name"`
+		Address common.Address  `json:"address"`
+		Nonce   hexutil.Uint64  `json:"nonce"`
+		Balance *hexutil.Big    `json:"balance"`
+		Code    hexutil.Bytes   `json:"code"`
+		Slots   []FootprintSlot `json:"slots"`
+		Hash    common.Hash     `json:"hash"`
+	} `json:"accounts"`
+	Transactions []struct {
+		Name      string      `json:"name"`
+		Accounts  []string    `json:"accounts"`
+		Footprint common.Hash `json:"footprint"`
+	} `json:"transactions"`
+}
+
+// Tests the footprint schemes against the test vectors shared with Rome-EVM.
+func TestFootprintVectors(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "footprint_v*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no footprint test vectors found")
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var vector footprintVector
+		if err := json.Unmarshal(data, &vector); err != nil {
+			t.Fatalf("%s: failed to decode vectors: %v", file, err)
+		}
+		hasher := NewFootprintHasher(vector.Version)
+		if hasher == nil {
+			t.Fatalf("%s: unsupported footprint version %d", file, vector.Version)
+		}
+		if hasher.Version() != vector.Version {
+			t.Fatalf("%s: version mismatch: have %d, want %d", file, hasher.Version(), vector.Version)
+		}
+		for _, addr := range vector.Excluded {
+			if !hasher.Excluded(addr) {
+				t.Errorf("%s: address %v not excluded", file, addr)
+			}
+		}
+		for _, addr := range vector.Included {
+			if hasher.Excluded(addr) {
+				t.Errorf("%s: address %v excluded", file, addr)
+			}
+		}
+		accounts := make(map[string]*FootprintAccount)
+		for _, want := range vector.Accounts {
+			account := &FootprintAccount{
+				Address: want.Address,
+				Nonce:   uint64(want.Nonce),
+				Balance: want.Balance.ToInt(),
+				Slots:   want.Slots,
+			}
+			account.Hash = hasher.HashAccount(account, want.Code)
+			if account.Hash != want.Hash {
+				t.Errorf("%s: account %s hash mismatch: have %x, want %x", file, want.Name, account.Hash, want.Hash)
+			}
+			accounts[want.Name] = account
+		}
+		for _, tx := range vector.Transactions {
+			var touched []*FootprintAccount
+			for _, name := range tx.Accounts {
+				touched = append(touched, accounts[name])
+			}
+			if have := hasher.Fold(touched); have != tx.Footprint {
+				t.Errorf("%s: transaction %s footprint mismatch: have %x, want %x", file, tx.Name, have, tx.Footprint)
+			}
+		}
+	}
+}
+
+// Tests that every footprint version accepted by the chain config checks has a
+// scheme implementing it.
+func TestFootprintHashers(t *testing.T) {
+	for version := uint64(1); version <= params.LatestFootprintVersion; version++ {
+		hasher := NewFootprintHasher(version)
+		if hasher == nil {
+			t.Fatalf("footprint version %d not implemented", version)
+		}
+		if hasher.Version() != version {
+			t.Fatalf("footprint version mismatch: have %d, want %d", hasher.Version(), version)
+		}
+	}
+	if NewFootprintHasher(params.LatestFootprintVersion+1) != nil {
+		t.Fatalf("footprint version %d implemented but unknown to the chain config", params.LatestFootprintVersion+1)
+	}
+}
@@ -18,13 +18,10 @@
 package state
 
 import (
-	"bytes"
-	"encoding/binary"
 	"fmt"
+	"maps"
 	"math/big"
 	"sort"
-	"strings"
-	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -323,6 +320,36 @@ func (s *StateDB) GetStorageRoot(addr common.Address) common.Hash {
 	return common.Hash{}
 }
 
+// CheckTransactionConditional verifies the known account constraints of the
+// conditional against the current state, including the storage changes of
+// the transactions applied so far.
+func (s *StateDB) CheckTransactionConditional(cond *types.TransactionConditional) error {
+	if cost := cond.Cost(); cost > params.TransactionConditionalMaxCost {
+		return fmt.Errorf("conditional cost %d exceeds maximum %d", cost, params.TransactionConditionalMaxCost)
+	}
+	for addr, account := range cond.KnownAccounts {
+		if account.StorageRoot != nil {
+			root := types.EmptyRootHash
+			if obj := s.getStateObject(addr); obj != nil {
+				// Hash the finalised storage changes into the trie first
+				if _, pending := s.stateObjectsPending[addr]; pending {
+					obj.updateRoot()
+				}
+				root = obj.Root()
+			}
+			if root != *account.StorageRoot {
+				return fmt.Errorf("storage root mismatch for %x: have %x, want %x", addr, root, *account.StorageRoot)
+			}
+		}
+		for key, want := range account.StorageSlots {
+			if have := s.GetState(addr, key); have != want {
+				return fmt.Errorf("storage slot %x mismatch for %x: have %x, want %x", key, addr, have, want)
+			}
+		}
+	}
+	return nil
+}
+
 // TxIndex returns the current transaction index set by Prepare.
 func (s *StateDB) TxIndex() int {
 	return s.txIndex
@@ -734,6 +761,7 @@ func (s *StateDB) Copy() *StateDB {
 		preimages:            make(map[common.Hash][]byte, len(s.preimages)),
 		journal:              newJournal(),
 		hasher:               crypto.NewKeccakState(),
+		touchedSlots:         make(map[common.Address]map[common.Hash]struct{}, len(s.touchedSlots)),
 
 		// In order for the block producer to be able to use and make additions
 		// to the snapshot tree, we need to copy that as well. Otherwise, any
@@ -742,6 +770,9 @@ func (s *StateDB) Copy() *StateDB {
 		snaps: s.snaps,
 		snap:  s.snap,
 	}
+	for addr, slots := range s.touchedSlots {
+		state.touchedSlots[addr] = maps.Clone(slots)
+	}
 	// Copy the dirty states, logs, and preimages
 	for addr := range s.journal.dirties {
 		// As documented [here](https://github.com/ethereum/go-ethereum/pull/16485#issuecomment-380438527),
@@ -1456,192 +1487,30 @@ func copy2DSet[k comparable](set map[k]map[common.Hash][]byte) map[k]map[common.
 
 // CalculateTxFootPrint computes the footprint considering only journal entries
 // from the provided start index (inclusive) to the current end, plus the current
-// transaction's touchedSlots.
-func (s *StateDB) CalculateTxFootPrint(start int) (common.Hash, []string) {
-    // 1) collect touched addresses from touchedSlots and journal entries since start
-    touched := make(map[common.Address]struct{}, len(s.touchedSlots))
-
-    // a) from touchedSlots (storage-only touches in this tx)
-    for addr := range s.touchedSlots {
-        if !IsMagicAddress(addr) {
-            touched[addr] = struct{}{}
-        }
-    }
-    // b) from relevant entry types in [start:]
-    if start < 0 {
-        start = 0
-    }
-    if start > len(s.journal.entries) {
-        start = len(s.journal.entries)
-    }
-	for i := start; i < len(s.journal.entries); i++ {
-        switch c := s.journal.entries[i].(type) {
-        case createObjectChange:
-            touched[*c.account] = struct{}{}
-        case resetObjectChange:
-            touched[*c.account] = struct{}{}
-        case selfDestructChange:
-            touched[*c.account] = struct{}{}
-        case balanceChange:
-            touched[*c.account] = struct{}{}
-        case nonceChange:
-            touched[*c.account] = struct{}{}
-        case storageChange:
-            touched[*c.account] = struct{}{}
-        case codeChange:
-            touched[*c.account] = struct{}{}
-        case touchChange:
-            touched[*c.account] = struct{}{}
-        }
-    }
-
-    // build and sort address list
-    addresses := make([]common.Address, 0, len(touched))
-    for addr := range touched {
-        if !IsMagicAddress(addr) {
-            addresses = append(addresses, addr)
-        }
-    }
-    sort.Slice(addresses, func(i, j int) bool {
-        return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
-    })
-
-    // 2) build slot-sets from touchedSlots and journal entries since start
-    slots := make(map[common.Address]map[common.Hash]struct{}, len(addresses))
-    for addr, m := range s.touchedSlots {
-        if IsMagicAddress(addr) {
-            continue
-        }
-        cmap := make(map[common.Hash]struct{}, len(m))
-        for k := range m {
-            cmap[k] = struct{}{}
-        }
-        slots[addr] = cmap
-    }
-    for i := start; i < len(s.journal.entries); i++ {
-        switch c := s.journal.entries[i].(type) {
-        case storageChange:
-            addr := *c.account
-            if IsMagicAddress(addr) {
-                continue
-            }
-            if slots[addr] == nil {
-                slots[addr] = make(map[common.Hash]struct{})
-            }
-            slots[addr][c.key] = struct{}{}
-        case resetObjectChange:
-            addr := *c.account
-            if IsMagicAddress(addr) {
-                continue
-            }
-            if slots[addr] == nil {
-                slots[addr] = make(map[common.Hash]struct{})
-            }
-            for k := range c.prevStorage {
-                slots[addr][k] = struct{}{}
-            }
-        }
-    }
-
-    // 3) per-account hashing in parallel 
-    type result struct {
-        idx  int
-        hash [32]byte
-        log  string
-    }
-
-    in := make(chan int, len(addresses))
-    out := make(chan result, len(addresses))
-
-    var wg sync.WaitGroup
-    const workers = 10
-    wg.Add(workers)
-    for w := 0; w < workers; w++ {
-        go func() {
-            defer wg.Done()
-            for i := range in {
-                addr := addresses[i]
-                var b strings.Builder
-                var pre []byte
-
-                b.WriteString(fmt.Sprintf("Address: %s\n", addr.Hex()))
-                pre = append(pre, addr.Bytes()...)
-                nonce := s.GetNonce(addr)
-                var nb [8]byte
-                binary.LittleEndian.PutUint64(nb[:], nonce)
-                pre = append(pre, nb[:]...)
-                b.WriteString(fmt.Sprintf("  Nonce: %d => %x\n", nonce, nb))
-
-                balBytes := s.GetBalance(addr).Bytes()
-                var bb [32]byte
-                copy(bb[32-len(balBytes):], balBytes)
-                pre = append(pre, bb[:]...)
-                b.WriteString(fmt.Sprintf("  Balance: %s => %x\n", new(big.Int).SetBytes(bb[:]).String(), bb))
-
-                code := s.GetCode(addr)
-                pre = append(pre, code...)
-                b.WriteString(fmt.Sprintf("  Code Length: %d\n", len(code)))
-
-                keys := make([]common.Hash, 0, len(slots[addr]))
-                for k := range slots[addr] {
-                    keys = append(keys, k)
-                }
-                sort.Slice(keys, func(i, j int) bool {
-                    return bytes.Compare(keys[i][:], keys[j][:]) < 0
-                })
-                b.WriteString(fmt.Sprintf("  Storage Slots (%d):\n", len(keys)))
-                for _, k := range keys {
-                    v := s.GetState(addr, k).Bytes()
-                    var vb [32]byte
-                    copy(vb[32-len(v):], v)
-                    pre = append(pre, vb[:]...)
-                    b.WriteString(fmt.Sprintf("    %s: %x\n", k.Hex(), vb))
-                }
-
-                h := crypto.Keccak256(pre)
-                var hh [32]byte
-                copy(hh[:], h)
-                b.WriteString(fmt.Sprintf("  Account Hash: %x\n", h))
-
-                out <- result{i, hh, b.String()}
-            }
-        }()
-    }
-
-    for i := range addresses {
-        in <- i
-    }
-    close(in)
-    go func() {
-        wg.Wait()
-        close(out)
-    }()
-
-    hashes := make([][]byte, len(addresses))
-    logs := make([]string, len(addresses))
-    for r := range out {
-        hashes[r.idx] = r.hash[:]
-        logs[r.idx] = r.log
-    }
+// transaction's touchedSlots, using the given footprint scheme.
+func (s *StateDB) CalculateTxFootPrint(start int, hasher FootprintHasher) (common.Hash, []string) {
+	accounts := s.TxFootprintAccounts(start, hasher)
+	logs := make([]string, len(accounts))
+	for i, account := range accounts {
+		logs[i] = account.String()
+	}
+	final := hasher.Fold(accounts)
 
-    // 4) final fold
-    fh := crypto.NewKeccakState()
-    for _, h := range hashes {
-        fh.Write(h)
-    }
-    var sum [32]byte
-    fh.Read(sum[:])
-    final := common.BytesToHash(sum[:])
+	log.Info("State Footprint Summary")
+	for _, l := range logs {
+		log.Info(l)
+	}
+	log.Info("Final Footprint Hash", "hash", final.Hex())
 
-    log.Info("State Footprint Summary")
-    for _, l := range logs {
-        log.Info(l)
-    }
-    log.Info("Final Footprint Hash", "hash", final.Hex())
+	// flush
+	s.ResetTxFootprint()
+	return final, logs
+}
 
-    // flush
-    s.touchedSlots = make(map[common.Address]map[common.Hash]struct{})
-    return final, logs
+// ResetTxFootprint discards the storage slots tracked for the footprint of the
+// current transaction.
+func (s *StateDB) ResetTxFootprint() {
+	s.touchedSlots = make(map[common.Address]map[common.Hash]struct{})
 }
 
 // IsMagicAddress returns true if the address is a precompile or other special
@@ -1189,3 +1189,45 @@ func TestDeleteStorage(t *testing.T) {
 		t.Fatalf("difference found:\nfast: %v\nslow: %v\n", fastRes, slowRes)
 	}
 }
+
+// Tests that transaction conditionals are checked against the storage changes
+// not yet hashed into the storage tries.
+func TestCheckTransactionConditional(t *testing.T) {
+	var (
+		addr  = common.Address{0xaa}
+		slot  = common.Hash{0x01}
+		state = NewDatabase(rawdb.NewMemoryDatabase())
+	)
+	sdb, _ := New(types.EmptyRootHash, state, nil)
+	sdb.SetNonce(addr, 1)
+	sdb.SetState(addr, slot, common.Hash{0x02})
+	sdb.Finalise(true)
+
+	cpy := sdb.Copy()
+	cpy.IntermediateRoot(true)
+	root := cpy.GetStorageRoot(addr)
+	if root == types.EmptyRootHash {
+		t.Fatal("storage root not updated")
+	}
+	empty, wrong := types.EmptyRootHash, common.Hash{0xff}
+	tests := []struct {
+		account types.KnownAccount
+		ok      bool
+	}{
+		{types.KnownAccount{StorageRoot: &root}, true},
+		{types.KnownAccount{StorageRoot: &wrong}, false},
+		{types.KnownAccount{StorageSlots: map[common.Hash]common.Hash{slot: {0x02}}}, true},
+		{types.KnownAccount{StorageSlots: map[common.Hash]common.Hash{slot: {0x03}}}, false},
+	}
+	for i, tt := range tests {
+		cond := &types.TransactionConditional{KnownAccounts: types.KnownAccounts{addr: tt.account}}
+		if err := sdb.CheckTransactionConditional(cond); (err == nil) != tt.ok {
+			t.Errorf("test %d: check mismatch: have %v, want ok %v", i, err, tt.ok)
+		}
+	}
+	// Non-existent accounts have an empty storage
+	cond := &types.TransactionConditional{KnownAccounts: types.KnownAccounts{{0xbb}: {StorageRoot: &empty}}}
+	if err := sdb.CheckTransactionConditional(cond); err != nil {
+		t.Errorf("empty storage root rejected: %v", err)
+	}
+}
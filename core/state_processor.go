@@ -26,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -68,7 +69,15 @@ func NewStateProcessor(config *params.ChainConfig, bc *BlockChain, engine consen
 // Process returns the receipts and logs accumulated during the process and
 // returns the amount of gas that was used in the process. If any of the
 // transactions failed to execute due to insufficient gas it will return an error.
-func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg vm.Config, romeGasUsed []uint64, romeGasPrice []uint64, footPrints []string) (types.Receipts, []*types.Log, uint64, error) {
+func (p *StateProcessor) Process(ctx context.Context, block *types.Block, statedb *state.StateDB, cfg vm.Config, romeGasUsed []uint64, romeGasPrice []uint64, footPrints []string) (types.Receipts, []*types.Log, uint64, error) {
+	ctx, span := log.GetTracer().Start(ctx, "Process",
+		trace.WithAttributes(
+			attribute.Int64("block_number", block.Number().Int64()),
+			attribute.String("block_hash", block.Hash().Hex()),
+			attribute.Int("txs", len(block.Transactions())),
+		))
+	defer span.End()
+
 	var (
 		receipts    types.Receipts
 		usedGas     = new(uint64)
@@ -89,6 +98,14 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		ProcessBeaconBlockRoot(*beaconRoot, vmenv, statedb)
 	}
 
+	// Blocks re-executed outside of the engine API (state regeneration, chain
+	// repair, imports) carry no Rome inputs, use the ones stored at insertion.
+	romeData := types.NewRomeBlockData(romeGasUsed, romeGasPrice, footPrints)
+	if len(romeGasUsed) < len(block.Transactions()) || len(romeGasPrice) < len(block.Transactions()) {
+		if stored := p.bc.GetRomeBlockData(block.Hash(), block.NumberU64()); stored != nil {
+			romeData = stored
+		}
+	}
 	for i, tx := range block.Transactions() {
 		statedb.SetTxContext(tx.Hash(), i)
 
@@ -100,17 +117,15 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 			solanaTimestamp = &ts
 		}
 
-		footPrint := "0x0"
-		if i < len(footPrints) {
-			footPrint = footPrints[i]
-		}
+		footPrint := romeData.TxFootprint(i)
 		if p.bc.GetFootprintManager() != nil {
 			if entry, found := p.bc.GetFootprintManager().Get(tx.Hash()); found {
 				footPrint = entry.ExpectedFootprint
 			}
 		}
 
-		receipt, err := ApplyTransactionWithSolana(p.config, p.bc, nil, gp, statedb, header, tx, usedGas, cfg, romeGasUsed[i], footPrint, romeGasPrice[i], solanaBlockNumber, solanaTimestamp)
+		txGasUsed, txGasPrice := romeData.TxGas(i)
+		receipt, err := ApplyTransactionWithSolana(ctx, p.config, p.bc, nil, gp, statedb, header, tx, usedGas, cfg, txGasUsed, footPrint, txGasPrice, solanaBlockNumber, solanaTimestamp)
 		if err != nil {
 			return nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
 		}
@@ -132,9 +147,9 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 	return receipts, allLogs, *usedGas, nil
 }
 
-func applyTransaction(msg *Message, config *params.ChainConfig, bc ChainContext, gp *GasPool, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, tx *types.Transaction, usedGas *uint64, evm *vm.EVM, romeGasUsed uint64, footPrint string, romeGasPrice uint64, solanaBlockNumber *uint64, solanaTimestamp *int64) (*types.Receipt, error) {
+func applyTransaction(ctx context.Context, msg *Message, config *params.ChainConfig, bc ChainContext, gp *GasPool, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, tx *types.Transaction, usedGas *uint64, evm *vm.EVM, romeGasUsed uint64, footPrint string, romeGasPrice uint64, solanaBlockNumber *uint64, solanaTimestamp *int64) (*types.Receipt, error) {
 	tracer := log.GetTracer()
-	_, span := tracer.Start(context.Background(), "applyTransaction",
+	_, span := tracer.Start(ctx, "applyTransaction",
 		trace.WithAttributes(
 			attribute.String("tx_hash", tx.Hash().Hex()),
 			attribute.String("timestamp", time.Now().Format(time.RFC3339Nano)),
@@ -163,7 +178,12 @@ func applyTransaction(msg *Message, config *params.ChainConfig, bc ChainContext,
 	log.Info("applyTransaction: submitted footPrint", "footPrint", footPrint, "txhash", tx.Hash().Hex())
 
 	// Calculate the state footprint after VM execution
-	vmState, logs := statedb.CalculateTxFootPrint(start)
+	version := config.FootprintVersion(evm.Context.Time)
+	hasher := state.NewFootprintHasher(version)
+	if hasher == nil {
+		return nil, fmt.Errorf("unsupported footprint version %d", version)
+	}
+	vmState, logs := statedb.CalculateTxFootPrint(start, hasher)
 	txHash := tx.Hash()
 	mismatch := vmState != common.HexToHash(footPrint)
 	manager := bc.GetFootprintManager()
@@ -172,24 +192,22 @@ func applyTransaction(msg *Message, config *params.ChainConfig, bc ChainContext,
 		if err := log.FlushLogs(logs); err != nil {
 			log.Error("failed to flush logs", "error", err)
 		}
-		if manager != nil && manager.IsKnownMismatch(txHash) {
-			log.Warn("state footprint mismatch",
-				"tx", txHash.Hex(),
-				"expected", footPrint,
-				"got", vmState.Hex())
-		} else {
+		if manager == nil {
 			log.Error("state footprint mismatch",
 				"tx", txHash.Hex(),
 				"expected", footPrint,
 				"got", vmState.Hex())
-
-			if manager != nil {
-				if err := manager.RecordMismatch(txHash); err != nil {
-					log.Error("Failed to record footprint mismatch", "tx", txHash.Hex(), "error", err)
-				}
-				if manager.ShouldPanic() {
-					panic("state footprint mismatch")
-				}
+		} else {
+			err := manager.HandleMismatch(&rawdb.FootprintMismatch{
+				TxHash:      txHash,
+				BlockNumber: blockNumber.Uint64(),
+				BlockHash:   blockHash,
+				Expected:    footPrint,
+				Actual:      vmState.Hex(),
+				Accounts:    logs,
+			}, evm.Config.EnforceFootprints && common.HexToHash(footPrint) != (common.Hash{}))
+			if err != nil {
+				return nil, err
 			}
 		}
 	}
@@ -255,10 +273,10 @@ func applyTransaction(msg *Message, config *params.ChainConfig, bc ChainContext,
 // for the transaction, gas used and an error if the transaction failed,
 // indicating the block was invalid.
 func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config, romeGasUsed uint64, footPrint string, romeGasPrice uint64) (*types.Receipt, error) {
-	return ApplyTransactionWithSolana(config, bc, author, gp, statedb, header, tx, usedGas, cfg, romeGasUsed, footPrint, romeGasPrice, nil, nil)
+	return ApplyTransactionWithSolana(context.Background(), config, bc, author, gp, statedb, header, tx, usedGas, cfg, romeGasUsed, footPrint, romeGasPrice, nil, nil)
 }
 
-func ApplyTransactionWithSolana(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config, romeGasUsed uint64, footPrint string, romeGasPrice uint64, solanaBlockNumber *uint64, solanaTimestamp *int64) (*types.Receipt, error) {
+func ApplyTransactionWithSolana(ctx context.Context, config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config, romeGasUsed uint64, footPrint string, romeGasPrice uint64, solanaBlockNumber *uint64, solanaTimestamp *int64) (*types.Receipt, error) {
 	msg, err := TransactionToMessage(tx, types.MakeSigner(config, header.Number, header.Time), header.BaseFee)
 	if err != nil {
 		return nil, err
@@ -276,7 +294,7 @@ func ApplyTransactionWithSolana(config *params.ChainConfig, bc ChainContext, aut
 	}
 
 	vmenv := vm.NewEVM(blockContext, txContext, statedb, config, cfg)
-	return applyTransaction(msg, config, bc, gp, statedb, header.Number, header.Hash(), tx, usedGas, vmenv, romeGasUsed, footPrint, romeGasPrice, solanaBlockNumber, solanaTimestamp)
+	return applyTransaction(ctx, msg, config, bc, gp, statedb, header.Number, header.Hash(), tx, usedGas, vmenv, romeGasUsed, footPrint, romeGasPrice, solanaBlockNumber, solanaTimestamp)
 }
 
 // ProcessBeaconBlockRoot applies the EIP-4788 system call to the beacon block root
@@ -33,6 +33,7 @@ import (
 // message no matter the execution itself is successful or not.
 type ExecutionResult struct {
 	UsedGas     uint64 // Total used gas, not including the refunded gas
+	EVMGasUsed  uint64 // Gas consumed by the local EVM execution, regardless of the Rome gas vector
 	RefundedGas uint64 // Total gas refunded after execution
 	Err         error  // Any error encountered during the execution(listed in core/vm/errors.go)
 	ReturnData  []byte // Returned data from evm(function result or data supplied with revert opcode)
@@ -446,6 +447,7 @@ func (st *StateTransition) innerTransitionDb(romeGasUsed uint64, romeGasPrice ui
 
 	return &ExecutionResult{
 		UsedGas:     romeGasUsed,
+		EVMGasUsed:  st.gasUsed(),
 		RefundedGas: 0,
 		Err:         vmerr,
 		ReturnData:  ret,
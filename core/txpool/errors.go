@@ -54,4 +54,8 @@ var (
 	// ErrFutureReplacePending is returned if a future transaction replaces a pending
 	// one. Future transactions should only be able to replace other future transactions.
 	ErrFutureReplacePending = errors.New("future transaction tries to replace pending")
+
+	// ErrTxConditionalRejected is returned if the conditional a transaction was
+	// submitted under does not hold against the current chain state.
+	ErrTxConditionalRejected = errors.New("transaction conditional rejected")
 )
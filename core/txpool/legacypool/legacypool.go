@@ -19,6 +19,7 @@ package legacypool
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"sort"
@@ -51,6 +52,11 @@ const (
 	// more expensive to propagate; larger transactions also take more resources
 	// to validate whether they fit into the pool or not.
 	txMaxSize = 4 * txSlotSize // 128KB
+
+	// minedRetention is the number of blocks the submission state of the mined
+	// private and conditional transactions is retained for, to be restored if
+	// a reorg reinjects them into the pool. Deeper reorgs are not reinjected.
+	minedRetention = 64
 )
 
 var (
@@ -68,15 +74,17 @@ var (
 	// Metrics for the pending pool
 	pendingDiscardMeter   = metrics.NewRegisteredMeter("txpool/pending/discard", nil)
 	pendingReplaceMeter   = metrics.NewRegisteredMeter("txpool/pending/replace", nil)
-	pendingRateLimitMeter = metrics.NewRegisteredMeter("txpool/pending/ratelimit", nil) // Dropped due to rate limiting
-	pendingNofundsMeter   = metrics.NewRegisteredMeter("txpool/pending/nofunds", nil)   // Dropped due to out-of-funds
+	pendingRateLimitMeter = metrics.NewRegisteredMeter("txpool/pending/ratelimit", nil)   // Dropped due to rate limiting
+	pendingNofundsMeter   = metrics.NewRegisteredMeter("txpool/pending/nofunds", nil)     // Dropped due to out-of-funds
+	pendingCondMeter      = metrics.NewRegisteredMeter("txpool/pending/conditional", nil) // Dropped due to a failed conditional
 
 	// Metrics for the queued pool
 	queuedDiscardMeter   = metrics.NewRegisteredMeter("txpool/queued/discard", nil)
 	queuedReplaceMeter   = metrics.NewRegisteredMeter("txpool/queued/replace", nil)
-	queuedRateLimitMeter = metrics.NewRegisteredMeter("txpool/queued/ratelimit", nil) // Dropped due to rate limiting
-	queuedNofundsMeter   = metrics.NewRegisteredMeter("txpool/queued/nofunds", nil)   // Dropped due to out-of-funds
-	queuedEvictionMeter  = metrics.NewRegisteredMeter("txpool/queued/eviction", nil)  // Dropped due to lifetime
+	queuedRateLimitMeter = metrics.NewRegisteredMeter("txpool/queued/ratelimit", nil)   // Dropped due to rate limiting
+	queuedNofundsMeter   = metrics.NewRegisteredMeter("txpool/queued/nofunds", nil)     // Dropped due to out-of-funds
+	queuedEvictionMeter  = metrics.NewRegisteredMeter("txpool/queued/eviction", nil)    // Dropped due to lifetime
+	queuedCondMeter      = metrics.NewRegisteredMeter("txpool/queued/conditional", nil) // Dropped due to a failed conditional
 
 	// General tx metrics
 	knownTxMeter       = metrics.NewRegisteredMeter("txpool/known", nil)
@@ -84,6 +92,8 @@ var (
 	invalidTxMeter     = metrics.NewRegisteredMeter("txpool/invalid", nil)
 	underpricedTxMeter = metrics.NewRegisteredMeter("txpool/underpriced", nil)
 	overflowedTxMeter  = metrics.NewRegisteredMeter("txpool/overflowed", nil)
+	conditionalTxMeter = metrics.NewRegisteredMeter("txpool/conditional/rejected", nil)
+	privateExpiryMeter = metrics.NewRegisteredMeter("txpool/private/expired", nil) // Dropped due to private lifetime
 
 	// throttleTxMeter counts how many transactions are rejected due to too-many-changes between
 	// txpool reorgs.
@@ -138,6 +148,9 @@ type Config struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	PrivateLifetime uint64 // Number of blocks a private transaction is retained for before being dropped
+	PrivateContent  bool   // Whether private transactions are exposed through the pool content
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -154,6 +167,8 @@ var DefaultConfig = Config{
 	GlobalQueue:  8192,
 
 	Lifetime: 5 * time.Minute,
+
+	PrivateLifetime: 100,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -192,6 +207,10 @@ func (config *Config) sanitize() Config {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultConfig.Lifetime)
 		conf.Lifetime = DefaultConfig.Lifetime
 	}
+	if conf.PrivateLifetime < 1 {
+		log.Warn("Sanitizing invalid txpool private lifetime", "provided", conf.PrivateLifetime, "updated", DefaultConfig.PrivateLifetime)
+		conf.PrivateLifetime = DefaultConfig.PrivateLifetime
+	}
 	return conf
 }
 
@@ -224,6 +243,8 @@ type LegacyPool struct {
 	beats   map[common.Address]time.Time // Last heartbeat from each known account
 	all     *lookup                      // All transactions to allow lookups
 	priced  *pricedList                  // All transactions sorted by price
+	private map[common.Hash]uint64       // Private transactions, mapped to the block they expire at
+	mined   map[common.Hash]*minedTx     // Mined private and conditional transactions, kept for reorgs
 
 	reqResetCh      chan *txpoolResetRequest
 	reqPromoteCh    chan *accountSet
@@ -238,6 +259,16 @@ type LegacyPool struct {
 	l1CostFn txpool.L1CostFunc // To apply L1 costs as rollup, optional field, may be nil.
 }
 
+// minedTx is the submission state of a private or conditional transaction that
+// left the pool by inclusion. The state is only carried by the transaction object
+// it was submitted with, so it is lost once the transaction is read back from a
+// block body.
+type minedTx struct {
+	private     bool
+	conditional *types.TransactionConditional
+	number      uint64 // Head block number the transaction left the pool at
+}
+
 type txpoolResetRequest struct {
 	oldHead, newHead *types.Header
 }
@@ -258,6 +289,8 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		queue:           make(map[common.Address]*list),
 		beats:           make(map[common.Address]time.Time),
 		all:             newLookup(),
+		private:         make(map[common.Hash]uint64),
+		mined:           make(map[common.Hash]*minedTx),
 		reqResetCh:      make(chan *txpoolResetRequest),
 		reqPromoteCh:    make(chan *accountSet),
 		queueTxEventCh:  make(chan *types.Transaction),
@@ -497,11 +530,15 @@ func (pool *LegacyPool) Content() (map[common.Address][]*types.Transaction, map[
 
 	pending := make(map[common.Address][]*types.Transaction, len(pool.pending))
 	for addr, list := range pool.pending {
-		pending[addr] = list.Flatten()
+		if txs := pool.content(list); len(txs) > 0 {
+			pending[addr] = txs
+		}
 	}
 	queued := make(map[common.Address][]*types.Transaction, len(pool.queue))
 	for addr, list := range pool.queue {
-		queued[addr] = list.Flatten()
+		if txs := pool.content(list); len(txs) > 0 {
+			queued[addr] = txs
+		}
 	}
 	return pending, queued
 }
@@ -514,15 +551,33 @@ func (pool *LegacyPool) ContentFrom(addr common.Address) ([]*types.Transaction,
 
 	var pending []*types.Transaction
 	if list, ok := pool.pending[addr]; ok {
-		pending = list.Flatten()
+		pending = pool.content(list)
 	}
 	var queued []*types.Transaction
 	if list, ok := pool.queue[addr]; ok {
-		queued = list.Flatten()
+		queued = pool.content(list)
 	}
 	return pending, queued
 }
 
+// content flattens the transactions of the list into the pool content, leaving
+// out the private ones unless they are configured to be exposed.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) content(list *list) []*types.Transaction {
+	txs := list.Flatten()
+	if pool.config.PrivateContent || len(pool.private) == 0 {
+		return txs
+	}
+	public := make([]*types.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if !tx.Private() {
+			public = append(public, tx)
+		}
+	}
+	return public
+}
+
 // Pending retrieves all currently processable transactions, grouped by origin
 // account and sorted by nonce. The returned transaction set is a copy and can be
 // freely modified by calling code.
@@ -605,6 +660,23 @@ func (pool *LegacyPool) local() map[common.Address]types.Transactions {
 			txs[addr] = append(txs[addr], queued.Flatten()...)
 		}
 	}
+	// Private transactions are not journaled, as they would be reloaded as
+	// public ones after a restart.
+	if len(pool.private) > 0 {
+		for addr, list := range txs {
+			public := list[:0]
+			for _, tx := range list {
+				if !tx.Private() {
+					public = append(public, tx)
+				}
+			}
+			if len(public) == 0 {
+				delete(txs, addr)
+			} else {
+				txs[addr] = public
+			}
+		}
+	}
 	return txs
 }
 
@@ -673,9 +745,26 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 	if err := txpool.ValidateTransactionWithState(tx, pool.signer, opts); err != nil {
 		return err
 	}
+	if cond := tx.Conditional(); cond != nil {
+		if err := pool.checkConditional(cond); err != nil {
+			conditionalTxMeter.Mark(1)
+			return fmt.Errorf("%w: %v", txpool.ErrTxConditionalRejected, err)
+		}
+	}
 	return nil
 }
 
+// checkConditional verifies that a transaction conditional can still be met
+// on top of the current head: its maximum block number and timestamp must not
+// have passed and its known accounts must match the head state. The minimum
+// bounds are left to the miner, the transaction waits in the pool until then.
+func (pool *LegacyPool) checkConditional(cond *types.TransactionConditional) error {
+	if err := cond.CheckExpiry(pool.currentHead.Load()); err != nil {
+		return err
+	}
+	return pool.currentState.CheckTransactionConditional(cond)
+}
+
 // add validates a transaction and inserts it into the non-executable queue for later
 // pending promotion and execution. If the transaction is a replacement for an already
 // pending or queued one, it overwrites the previous transaction if its price is higher.
@@ -805,6 +894,7 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 		pool.all.Add(tx, isLocal)
 		pool.priced.Put(tx, isLocal)
 		pool.journalTx(from, tx)
+		pool.trackPrivate(tx)
 		pool.queueTxEvent(tx)
 		log.Trace("Pooled new executable transaction", "hash", hash, "from", from, "to", tx.To())
 
@@ -827,6 +917,7 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 		localGauge.Inc(1)
 	}
 	pool.journalTx(from, tx)
+	pool.trackPrivate(tx)
 
 	log.Trace("Pooled new future transaction", "hash", hash, "from", from, "to", tx.To())
 	return replaced, nil
@@ -903,11 +994,92 @@ func (pool *LegacyPool) journalTx(from common.Address, tx *types.Transaction) {
 	if pool.journal == nil || (!pool.config.JournalRemote && !pool.locals.contains(from)) {
 		return
 	}
+	// Private transactions are withheld from the journal too
+	if tx.Private() {
+		return
+	}
 	if err := pool.journal.insert(tx); err != nil {
 		log.Warn("Failed to journal local transaction", "err", err)
 	}
 }
 
+// trackPrivate registers the specified transaction in the private lane if it
+// was submitted privately, to be dropped if not included within its lifetime.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) trackPrivate(tx *types.Transaction) {
+	if !tx.Private() {
+		return
+	}
+	pool.private[tx.Hash()] = pool.currentHead.Load().Number.Uint64() + pool.config.PrivateLifetime
+}
+
+// expirePrivate drops all the private transactions not included until the given
+// block, along with the lane entries of the ones already gone from the pool.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) expirePrivate(number uint64) {
+	for hash, expiry := range pool.private {
+		if pool.all.Get(hash) == nil {
+			delete(pool.private, hash)
+			continue
+		}
+		if number >= expiry {
+			log.Trace("Removed expired private transaction", "hash", hash)
+			pool.removeTx(hash, true, true)
+			privateExpiryMeter.Mark(1)
+		}
+	}
+}
+
+// retainMined records the submission state of a transaction that left the pool
+// by inclusion, if it was submitted privately or with a conditional.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) retainMined(tx *types.Transaction) {
+	if !tx.Private() && tx.Conditional() == nil {
+		return
+	}
+	pool.mined[tx.Hash()] = &minedTx{
+		private:     tx.Private(),
+		conditional: tx.Conditional(),
+		number:      pool.currentHead.Load().Number.Uint64(),
+	}
+}
+
+// restoreMined restores the submission state of the transactions reinjected by
+// a reorg, so private transactions are not announced and conditional ones are
+// still checked against their conditional.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) restoreMined(txs types.Transactions) {
+	for _, tx := range txs {
+		mined, ok := pool.mined[tx.Hash()]
+		if !ok {
+			continue
+		}
+		if mined.private {
+			tx.SetPrivate(true)
+		}
+		if mined.conditional != nil {
+			tx.SetConditional(mined.conditional)
+		}
+		delete(pool.mined, tx.Hash())
+	}
+}
+
+// expireMined drops the submission state of the transactions mined more than
+// minedRetention blocks before the given block.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) expireMined(number uint64) {
+	for hash, mined := range pool.mined {
+		if number >= mined.number+minedRetention {
+			delete(pool.mined, hash)
+		}
+	}
+}
+
 // promoteTx adds a transaction to the pending (processable) list of transactions
 // and returns whether it was inserted or an older was better.
 //
@@ -1131,6 +1303,7 @@ func (pool *LegacyPool) removeTx(hash common.Hash, outofbound bool, unreserve bo
 	}
 	// Remove it from the list of known transactions
 	pool.all.Remove(hash)
+	delete(pool.private, hash)
 	if outofbound {
 		pool.priced.Removed(1)
 	}
@@ -1314,6 +1487,8 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 	// because of another transaction (e.g. higher gas price).
 	if reset != nil {
 		pool.demoteUnexecutables()
+		pool.expirePrivate(pool.currentHead.Load().Number.Uint64())
+		pool.expireMined(pool.currentHead.Load().Number.Uint64())
 		if reset.newHead != nil {
 			if pool.chainconfig.IsLondon(new(big.Int).Add(reset.newHead.Number, big.NewInt(1))) {
 				pendingBaseFee := eip1559.CalcBaseFee(pool.chainconfig, reset.newHead, reset.newHead.Time+1)
@@ -1349,9 +1524,17 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 	if len(events) > 0 {
 		var txs []*types.Transaction
 		for _, set := range events {
-			txs = append(txs, set.Flatten()...)
+			for _, tx := range set.Flatten() {
+				// Private transactions are withheld from the subscribers, as
+				// they would end up gossiped to the network.
+				if !tx.Private() {
+					txs = append(txs, tx)
+				}
+			}
+		}
+		if len(txs) > 0 {
+			pool.txFeed.Send(core.NewTxsEvent{Txs: txs})
 		}
-		pool.txFeed.Send(core.NewTxsEvent{Txs: txs})
 	}
 }
 
@@ -1457,6 +1640,7 @@ func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	core.SenderCacher.Recover(pool.signer, reinject)
+	pool.restoreMined(reinject)
 	pool.addTxsLocked(reinject, false)
 }
 
@@ -1468,7 +1652,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 	var promoted []*types.Transaction
 
 	// Iterate over all accounts and promote any executable transactions
-	gasLimit := txpool.EffectiveGasLimit(pool.chainconfig, pool.currentHead.Load().GasLimit)
+	gasLimit := txpool.EffectiveGasLimit(pool.chainconfig, pool.currentHead.Load())
 	for _, addr := range accounts {
 		list := pool.queue[addr]
 		if list == nil {
@@ -1479,6 +1663,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		for _, tx := range forwards {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.retainMined(tx)
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
 		balance := pool.currentState.GetBalance(addr)
@@ -1502,6 +1687,16 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		log.Trace("Removed unpayable queued transactions", "count", len(drops), "balance", balance, "gasLimit", gasLimit)
 		queuedNofundsMeter.Mark(int64(len(drops)))
 
+		// Drop all transactions whose conditional can no longer be met
+		conds, _ := list.FilterConditional(pool.checkConditional)
+		for _, tx := range conds {
+			hash := tx.Hash()
+			log.Trace("Removed queued transaction with failed conditional", "hash", hash)
+			pool.all.Remove(hash)
+		}
+		queuedCondMeter.Mark(int64(len(conds)))
+		drops = append(drops, conds...)
+
 		// Gather all executable transactions and promote them
 		readies := list.Ready(pool.pendingNonces.get(addr))
 		for _, tx := range readies {
@@ -1683,7 +1878,7 @@ func (pool *LegacyPool) truncateQueue() {
 // to trigger a re-heap is this function
 func (pool *LegacyPool) demoteUnexecutables() {
 	// Iterate over all accounts and demote any non-executable transactions
-	gasLimit := txpool.EffectiveGasLimit(pool.chainconfig, pool.currentHead.Load().GasLimit)
+	gasLimit := txpool.EffectiveGasLimit(pool.chainconfig, pool.currentHead.Load())
 	for addr, list := range pool.pending {
 		nonce := pool.currentState.GetNonce(addr)
 
@@ -1692,6 +1887,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 		for _, tx := range olds {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.retainMined(tx)
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
 		balance := pool.currentState.GetBalance(addr)
@@ -1711,6 +1907,18 @@ func (pool *LegacyPool) demoteUnexecutables() {
 		}
 		pendingNofundsMeter.Mark(int64(len(drops)))
 
+		// Drop all transactions whose conditional can no longer be met, and queue
+		// the ones after them back for later
+		conds, condInvalids := list.FilterConditional(pool.checkConditional)
+		for _, tx := range conds {
+			hash := tx.Hash()
+			log.Trace("Removed pending transaction with failed conditional", "hash", hash)
+			pool.all.Remove(hash)
+		}
+		pendingCondMeter.Mark(int64(len(conds)))
+		drops = append(drops, conds...)
+		invalids = append(invalids, condInvalids...)
+
 		for _, tx := range invalids {
 			hash := tx.Hash()
 			log.Trace("Demoting pending transaction", "hash", hash)
@@ -2686,3 +2686,253 @@ func BenchmarkMultiAccountBatchInsert(b *testing.B) {
 		pool.addRemotesSync([]*types.Transaction{tx})
 	}
 }
+
+// Tests that conditional transactions are only admitted while their conditional
+// holds, and dropped once it no longer does.
+func TestConditionalTransactions(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	var (
+		account  = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.Address{0xc0}
+		slot     = common.Hash{0x01}
+	)
+	testAddBalance(pool, account, big.NewInt(params.Ether))
+
+	pool.mu.Lock()
+	pool.currentState.SetState(contract, slot, common.Hash{0x01})
+	pool.mu.Unlock()
+
+	conditional := func(nonce uint64, cond *types.TransactionConditional) *types.Transaction {
+		tx := transaction(nonce, 100000, key)
+		tx.SetConditional(cond)
+		return tx
+	}
+	slotCond := func(value common.Hash) *types.TransactionConditional {
+		return &types.TransactionConditional{KnownAccounts: types.KnownAccounts{
+			contract: {StorageSlots: map[common.Hash]common.Hash{slot: value}},
+		}}
+	}
+	// Conditionals not holding against the head are rejected
+	if err := pool.addLocal(conditional(0, slotCond(common.Hash{0x02}))); !errors.Is(err, txpool.ErrTxConditionalRejected) {
+		t.Fatalf("storage mismatch: have %v, want %v", err, txpool.ErrTxConditionalRejected)
+	}
+	if err := pool.addLocal(conditional(0, &types.TransactionConditional{BlockNumberMax: common.Big0})); !errors.Is(err, txpool.ErrTxConditionalRejected) {
+		t.Fatalf("expired block number: have %v, want %v", err, txpool.ErrTxConditionalRejected)
+	}
+	// Matching conditionals are admitted, minimum bounds are left to the miner
+	if err := pool.addLocal(conditional(0, slotCond(common.Hash{0x01}))); err != nil {
+		t.Fatalf("failed to add matching conditional: %v", err)
+	}
+	if err := pool.addLocal(conditional(1, &types.TransactionConditional{BlockNumberMin: big.NewInt(5)})); err != nil {
+		t.Fatalf("failed to add future conditional: %v", err)
+	}
+	if err := pool.addLocal(transaction(2, 100000, key)); err != nil {
+		t.Fatalf("failed to add unconditional transaction: %v", err)
+	}
+	if pending, queued := pool.Stats(); pending != 3 || queued != 0 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 3/0", pending, queued)
+	}
+	// Change the storage and ensure the conditional is dropped, and the
+	// transactions after it are demoted
+	pool.mu.Lock()
+	pool.currentState.SetState(contract, slot, common.Hash{0x02})
+	pool.mu.Unlock()
+
+	<-pool.requestReset(nil, nil)
+	if pending, queued := pool.Stats(); pending != 0 || queued != 2 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 0/2", pending, queued)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that private transactions are withheld from the event feed and the
+// pool content, but are still served to the miner until they expire.
+func TestPrivateTransactions(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	other, _ := crypto.GenerateKey()
+	var (
+		account = crypto.PubkeyToAddress(key.PublicKey)
+		public  = crypto.PubkeyToAddress(other.PublicKey)
+	)
+	testAddBalance(pool, account, big.NewInt(params.Ether))
+	testAddBalance(pool, public, big.NewInt(params.Ether))
+
+	events := make(chan core.NewTxsEvent, 32)
+	sub := pool.txFeed.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	private := transaction(0, 100000, key)
+	private.SetPrivate(true)
+	if err := pool.addLocal(private); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(transaction(0, 100000, other)); err != nil {
+		t.Fatalf("failed to add public transaction: %v", err)
+	}
+	// Only the public transaction is announced and listed
+	if err := validateEvents(events, 1); err != nil {
+		t.Fatalf("event firing failed: %v", err)
+	}
+	if pending := pool.Pending(false); len(pending[account]) != 1 || len(pending[public]) != 1 {
+		t.Fatalf("pending transactions mismatch: have %d/%d, want 1/1", len(pending[account]), len(pending[public]))
+	}
+	if pending, _ := pool.Content(); len(pending) != 1 || len(pending[public]) != 1 {
+		t.Fatalf("private transaction listed in content")
+	}
+	if pending, _ := pool.ContentFrom(account); len(pending) != 0 {
+		t.Fatalf("private transaction listed in content of sender")
+	}
+	pool.mu.Lock()
+	pool.config.PrivateContent = true
+	pool.mu.Unlock()
+
+	if pending, _ := pool.ContentFrom(account); len(pending) != 1 {
+		t.Fatalf("private transaction not listed in exposed content")
+	}
+	// Advance the chain to just before the expiry, and then onto it
+	head := func(number uint64) *types.Header {
+		header := pool.chain.CurrentBlock()
+		header.Number = new(big.Int).SetUint64(number)
+		header.BaseFee = common.Big1
+		return header
+	}
+	<-pool.requestReset(nil, head(pool.config.PrivateLifetime-1))
+	if !pool.Has(private.Hash()) {
+		t.Fatalf("private transaction dropped before expiry")
+	}
+	<-pool.requestReset(nil, head(pool.config.PrivateLifetime))
+	if pool.Has(private.Hash()) {
+		t.Fatalf("expired private transaction retained")
+	}
+	if pending, queued := pool.Stats(); pending != 1 || queued != 0 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 1/0", pending, queued)
+	}
+	if len(pool.private) != 0 {
+		t.Fatalf("private lane not emptied: %d", len(pool.private))
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// reorgBlockChain is a testBlockChain serving the blocks of a reorg.
+type reorgBlockChain struct {
+	*testBlockChain
+	blocks map[common.Hash]*types.Block
+}
+
+func (bc *reorgBlockChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return bc.blocks[hash]
+}
+
+// Tests that private and conditional transactions mined and then reorged out
+// are reinjected with their submission state, instead of as public ones.
+func TestReorgedSubmissionState(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &reorgBlockChain{
+		testBlockChain: newTestBlockChain(params.TestChainConfig, 10000000, statedb, new(event.Feed)),
+		blocks:         make(map[common.Hash]*types.Block),
+	}
+	pool := New(testTxPoolConfig, blockchain)
+	if err := pool.Init(new(big.Int).SetUint64(testTxPoolConfig.PriceLimit), blockchain.CurrentBlock(), makeAddressReserver()); err != nil {
+		t.Fatalf("failed to init pool: %v", err)
+	}
+	<-pool.initDoneCh
+	defer pool.Close()
+
+	key, _ := crypto.GenerateKey()
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, account, big.NewInt(params.Ether))
+
+	events := make(chan core.NewTxsEvent, 32)
+	sub := pool.txFeed.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	private := transaction(0, 100000, key)
+	private.SetPrivate(true)
+	if err := pool.addLocal(private); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	conditional := transaction(1, 100000, key)
+	conditional.SetConditional(&types.TransactionConditional{BlockNumberMax: big.NewInt(10)})
+	if err := pool.addLocal(conditional); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+	if err := validateEvents(events, 1); err != nil {
+		t.Fatalf("event firing failed: %v", err)
+	}
+	// Mine both transactions, read back from the block body without their
+	// submission state
+	decode := func(tx *types.Transaction) *types.Transaction {
+		blob, _ := tx.MarshalBinary()
+		dec := new(types.Transaction)
+		if err := dec.UnmarshalBinary(blob); err != nil {
+			t.Fatalf("failed to decode transaction: %v", err)
+		}
+		return dec
+	}
+	block := func(parent *types.Header, extra byte, txs ...*types.Transaction) *types.Header {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			GasLimit:   parent.GasLimit,
+			BaseFee:    common.Big1,
+			Extra:      []byte{extra},
+		}
+		b := types.NewBlock(header, txs, nil, nil, trie.NewStackTrie(nil))
+		blockchain.blocks[b.Hash()] = b
+		return b.Header()
+	}
+	genesis := blockchain.CurrentBlock()
+	blockchain.blocks[genesis.Hash()] = types.NewBlockWithHeader(genesis)
+
+	mined := block(genesis, 0x01, decode(private), decode(conditional))
+	pool.mu.Lock()
+	pool.currentState.SetNonce(account, 2)
+	pool.mu.Unlock()
+
+	<-pool.requestReset(genesis, mined)
+	if pending, queued := pool.Stats(); pending != 0 || queued != 0 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 0/0", pending, queued)
+	}
+	// Reorg the transactions out and ensure they are reinjected privately and
+	// with their conditional
+	pool.mu.Lock()
+	pool.currentState.SetNonce(account, 0)
+	pool.mu.Unlock()
+
+	<-pool.requestReset(mined, block(genesis, 0x02))
+	if pending, queued := pool.Stats(); pending != 2 || queued != 0 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 2/0", pending, queued)
+	}
+	if tx := pool.Get(private.Hash()); tx == nil || !tx.Private() {
+		t.Fatalf("reinjected private transaction not private")
+	}
+	if tx := pool.Get(conditional.Hash()); tx == nil || tx.Conditional() == nil {
+		t.Fatalf("reinjected conditional transaction lost its conditional")
+	}
+	if pending, _ := pool.Content(); len(pending[account]) != 1 {
+		t.Fatalf("content mismatch: have %d, want 1", len(pending[account]))
+	}
+	if err := validateEvents(events, 1); err != nil {
+		t.Fatalf("event firing failed: %v", err)
+	}
+	if len(pool.mined) != 0 {
+		t.Fatalf("mined submission state not released: %d", len(pool.mined))
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
@@ -404,6 +404,34 @@ func (l *list) Filter(costLimit *big.Int, gasLimit uint64) (types.Transactions,
 	return removed, invalids
 }
 
+// FilterConditional removes all transactions from the list whose conditional
+// fails the given check. Like Filter, in strict mode any transaction after the
+// lowest removed one is returned as invalidated.
+func (l *list) FilterConditional(check func(*types.TransactionConditional) error) (types.Transactions, types.Transactions) {
+	removed := l.txs.filter(func(tx *types.Transaction) bool {
+		cond := tx.Conditional()
+		return cond != nil && check(cond) != nil
+	})
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	var invalids types.Transactions
+	// If the list was strict, filter anything above the lowest nonce
+	if l.strict {
+		lowest := uint64(math.MaxUint64)
+		for _, tx := range removed {
+			if nonce := tx.Nonce(); lowest > nonce {
+				lowest = nonce
+			}
+		}
+		invalids = l.txs.filter(func(tx *types.Transaction) bool { return tx.Nonce() > lowest })
+	}
+	l.subTotalCost(removed)
+	l.subTotalCost(invalids)
+	l.txs.reheap()
+	return removed, invalids
+}
+
 // Cap places a hard limit on the number of items, returning all transactions
 // exceeding that limit.
 func (l *list) Cap(threshold int) types.Transactions {
@@ -32,11 +32,41 @@ import (
 
 // Gas Limit Proxy is the amount of gas consumed by all transactions in a block on RomeEVM.
 // Since RomeEVM is the source of truth, we adhere to this value as Effective Gas Limit for a
-// block on Rome OP-Geth.
+// block on Rome OP-Geth, unless the chain is configured to follow the block headers.
 const gasLimitProxy = uint64(48000000000000)
 
-func EffectiveGasLimit(chainConfig *params.ChainConfig, gasLimit uint64) uint64 {
-	return gasLimitProxy
+// headerGasLimit reports whether the effective gas limit of a block with the
+// given timestamp is the one in its header, as set by the Rome payload
+// attributes the block was built with, instead of the gas limit proxy.
+func headerGasLimit(chainConfig *params.ChainConfig, time uint64) bool {
+	return chainConfig.IsRomeGasLimit(time)
+}
+
+// EffectiveGasLimit returns the gas limit to hold the transactions of a block
+// to: the gas limit in its header if the chain follows the headers, the gas
+// limit proxy otherwise.
+func EffectiveGasLimit(chainConfig *params.ChainConfig, header *types.Header) uint64 {
+	if !headerGasLimit(chainConfig, header.Time) || header.GasLimit == 0 {
+		return gasLimitProxy
+	}
+	return header.GasLimit
+}
+
+// BlockGasLimit returns the gas limit of a block with the given timestamp to
+// build on top of a parent. If the chain follows the headers, the gas limit
+// requested in the payload attributes is preferred over the one in the chain
+// config, falling back to the effective gas limit of the parent.
+func BlockGasLimit(chainConfig *params.ChainConfig, parent *types.Header, time uint64, attrGasLimit *uint64) uint64 {
+	if !headerGasLimit(chainConfig, time) {
+		return gasLimitProxy
+	}
+	if attrGasLimit != nil && *attrGasLimit != 0 {
+		return *attrGasLimit
+	}
+	if chainConfig.Rome.GasLimit != nil {
+		return *chainConfig.Rome.GasLimit
+	}
+	return EffectiveGasLimit(chainConfig, parent)
 }
 
 // ValidationOptions define certain differences between transaction validation
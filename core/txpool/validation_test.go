@@ -0,0 +1,72 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestBlockGasLimit(t *testing.T) {
+	var (
+		configured = uint64(60_000_000)
+		requested  = uint64(45_000_000)
+		zero       = uint64(0)
+		fork       = uint64(100)
+	)
+	tests := []struct {
+		rome      *params.RomeConfig
+		time      uint64
+		parent    uint64
+		attr      *uint64
+		effective uint64 // Effective gas limit of the parent
+		built     uint64 // Gas limit of the block built on the parent
+	}{
+		// Without opting in, the gas limit proxy is used regardless of the headers
+		{nil, 0, 30_000_000, &requested, gasLimitProxy, gasLimitProxy},
+		{&params.RomeConfig{}, 0, 30_000_000, &requested, gasLimitProxy, gasLimitProxy},
+
+		// A configured gas limit alone does not switch away from the proxy
+		{&params.RomeConfig{GasLimit: &configured}, 0, 30_000_000, nil, gasLimitProxy, gasLimitProxy},
+		{&params.RomeConfig{GasLimit: &configured, GasLimitTime: &fork}, 99, 30_000_000, nil, gasLimitProxy, gasLimitProxy},
+
+		// The configured gas limit is used past the fork when the attributes have none
+		{&params.RomeConfig{GasLimit: &configured, GasLimitTime: &fork}, 100, 30_000_000, nil, 30_000_000, configured},
+		{&params.RomeConfig{GasLimit: &configured, GasLimitTime: &fork}, 100, 30_000_000, &requested, 30_000_000, requested},
+		{&params.RomeConfig{GasLimit: &configured, GasLimitTime: &fork}, 100, 0, &zero, gasLimitProxy, configured},
+
+		// The headers are followed past the gas limit fork
+		{&params.RomeConfig{GasLimitTime: &fork}, 99, 30_000_000, &requested, gasLimitProxy, gasLimitProxy},
+		{&params.RomeConfig{GasLimitTime: &fork}, 100, 30_000_000, &requested, 30_000_000, requested},
+		{&params.RomeConfig{GasLimitTime: &fork}, 100, 30_000_000, nil, 30_000_000, 30_000_000},
+		{&params.RomeConfig{GasLimitTime: &fork}, 100, 0, nil, gasLimitProxy, gasLimitProxy},
+	}
+	for i, tt := range tests {
+		config := *params.TestChainConfig
+		config.Rome = tt.rome
+
+		parent := &types.Header{GasLimit: tt.parent, Time: tt.time}
+		if have := EffectiveGasLimit(&config, parent); have != tt.effective {
+			t.Errorf("test %d: effective gas limit mismatch: have %d, want %d", i, have, tt.effective)
+		}
+		if have := BlockGasLimit(&config, parent, tt.time, tt.attr); have != tt.built {
+			t.Errorf("test %d: block gas limit mismatch: have %d, want %d", i, have, tt.built)
+		}
+	}
+}
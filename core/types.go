@@ -17,6 +17,7 @@
 package core
 
 import (
+	"context"
 	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/core/state"
@@ -48,6 +49,7 @@ type Prefetcher interface {
 type Processor interface {
 	// Process processes the state changes according to the Ethereum rules by running
 	// the transaction messages using the statedb and applying any rewards to both
-	// the processor (coinbase) and any included uncles.
-	Process(block *types.Block, statedb *state.StateDB, cfg vm.Config, romeGasUsed []uint64, romeGasPrice []uint64, footPrints []string) (types.Receipts, []*types.Log, uint64, error)
+	// the processor (coinbase) and any included uncles. If the Rome vectors are
+	// not supplied, the ones stored with the block at insertion time are used.
+	Process(ctx context.Context, block *types.Block, statedb *state.StateDB, cfg vm.Config, romeGasUsed []uint64, romeGasPrice []uint64, footPrints []string) (types.Receipts, []*types.Log, uint64, error)
 }
@@ -0,0 +1,64 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+// RomeBlockData contains the per-transaction execution inputs supplied by the
+// Rome sequencer for a block. The gas used and gas price vectors drive the fee
+// accounting of every transaction, so a block can only be re-executed to the
+// same state root if these values are available.
+type RomeBlockData struct {
+	GasUsed    []uint64 // Rome gas used per transaction
+	GasPrice   []uint64 // Rome gas price per transaction
+	Footprints []string // Rome-EVM state footprint per transaction
+}
+
+// NewRomeBlockData assembles the Rome execution inputs of a block. It returns
+// nil if all vectors are empty.
+func NewRomeBlockData(gasUsed []uint64, gasPrice []uint64, footprints []string) *RomeBlockData {
+	if len(gasUsed) == 0 && len(gasPrice) == 0 && len(footprints) == 0 {
+		return nil
+	}
+	return &RomeBlockData{
+		GasUsed:    gasUsed,
+		GasPrice:   gasPrice,
+		Footprints: footprints,
+	}
+}
+
+// TxGas returns the Rome gas used and gas price of the i'th transaction, or
+// zeroes if they are unknown.
+func (d *RomeBlockData) TxGas(i int) (gasUsed uint64, gasPrice uint64) {
+	if d == nil {
+		return 0, 0
+	}
+	if i < len(d.GasUsed) {
+		gasUsed = d.GasUsed[i]
+	}
+	if i < len(d.GasPrice) {
+		gasPrice = d.GasPrice[i]
+	}
+	return gasUsed, gasPrice
+}
+
+// TxFootprint returns the Rome-EVM state footprint of the i'th transaction, or
+// the empty footprint "0x0" if it is unknown.
+func (d *RomeBlockData) TxFootprint(i int) string {
+	if d == nil || i >= len(d.Footprints) {
+		return "0x0"
+	}
+	return d.Footprints[i]
+}
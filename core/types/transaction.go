@@ -64,6 +64,12 @@ type Transaction struct {
 
 	// cache of details to compute the data availability fee
 	rollupCostData atomic.Value
+
+	// constraints the transaction was submitted under (not consensus)
+	conditional atomic.Value
+
+	// whether the transaction was submitted privately (not consensus)
+	private atomic.Value
 }
 
 // NewTx creates a new transaction.
@@ -541,6 +547,35 @@ func (tx *Transaction) Time() time.Time {
 	return tx.time
 }
 
+// SetConditional attaches the chain state constraints the transaction is only
+// to be included under.
+func (tx *Transaction) SetConditional(cond *TransactionConditional) {
+	tx.conditional.Store(cond)
+}
+
+// Conditional returns the inclusion constraints of the transaction, or nil if
+// it was submitted unconditionally.
+func (tx *Transaction) Conditional() *TransactionConditional {
+	if cond := tx.conditional.Load(); cond != nil {
+		return cond.(*TransactionConditional)
+	}
+	return nil
+}
+
+// SetPrivate marks the transaction as privately submitted, to be withheld from
+// the network and only included by the local miner.
+func (tx *Transaction) SetPrivate(private bool) {
+	tx.private.Store(private)
+}
+
+// Private returns whether the transaction was submitted privately.
+func (tx *Transaction) Private() bool {
+	if private := tx.private.Load(); private != nil {
+		return private.(bool)
+	}
+	return false
+}
+
 // Hash returns the transaction hash.
 func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
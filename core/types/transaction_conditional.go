@@ -0,0 +1,162 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// KnownAccounts represents a set of accounts whose storage is expected to be
+// in a given state when the transaction is included.
+type KnownAccounts map[common.Address]KnownAccount
+
+// KnownAccount is the expected storage of an account, given either as the
+// root of its storage trie or as a set of slot values. It is encoded in JSON
+// as a hash for the former and as a slot to value object for the latter.
+type KnownAccount struct {
+	StorageRoot  *common.Hash
+	StorageSlots map[common.Hash]common.Hash
+}
+
+// MarshalJSON marshals as JSON.
+func (ka KnownAccount) MarshalJSON() ([]byte, error) {
+	if ka.StorageRoot != nil {
+		return json.Marshal(ka.StorageRoot)
+	}
+	return json.Marshal(ka.StorageSlots)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (ka *KnownAccount) UnmarshalJSON(input []byte) error {
+	var root common.Hash
+	if err := json.Unmarshal(input, &root); err == nil {
+		ka.StorageRoot = &root
+		return nil
+	}
+	var slots map[common.Hash]common.Hash
+	if err := json.Unmarshal(input, &slots); err != nil {
+		return errors.New("known account must be a storage root or a map of storage slots")
+	}
+	ka.StorageSlots = slots
+	return nil
+}
+
+//go:generate go run github.com/fjl/gencodec -type TransactionConditional -field-override transactionConditionalMarshaling -out gen_transaction_conditional_json.go
+
+// TransactionConditional is a set of constraints on the chain state a
+// transaction is only to be included under. It is not part of the consensus
+// encoding of the transaction, but carried along it by the local node.
+type TransactionConditional struct {
+	KnownAccounts  KnownAccounts `json:"knownAccounts"`
+	BlockNumberMin *big.Int      `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *big.Int      `json:"blockNumberMax,omitempty"`
+	TimestampMin   *uint64       `json:"timestampMin,omitempty"`
+	TimestampMax   *uint64       `json:"timestampMax,omitempty"`
+}
+
+// field type overrides for gencodec
+type transactionConditionalMarshaling struct {
+	BlockNumberMin *math.HexOrDecimal256
+	BlockNumberMax *math.HexOrDecimal256
+	TimestampMin   *math.HexOrDecimal64
+	TimestampMax   *math.HexOrDecimal64
+}
+
+// Validate performs the sanity checks of the conditional not requiring any
+// chain state, i.e. that the bounds are not inverted.
+func (cond *TransactionConditional) Validate() error {
+	if cond.BlockNumberMin != nil && cond.BlockNumberMax != nil && cond.BlockNumberMin.Cmp(cond.BlockNumberMax) > 0 {
+		return fmt.Errorf("block number minimum %v exceeds maximum %v", cond.BlockNumberMin, cond.BlockNumberMax)
+	}
+	if cond.TimestampMin != nil && cond.TimestampMax != nil && *cond.TimestampMin > *cond.TimestampMax {
+		return fmt.Errorf("timestamp minimum %d exceeds maximum %d", *cond.TimestampMin, *cond.TimestampMax)
+	}
+	for addr, account := range cond.KnownAccounts {
+		if account.StorageRoot != nil && account.StorageSlots != nil {
+			return fmt.Errorf("known account %x has both a storage root and storage slots", addr)
+		}
+	}
+	return nil
+}
+
+// Cost returns the number of checks needed to verify the conditional, used
+// to bound the work a single conditional may request.
+func (cond *TransactionConditional) Cost() int {
+	cost := 0
+	if cond.BlockNumberMin != nil || cond.BlockNumberMax != nil {
+		cost++
+	}
+	if cond.TimestampMin != nil || cond.TimestampMax != nil {
+		cost++
+	}
+	for _, account := range cond.KnownAccounts {
+		if account.StorageRoot != nil {
+			cost++
+		}
+		cost += len(account.StorageSlots)
+	}
+	return cost
+}
+
+// CheckBlockNumber verifies the block number bounds of the conditional.
+func (cond *TransactionConditional) CheckBlockNumber(number *big.Int) error {
+	if cond.BlockNumberMin != nil && number.Cmp(cond.BlockNumberMin) < 0 {
+		return fmt.Errorf("block number %v below minimum %v", number, cond.BlockNumberMin)
+	}
+	if cond.BlockNumberMax != nil && number.Cmp(cond.BlockNumberMax) > 0 {
+		return fmt.Errorf("block number %v above maximum %v", number, cond.BlockNumberMax)
+	}
+	return nil
+}
+
+// CheckTimestamp verifies the timestamp bounds of the conditional.
+func (cond *TransactionConditional) CheckTimestamp(time uint64) error {
+	if cond.TimestampMin != nil && time < *cond.TimestampMin {
+		return fmt.Errorf("timestamp %d below minimum %d", time, *cond.TimestampMin)
+	}
+	if cond.TimestampMax != nil && time > *cond.TimestampMax {
+		return fmt.Errorf("timestamp %d above maximum %d", time, *cond.TimestampMax)
+	}
+	return nil
+}
+
+// CheckExpiry verifies that the maximum block number and timestamp bounds of
+// the conditional can still be met by a block built on top of the given head.
+func (cond *TransactionConditional) CheckExpiry(head *Header) error {
+	if cond.BlockNumberMax != nil && cond.BlockNumberMax.Cmp(head.Number) <= 0 {
+		return fmt.Errorf("block number maximum %v reached", cond.BlockNumberMax)
+	}
+	if cond.TimestampMax != nil && *cond.TimestampMax <= head.Time {
+		return fmt.Errorf("timestamp maximum %d reached", *cond.TimestampMax)
+	}
+	return nil
+}
+
+// CheckTransactionConditional verifies the block number and timestamp bounds
+// of the conditional against the header.
+func (h *Header) CheckTransactionConditional(cond *TransactionConditional) error {
+	if err := cond.CheckBlockNumber(h.Number); err != nil {
+		return err
+	}
+	return cond.CheckTimestamp(h.Time)
+}
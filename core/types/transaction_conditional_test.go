@@ -0,0 +1,110 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestTransactionConditionalJSON(t *testing.T) {
+	input := `{
+		"knownAccounts": {
+			"0x00000000000000000000000000000000000000aa": "0x00000000000000000000000000000000000000000000000000000000000000bb",
+			"0x00000000000000000000000000000000000000cc": {
+				"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000002"
+			}
+		},
+		"blockNumberMin": "0x10",
+		"timestampMax": "0x20"
+	}`
+	root := common.HexToHash("0xbb")
+	minNumber, maxTime := big.NewInt(16), uint64(32)
+	want := TransactionConditional{
+		KnownAccounts: KnownAccounts{
+			common.HexToAddress("0xaa"): {StorageRoot: &root},
+			common.HexToAddress("0xcc"): {StorageSlots: map[common.Hash]common.Hash{
+				common.HexToHash("0x01"): common.HexToHash("0x02"),
+			}},
+		},
+		BlockNumberMin: minNumber,
+		TimestampMax:   &maxTime,
+	}
+	var cond TransactionConditional
+	if err := json.Unmarshal([]byte(input), &cond); err != nil {
+		t.Fatalf("failed to decode conditional: %v", err)
+	}
+	if !reflect.DeepEqual(cond, want) {
+		t.Fatalf("decoded conditional mismatch: have %+v, want %+v", cond, want)
+	}
+	if cost := cond.Cost(); cost != 4 {
+		t.Errorf("cost mismatch: have %d, want 4", cost)
+	}
+	// Ensure the encoding round trips
+	blob, err := json.Marshal(cond)
+	if err != nil {
+		t.Fatalf("failed to encode conditional: %v", err)
+	}
+	var dec TransactionConditional
+	if err := json.Unmarshal(blob, &dec); err != nil {
+		t.Fatalf("failed to decode encoded conditional: %v", err)
+	}
+	if !reflect.DeepEqual(dec, want) {
+		t.Fatalf("round trip mismatch: have %+v, want %+v", dec, want)
+	}
+}
+
+func TestTransactionConditionalBounds(t *testing.T) {
+	u64 := func(n uint64) *uint64 { return &n }
+	cond := &TransactionConditional{
+		BlockNumberMin: big.NewInt(10),
+		BlockNumberMax: big.NewInt(20),
+		TimestampMin:   u64(100),
+		TimestampMax:   u64(200),
+	}
+	if err := cond.Validate(); err != nil {
+		t.Fatalf("valid conditional rejected: %v", err)
+	}
+	tests := []struct {
+		number, time uint64
+		ok, expired  bool
+	}{
+		{number: 9, time: 150, ok: false, expired: false},
+		{number: 10, time: 100, ok: true, expired: false},
+		{number: 20, time: 200, ok: true, expired: true},
+		{number: 21, time: 150, ok: false, expired: true},
+		{number: 15, time: 201, ok: false, expired: true},
+	}
+	for i, tt := range tests {
+		header := &Header{Number: new(big.Int).SetUint64(tt.number), Time: tt.time}
+		if err := header.CheckTransactionConditional(cond); (err == nil) != tt.ok {
+			t.Errorf("test %d: inclusion check mismatch: have %v, want ok %v", i, err, tt.ok)
+		}
+		if err := cond.CheckExpiry(header); (err != nil) != tt.expired {
+			t.Errorf("test %d: expiry check mismatch: have %v, want expired %v", i, err, tt.expired)
+		}
+	}
+	// Inverted bounds should be rejected
+	inverted := &TransactionConditional{TimestampMin: u64(2), TimestampMax: u64(1)}
+	if err := inverted.Validate(); err == nil {
+		t.Error("inverted timestamp bounds accepted")
+	}
+}
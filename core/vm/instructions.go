@@ -463,7 +463,15 @@ func opBlockhash(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) (
 		num.Clear()
 		return nil, nil
 	}
-
+	// Past the Solana blockhash fork, serve the real blockhash of the slot
+	if interpreter.evm.chainRules.IsRomeBlockhash {
+		if getHash := interpreter.evm.Context.GetSolanaHash; getHash != nil {
+			num.SetBytes(getHash(num64).Bytes())
+		} else {
+			num.Clear()
+		}
+		return nil, nil
+	}
 	var buf [32]byte
 	binary.BigEndian.PutUint64(buf[24:], num64)
 	hash := crypto.Keccak256Hash(buf[:])
@@ -34,6 +34,7 @@ type Config struct {
 	EnablePreimageRecording     bool                // Enables recording of SHA3/keccak preimages
 	ExtraEips                   []int               // Additional EIPS that are to be enabled
 	OptimismPrecompileOverrides PrecompileOverrides // Precompile overrides for Optimism
+	EnforceFootprints           bool                // Applies the footprint mismatch policy to the executed transactions
 }
 
 // ScopeContext contains the things that are per-call, such as stack and memory,
@@ -253,6 +253,11 @@ func (d *dummyChain) GetFootprintManager() *footprint.Manager {
 	return nil
 }
 
+// GetSolanaBlockhash returns the zero hash for the dummy chain.
+func (d *dummyChain) GetSolanaBlockhash(slot uint64) common.Hash {
+	return common.Hash{}
+}
+
 
 // TestBlockhash tests the blockhash operation. It's a bit special, since it internally
 // requires access to a chain reader.
@@ -0,0 +1,528 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// defaultFootprintReexec is the number of blocks the footprint debugger is
+// willing to go back and re-execute to produce the parent state of a
+// transaction.
+const defaultFootprintReexec = uint64(128)
+
+// RomeAPI provides Rome specific debugging methods of a full node.
+type RomeAPI struct {
+	eth *Ethereum
+}
+
+// NewRomeAPI creates a new RomeAPI instance.
+func NewRomeAPI(eth *Ethereum) *RomeAPI {
+	return &RomeAPI{eth: eth}
+}
+
+// FootprintSlot is a storage slot included in an account footprint.
+type FootprintSlot struct {
+	Key   common.Hash `json:"key"`
+	Value common.Hash `json:"value"`
+}
+
+// FootprintAccount is the footprint of an account touched by a transaction.
+type FootprintAccount struct {
+	Address    common.Address  `json:"address"`
+	Nonce      hexutil.Uint64  `json:"nonce"`
+	Balance    *hexutil.Big    `json:"balance"`
+	CodeHash   common.Hash     `json:"codeHash"`
+	CodeLength hexutil.Uint64  `json:"codeLength"`
+	Slots      []FootprintSlot `json:"slots"`
+	Hash       common.Hash     `json:"hash"`
+}
+
+// FootprintExpectation is the Rome-EVM view of an account footprint. Fields
+// left empty are not compared.
+type FootprintExpectation struct {
+	Address    common.Address  `json:"address"`
+	Nonce      *hexutil.Uint64 `json:"nonce"`
+	Balance    *hexutil.Big    `json:"balance"`
+	CodeLength *hexutil.Uint64 `json:"codeLength"`
+	Slots      []FootprintSlot `json:"slots"`
+	Hash       *common.Hash    `json:"hash"`
+}
+
+// FootprintFieldDiff is a single diverging component of an account footprint.
+type FootprintFieldDiff struct {
+	Field    string       `json:"field"`
+	Slot     *common.Hash `json:"slot,omitempty"`
+	Expected string       `json:"expected"`
+	Actual   string       `json:"actual"`
+}
+
+// FootprintAccountDiff lists the divergences of a single account footprint.
+type FootprintAccountDiff struct {
+	Address    common.Address       `json:"address"`
+	Missing    bool                 `json:"missing,omitempty"`    // Expected by Rome, not touched locally
+	Unexpected bool                 `json:"unexpected,omitempty"` // Touched locally, not expected by Rome
+	Fields     []FootprintFieldDiff `json:"fields,omitempty"`
+}
+
+// FootprintDebugResult is the structured footprint breakdown of a transaction.
+type FootprintDebugResult struct {
+	TxHash      common.Hash             `json:"txHash"`
+	BlockNumber hexutil.Uint64          `json:"blockNumber"`
+	BlockHash   common.Hash             `json:"blockHash"`
+	Version     hexutil.Uint64          `json:"footprintVersion"`
+	Expected    string                  `json:"expectedFootprint"`
+	Actual      common.Hash             `json:"actualFootprint"`
+	Match       bool                    `json:"match"`
+	Accounts    []*FootprintAccount     `json:"accounts"`
+	Diff        []*FootprintAccountDiff `json:"diff,omitempty"`
+}
+
+// DebugFootprint re-executes a transaction on top of its parent state and
+// returns the per-account breakdown of its state footprint. If the Rome-EVM
+// view of the touched accounts is supplied, the breakdown is diffed against it.
+func (api *RomeAPI) DebugFootprint(ctx context.Context, hash common.Hash, expected *[]FootprintExpectation) (*FootprintDebugResult, error) {
+	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(api.eth.ChainDb(), hash)
+	if tx == nil {
+		return nil, errors.New("transaction not found")
+	}
+	if blockNumber == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	block := api.eth.blockchain.GetBlock(blockHash, blockNumber)
+	if block == nil {
+		return nil, fmt.Errorf("block %#x not found", blockHash)
+	}
+	msg, vmctx, statedb, release, err := api.eth.stateAtTransaction(ctx, block, int(index), defaultFootprintReexec)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	txContext := core.NewEVMTxContext(msg)
+	core.ReadSolanaTxContext(api.eth.ChainDb(), hash, &txContext)
+	var (
+		romeData                  = api.eth.blockchain.GetRomeBlockData(blockHash, blockNumber)
+		romeGasUsed, romeGasPrice = romeData.TxGas(int(index))
+		vmenv                     = vm.NewEVM(vmctx, txContext, statedb, api.eth.blockchain.Config(), vm.Config{})
+	)
+	statedb.SetTxContext(hash, int(index))
+	statedb.ResetTxFootprint()
+	start := statedb.JournalLength()
+	if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(max(msg.GasLimit, romeGasUsed)), romeGasUsed, romeGasPrice); err != nil {
+		return nil, fmt.Errorf("transaction %#x failed: %v", hash, err)
+	}
+	version := api.eth.blockchain.Config().FootprintVersion(block.Time())
+	hasher := state.NewFootprintHasher(version)
+	if hasher == nil {
+		return nil, fmt.Errorf("unsupported footprint version %d", version)
+	}
+	accounts := statedb.TxFootprintAccounts(start, hasher)
+
+	result := &FootprintDebugResult{
+		TxHash:      hash,
+		BlockNumber: hexutil.Uint64(blockNumber),
+		BlockHash:   blockHash,
+		Version:     hexutil.Uint64(version),
+		Expected:    romeData.TxFootprint(int(index)),
+		Actual:      hasher.Fold(accounts),
+		Accounts:    make([]*FootprintAccount, 0, len(accounts)),
+	}
+	// Blocks imported without Rome data may still have a recorded mismatch
+	if manager := api.eth.blockchain.GetFootprintManager(); manager != nil && romeData == nil {
+		if mismatch := manager.Mismatch(hash); mismatch != nil {
+			result.Expected = mismatch.Expected
+		}
+	}
+	result.Match = common.HexToHash(result.Expected) == result.Actual
+
+	for _, account := range accounts {
+		rpcAccount := &FootprintAccount{
+			Address:    account.Address,
+			Nonce:      hexutil.Uint64(account.Nonce),
+			Balance:    (*hexutil.Big)(account.Balance),
+			CodeHash:   account.CodeHash,
+			CodeLength: hexutil.Uint64(account.CodeLength),
+			Slots:      make([]FootprintSlot, 0, len(account.Slots)),
+			Hash:       account.Hash,
+		}
+		for _, slot := range account.Slots {
+			rpcAccount.Slots = append(rpcAccount.Slots, FootprintSlot{Key: slot.Key, Value: slot.Value})
+		}
+		result.Accounts = append(result.Accounts, rpcAccount)
+	}
+	if expected != nil {
+		result.Diff = diffFootprint(result.Accounts, *expected)
+	}
+	return result, nil
+}
+
+// diffFootprint compares the locally computed account footprints against the
+// Rome-EVM expectation and returns the diverging accounts.
+func diffFootprint(actual []*FootprintAccount, expected []FootprintExpectation) []*FootprintAccountDiff {
+	var (
+		diffs   []*FootprintAccountDiff
+		touched = make(map[common.Address]*FootprintAccount, len(actual))
+		seen    = make(map[common.Address]bool, len(expected))
+	)
+	for _, account := range actual {
+		touched[account.Address] = account
+	}
+	for _, want := range expected {
+		seen[want.Address] = true
+
+		have, ok := touched[want.Address]
+		if !ok {
+			diffs = append(diffs, &FootprintAccountDiff{Address: want.Address, Missing: true})
+			continue
+		}
+		diff := &FootprintAccountDiff{Address: want.Address}
+		if want.Nonce != nil && *want.Nonce != have.Nonce {
+			diff.Fields = append(diff.Fields, FootprintFieldDiff{Field: "nonce", Expected: strconv.FormatUint(uint64(*want.Nonce), 10), Actual: strconv.FormatUint(uint64(have.Nonce), 10)})
+		}
+		if want.Balance != nil && want.Balance.ToInt().Cmp(have.Balance.ToInt()) != 0 {
+			diff.Fields = append(diff.Fields, FootprintFieldDiff{Field: "balance", Expected: want.Balance.ToInt().String(), Actual: have.Balance.ToInt().String()})
+		}
+		if want.CodeLength != nil && *want.CodeLength != have.CodeLength {
+			diff.Fields = append(diff.Fields, FootprintFieldDiff{Field: "codeLength", Expected: strconv.FormatUint(uint64(*want.CodeLength), 10), Actual: strconv.FormatUint(uint64(have.CodeLength), 10)})
+		}
+		if want.Slots != nil {
+			slots := make(map[common.Hash]common.Hash, len(have.Slots))
+			for _, slot := range have.Slots {
+				slots[slot.Key] = slot.Value
+			}
+			wantSlots := make(map[common.Hash]bool, len(want.Slots))
+			for _, slot := range want.Slots {
+				wantSlots[slot.Key] = true
+				key := slot.Key
+				if value, ok := slots[slot.Key]; !ok {
+					diff.Fields = append(diff.Fields, FootprintFieldDiff{Field: "slot", Slot: &key, Expected: slot.Value.Hex(), Actual: "untouched"})
+				} else if value != slot.Value {
+					diff.Fields = append(diff.Fields, FootprintFieldDiff{Field: "slot", Slot: &key, Expected: slot.Value.Hex(), Actual: value.Hex()})
+				}
+			}
+			for _, slot := range have.Slots {
+				if !wantSlots[slot.Key] {
+					key := slot.Key
+					diff.Fields = append(diff.Fields, FootprintFieldDiff{Field: "slot", Slot: &key, Expected: "untouched", Actual: slot.Value.Hex()})
+				}
+			}
+		}
+		if want.Hash != nil && *want.Hash != have.Hash {
+			diff.Fields = append(diff.Fields, FootprintFieldDiff{Field: "hash", Expected: want.Hash.Hex(), Actual: have.Hash.Hex()})
+		}
+		if len(diff.Fields) > 0 {
+			diffs = append(diffs, diff)
+		}
+	}
+	for _, account := range actual {
+		if !seen[account.Address] {
+			diffs = append(diffs, &FootprintAccountDiff{Address: account.Address, Unexpected: true})
+		}
+	}
+	return diffs
+}
+
+// maxSolanaSlotRangeTxs is the maximum number of transactions a single Solana
+// slot range query is allowed to return.
+const maxSolanaSlotRangeTxs = 10000
+
+// SolanaSlotTransaction is the position of a transaction executed in a Solana
+// slot on the canonical chain.
+type SolanaSlotTransaction struct {
+	SolanaSlot      hexutil.Uint64 `json:"solanaSlot"`
+	SolanaTimestamp hexutil.Uint64 `json:"solanaTimestamp"`
+	TxHash          common.Hash    `json:"transactionHash"`
+	BlockHash       common.Hash    `json:"blockHash"`
+	BlockNumber     hexutil.Uint64 `json:"blockNumber"`
+	TxIndex         hexutil.Uint64 `json:"transactionIndex"`
+}
+
+// GetBlockBySolanaSlot returns the first canonical block containing a
+// transaction executed in the given Solana slot, or nil if there is none.
+func (api *RomeAPI) GetBlockBySolanaSlot(ctx context.Context, slot hexutil.Uint64, fullTx bool) (map[string]interface{}, error) {
+	var first *rawdb.SolanaSlotEntry
+	rawdb.IterateSolanaSlotIndex(api.eth.ChainDb(), uint64(slot), uint64(slot), func(entry *rawdb.SolanaSlotEntry) bool {
+		if first == nil || entry.BlockNumber < first.BlockNumber {
+			first = entry
+		}
+		return true
+	})
+	if first == nil {
+		return nil, nil
+	}
+	block := api.eth.blockchain.GetBlock(first.BlockHash, first.BlockNumber)
+	if block == nil {
+		return nil, nil
+	}
+	return ethapi.RPCMarshalBlock(ctx, block, true, fullTx, api.eth.APIBackend.ChainConfig(), api.eth.APIBackend)
+}
+
+// GetTransactionsBySolanaSlotRange returns the canonical transactions executed
+// in the Solana slots [from, to], ordered by slot.
+func (api *RomeAPI) GetTransactionsBySolanaSlotRange(ctx context.Context, from, to hexutil.Uint64) ([]*SolanaSlotTransaction, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid solana slot range: from %d > to %d", from, to)
+	}
+	var (
+		txs = make([]*SolanaSlotTransaction, 0)
+		err error
+	)
+	rawdb.IterateSolanaSlotIndex(api.eth.ChainDb(), uint64(from), uint64(to), func(entry *rawdb.SolanaSlotEntry) bool {
+		if err = ctx.Err(); err != nil {
+			return false
+		}
+		if len(txs) == maxSolanaSlotRangeTxs {
+			err = fmt.Errorf("solana slot range exceeds %d transactions", maxSolanaSlotRangeTxs)
+			return false
+		}
+		_, timestamp, _ := rawdb.ReadSolanaTxMetadata(api.eth.ChainDb(), entry.TxHash)
+		txs = append(txs, &SolanaSlotTransaction{
+			SolanaSlot:      hexutil.Uint64(entry.Slot),
+			SolanaTimestamp: hexutil.Uint64(timestamp),
+			TxHash:          entry.TxHash,
+			BlockHash:       entry.BlockHash,
+			BlockNumber:     hexutil.Uint64(entry.BlockNumber),
+			TxIndex:         hexutil.Uint64(entry.TxIndex),
+		})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// defaultFeeBreakdownReexec is the number of blocks the fee breakdown is
+// willing to go back and re-execute to produce the parent state of a block.
+const defaultFeeBreakdownReexec = uint64(128)
+
+// TxFeeBreakdown is the fee accounting of a single transaction, contrasting
+// the Rome gas vectors the fees are charged by with the local EVM view.
+type TxFeeBreakdown struct {
+	TxHash         common.Hash    `json:"transactionHash"`
+	TxIndex        hexutil.Uint64 `json:"transactionIndex"`
+	From           common.Address `json:"from"`
+	Deposit        bool           `json:"deposit,omitempty"`
+	EVMGasUsed     hexutil.Uint64 `json:"evmGasUsed"`
+	RomeGasUsed    hexutil.Uint64 `json:"romeGasUsed"`
+	FeeGasUsed     hexutil.Uint64 `json:"feeGasUsed"` // Gas the coinbase credit is computed from
+	RomeGasPrice   *hexutil.Big   `json:"romeGasPrice"`
+	GasPrice       *hexutil.Big   `json:"gasPrice"`
+	GasFeeCap      *hexutil.Big   `json:"maxFeePerGas"`
+	GasTipCap      *hexutil.Big   `json:"maxPriorityFeePerGas"`
+	EffectiveTip   *hexutil.Big   `json:"effectiveTip"`
+	TipFallback    bool           `json:"tipFallback,omitempty"` // No Rome gas price, tip taken from the transaction
+	Charged        *hexutil.Big   `json:"charged"`
+	RefundedGas    hexutil.Uint64 `json:"refundedGas"`
+	Refund         *hexutil.Big   `json:"refund"`
+	CoinbaseCredit *hexutil.Big   `json:"coinbaseCredit"`
+	BaseFeeBurn    *hexutil.Big   `json:"baseFeeBurn"`
+	L1Cost         *hexutil.Big   `json:"l1Cost"`
+	Imbalance      *hexutil.Big   `json:"imbalance"` // Credited minus net charged, zero if the fees are fully backed
+}
+
+// BlockFeeBreakdown is the fee accounting of a block and its transactions.
+type BlockFeeBreakdown struct {
+	BlockHash      common.Hash       `json:"blockHash"`
+	BlockNumber    hexutil.Uint64    `json:"blockNumber"`
+	Coinbase       common.Address    `json:"miner"`
+	BaseFee        *hexutil.Big      `json:"baseFeePerGas,omitempty"`
+	EVMGasUsed     hexutil.Uint64    `json:"evmGasUsed"`
+	RomeGasUsed    hexutil.Uint64    `json:"romeGasUsed"`
+	Charged        *hexutil.Big      `json:"charged"`
+	Refund         *hexutil.Big      `json:"refund"`
+	CoinbaseCredit *hexutil.Big      `json:"coinbaseCredit"`
+	BaseFeeBurn    *hexutil.Big      `json:"baseFeeBurn"`
+	L1Cost         *hexutil.Big      `json:"l1Cost"`
+	Imbalance      *hexutil.Big      `json:"imbalance"`
+	Transactions   []*TxFeeBreakdown `json:"transactions"`
+}
+
+// GetFeeBreakdown re-executes a block on top of its parent state and returns
+// how the fees of each of its transactions were charged and distributed,
+// alongside the block totals.
+func (api *RomeAPI) GetFeeBreakdown(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*BlockFeeBreakdown, error) {
+	block, err := api.eth.APIBackend.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, errors.New("block not found")
+	}
+	result := &BlockFeeBreakdown{
+		BlockHash:      block.Hash(),
+		BlockNumber:    hexutil.Uint64(block.NumberU64()),
+		Coinbase:       block.Coinbase(),
+		Charged:        new(hexutil.Big),
+		Refund:         new(hexutil.Big),
+		CoinbaseCredit: new(hexutil.Big),
+		BaseFeeBurn:    new(hexutil.Big),
+		L1Cost:         new(hexutil.Big),
+		Imbalance:      new(hexutil.Big),
+		Transactions:   make([]*TxFeeBreakdown, 0, len(block.Transactions())),
+	}
+	if block.BaseFee() != nil {
+		result.BaseFee = (*hexutil.Big)(block.BaseFee())
+	}
+	if len(block.Transactions()) == 0 {
+		return result, nil
+	}
+	parent := api.eth.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+	}
+	statedb, release, err := api.eth.stateAtBlock(ctx, parent, defaultFeeBreakdownReexec, nil, true, false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var (
+		config   = api.eth.blockchain.Config()
+		signer   = types.MakeSigner(config, block.Number(), block.Time())
+		romeData = api.eth.blockchain.GetRomeBlockData(block.Hash(), block.NumberU64())
+	)
+	for idx, tx := range block.Transactions() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		msg, err := core.TransactionToMessage(tx, signer, block.BaseFee())
+		if err != nil {
+			return nil, fmt.Errorf("transaction %#x: %v", tx.Hash(), err)
+		}
+		txContext := core.NewEVMTxContext(msg)
+		core.ReadSolanaTxContext(api.eth.ChainDb(), tx.Hash(), &txContext)
+		var (
+			vmctx                     = core.NewEVMBlockContext(block.Header(), api.eth.blockchain, nil, config, statedb)
+			vmenv                     = vm.NewEVM(vmctx, txContext, statedb, config, vm.Config{})
+			romeGasUsed, romeGasPrice = romeData.TxGas(idx)
+		)
+		statedb.SetTxContext(tx.Hash(), idx)
+		res, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(max(tx.Gas(), romeGasUsed)), romeGasUsed, romeGasPrice)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+		}
+		statedb.Finalise(config.IsEIP158(block.Number()))
+
+		fees := txFeeBreakdown(config, &vmctx, msg, res, romeGasUsed, romeGasPrice)
+		fees.TxHash, fees.TxIndex = tx.Hash(), hexutil.Uint64(idx)
+		result.Transactions = append(result.Transactions, fees)
+
+		result.EVMGasUsed += fees.EVMGasUsed
+		result.RomeGasUsed += fees.RomeGasUsed
+		result.Charged.ToInt().Add(result.Charged.ToInt(), fees.Charged.ToInt())
+		result.Refund.ToInt().Add(result.Refund.ToInt(), fees.Refund.ToInt())
+		result.CoinbaseCredit.ToInt().Add(result.CoinbaseCredit.ToInt(), fees.CoinbaseCredit.ToInt())
+		result.BaseFeeBurn.ToInt().Add(result.BaseFeeBurn.ToInt(), fees.BaseFeeBurn.ToInt())
+		result.L1Cost.ToInt().Add(result.L1Cost.ToInt(), fees.L1Cost.ToInt())
+		result.Imbalance.ToInt().Add(result.Imbalance.ToInt(), fees.Imbalance.ToInt())
+	}
+	return result, nil
+}
+
+// txFeeBreakdown reconstructs the fee flows of an executed message, following
+// the accounting rules of the state transition: the sender is charged the Rome
+// gas used at the Rome gas price, the coinbase is credited the tip (falling
+// back to the transaction's own tip without a Rome gas price), and on Optimism
+// chains the EVM gas used is burnt at the base fee and the L1 cost is paid out
+// on top.
+func txFeeBreakdown(config *params.ChainConfig, vmctx *vm.BlockContext, msg *core.Message, res *core.ExecutionResult, romeGasUsed, romeGasPrice uint64) *TxFeeBreakdown {
+	fees := &TxFeeBreakdown{
+		From:         msg.From,
+		Deposit:      msg.IsDepositTx,
+		EVMGasUsed:   hexutil.Uint64(res.EVMGasUsed),
+		RomeGasUsed:  hexutil.Uint64(romeGasUsed),
+		RomeGasPrice: (*hexutil.Big)(new(big.Int).SetUint64(romeGasPrice)),
+		GasPrice:     (*hexutil.Big)(msg.GasPrice),
+		GasFeeCap:    (*hexutil.Big)(msg.GasFeeCap),
+		GasTipCap:    (*hexutil.Big)(msg.GasTipCap),
+		EffectiveTip: (*hexutil.Big)(new(big.Int)),
+		RefundedGas:  hexutil.Uint64(res.RefundedGas),
+	}
+	var (
+		charged  = new(big.Int)
+		refund   = new(big.Int)
+		coinbase = new(big.Int)
+		burn     = new(big.Int)
+		l1Cost   = new(big.Int)
+	)
+	// Deposits neither buy gas nor pay out any fees
+	if !msg.IsDepositTx {
+		feeGasUsed := romeGasUsed
+		if feeGasUsed == 0 {
+			feeGasUsed = res.EVMGasUsed
+		}
+		fees.FeeGasUsed = hexutil.Uint64(feeGasUsed)
+
+		tip := new(big.Int).SetUint64(romeGasPrice)
+		if romeGasPrice == 0 {
+			baseFee := vmctx.BaseFee
+			if baseFee == nil {
+				baseFee = new(big.Int)
+			}
+			tip = new(big.Int).Sub(msg.GasFeeCap, baseFee)
+			if tip.Cmp(msg.GasTipCap) > 0 {
+				tip = new(big.Int).Set(msg.GasTipCap)
+			}
+			fees.TipFallback = true
+		}
+		fees.EffectiveTip = (*hexutil.Big)(tip)
+
+		if vmctx.Coinbase != (common.Address{}) {
+			charged.Mul(new(big.Int).SetUint64(romeGasUsed), new(big.Int).SetUint64(romeGasPrice))
+			refund.Mul(new(big.Int).SetUint64(res.RefundedGas), new(big.Int).SetUint64(romeGasPrice))
+			coinbase.Mul(new(big.Int).SetUint64(feeGasUsed), tip)
+		}
+		if config.Optimism != nil && config.IsOptimismBedrock(vmctx.BlockNumber) {
+			if vmctx.BaseFee != nil {
+				burn.Mul(new(big.Int).SetUint64(res.EVMGasUsed), vmctx.BaseFee)
+			}
+			if vmctx.L1CostFunc != nil {
+				if cost := vmctx.L1CostFunc(msg.RollupCostData, vmctx.Time); cost != nil {
+					l1Cost.Set(cost)
+				}
+			}
+		}
+	}
+	imbalance := new(big.Int).Add(coinbase, burn)
+	imbalance.Add(imbalance, l1Cost)
+	imbalance.Sub(imbalance, new(big.Int).Sub(charged, refund))
+
+	fees.Charged = (*hexutil.Big)(charged)
+	fees.Refund = (*hexutil.Big)(refund)
+	fees.CoinbaseCredit = (*hexutil.Big)(coinbase)
+	fees.BaseFeeBurn = (*hexutil.Big)(burn)
+	fees.L1Cost = (*hexutil.Big)(l1Cost)
+	fees.Imbalance = (*hexutil.Big)(imbalance)
+	return fees
+}
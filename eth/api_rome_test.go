@@ -0,0 +1,129 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+func TestGetFeeBreakdown(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		config  = *params.TestChainConfig
+		db      = rawdb.NewMemoryDatabase()
+		genesis = &core.Genesis{Config: &config, Alloc: core.GenesisAlloc{
+			addr:                 {Balance: big.NewInt(params.Ether)},
+			common.Address{0xaa}: {Code: common.FromHex("0x600160005200")}, // mstore(0, 1), 12 gas
+		}}
+	)
+	config.BedrockBlock = common.Big0
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}
+
+	chain, err := core.NewBlockChain(db, nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	// Assemble a block with a transaction charged by the Rome gas vectors and
+	// one falling back to its own tip
+	signer := types.LatestSigner(&config)
+	txs := []*types.Transaction{
+		types.MustSignNewTx(key, signer, &types.DynamicFeeTx{ChainID: config.ChainID, Nonce: 0, To: &common.Address{0xaa}, Gas: 21000, GasFeeCap: big.NewInt(10), GasTipCap: big.NewInt(3)}),
+		types.MustSignNewTx(key, signer, &types.DynamicFeeTx{ChainID: config.ChainID, Nonce: 1, To: &common.Address{0xaa}, Gas: 21000, GasFeeCap: big.NewInt(10), GasTipCap: big.NewInt(3)}),
+	}
+	header := &types.Header{
+		ParentHash: chain.Genesis().Hash(),
+		Number:     big.NewInt(1),
+		Coinbase:   common.Address{0xcb},
+		GasLimit:   chain.Genesis().GasLimit(),
+		BaseFee:    big.NewInt(1),
+	}
+	block := types.NewBlock(header, txs, nil, nil, trie.NewStackTrie(nil))
+	rawdb.WriteBlock(db, block)
+	rawdb.WriteCanonicalHash(db, block.Hash(), 1)
+	chain.WriteRomeBlockData(block.Hash(), 1, &types.RomeBlockData{GasUsed: []uint64{30000}, GasPrice: []uint64{2}})
+
+	eth := &Ethereum{blockchain: chain, chainDb: db}
+	eth.APIBackend = &EthAPIBackend{eth: eth}
+
+	res, err := NewRomeAPI(eth).GetFeeBreakdown(context.Background(), rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+	if err != nil {
+		t.Fatalf("failed to retrieve fee breakdown: %v", err)
+	}
+	if len(res.Transactions) != 2 {
+		t.Fatalf("transaction count mismatch: have %d, want 2", len(res.Transactions))
+	}
+	tests := []struct {
+		fees                               *TxFeeBreakdown
+		evmGas, romeGas, feeGas            uint64
+		tip                                int64
+		fallback                           bool
+		charged, coinbase, burn, imbalance int64
+	}{
+		{res.Transactions[0], 12, 30000, 30000, 2, false, 60000, 60000, 12, 12},
+		{res.Transactions[1], 12, 0, 12, 3, true, 0, 36, 12, 48},
+	}
+	for i, tt := range tests {
+		fees := tt.fees
+		if fees.TxHash != txs[i].Hash() || uint64(fees.TxIndex) != uint64(i) || fees.From != addr {
+			t.Errorf("tx %d: identity mismatch: have %x/%d/%x", i, fees.TxHash, fees.TxIndex, fees.From)
+		}
+		if uint64(fees.EVMGasUsed) != tt.evmGas || uint64(fees.RomeGasUsed) != tt.romeGas || uint64(fees.FeeGasUsed) != tt.feeGas {
+			t.Errorf("tx %d: gas mismatch: have evm %d rome %d fee %d, want %d/%d/%d", i, fees.EVMGasUsed, fees.RomeGasUsed, fees.FeeGasUsed, tt.evmGas, tt.romeGas, tt.feeGas)
+		}
+		if fees.EffectiveTip.ToInt().Int64() != tt.tip || fees.TipFallback != tt.fallback {
+			t.Errorf("tx %d: tip mismatch: have %v (fallback %v), want %d (fallback %v)", i, fees.EffectiveTip, fees.TipFallback, tt.tip, tt.fallback)
+		}
+		for _, amount := range []struct {
+			name string
+			have *big.Int
+			want int64
+		}{
+			{"charged", fees.Charged.ToInt(), tt.charged},
+			{"refund", fees.Refund.ToInt(), 0},
+			{"coinbase credit", fees.CoinbaseCredit.ToInt(), tt.coinbase},
+			{"base fee burn", fees.BaseFeeBurn.ToInt(), tt.burn},
+			{"l1 cost", fees.L1Cost.ToInt(), 0},
+			{"imbalance", fees.Imbalance.ToInt(), tt.imbalance},
+		} {
+			if amount.have.Int64() != amount.want {
+				t.Errorf("tx %d: %s mismatch: have %v, want %d", i, amount.name, amount.have, amount.want)
+			}
+		}
+	}
+	if res.EVMGasUsed != 24 || res.RomeGasUsed != 30000 {
+		t.Errorf("block gas mismatch: have evm %d rome %d, want 24/30000", res.EVMGasUsed, res.RomeGasUsed)
+	}
+	if res.Charged.ToInt().Int64() != 60000 || res.CoinbaseCredit.ToInt().Int64() != 60036 || res.BaseFeeBurn.ToInt().Int64() != 24 || res.Imbalance.ToInt().Int64() != 60 {
+		t.Errorf("block totals mismatch: charged %v, coinbase %v, burn %v, imbalance %v", res.Charged, res.CoinbaseCredit, res.BaseFeeBurn, res.Imbalance)
+	}
+}
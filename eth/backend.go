@@ -44,8 +44,11 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/forwarder"
+	"github.com/ethereum/go-ethereum/eth/gasometer"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/eth/protocols/rome"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
@@ -79,8 +82,9 @@ type Ethereum struct {
 	snapDialCandidates enode.Iterator
 	merger             *consensus.Merger
 
-	seqRPCService        *rpc.Client
+	forwarder            *forwarder.Forwarder
 	historicalRPCService *rpc.Client
+	gasometer            gasometer.Gasometer
 
 	// DB interfaces
 	chainDb ethdb.Database // Block chain database
@@ -145,6 +149,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
+	if config.StateHistoryArchive && scheme != rawdb.PathScheme {
+		log.Warn("Historical state is only served from state history in path scheme", "scheme", scheme)
+	}
 	// Try to recover offline state pruning only in hash-based.
 	if scheme == rawdb.HashScheme {
 		if err := pruner.RecoverPruning(stack.ResolvePath(""), chainDb); err != nil {
@@ -210,6 +217,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			SnapshotLimit:       config.SnapshotCache,
 			Preimages:           config.Preimages,
 			StateHistory:        config.StateHistory,
+			HistoryLookback:     config.StateHistoryLookback,
 			StateScheme:         scheme,
 		}
 	)
@@ -238,7 +246,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	
 	// Initialize footprint manager
 	dataDir := stack.ResolvePath("")
-	manager := footprint.GetManager(dataDir)
+	manager := footprint.NewManager(chainDb, dataDir, config.RomeFootprintPolicy)
 	eth.blockchain.SetFootprintManager(manager)
 	
 	if chainConfig := eth.blockchain.Config(); chainConfig.Optimism != nil { // config.Genesis.Config.ChainID cannot be used because it's based on CLI flags only, thus default to mainnet L1
@@ -319,7 +327,8 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		if err != nil {
 			return nil, err
 		}
-		eth.seqRPCService = client
+		eth.forwarder = forwarder.New(config.RollupSequencerHTTP, client, config.RollupForwarding)
+		stack.RegisterLifecycle(eth.forwarder)
 	}
 
 	if config.RollupHistoricalRPC != "" {
@@ -332,6 +341,17 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		eth.historicalRPCService = client
 	}
 
+	// Set up the Rome gasometer, either served in-process or by the Rome
+	// gasometer service, connecting along with the node
+	if config.Gasometer.Local {
+		log.Info("Serving gasometer requests from local execution")
+		eth.gasometer = ethapi.NewLocalGasometer(eth.APIBackend)
+	} else {
+		client := gasometer.New(config.Gasometer)
+		stack.RegisterLifecycle(client)
+		eth.gasometer = client
+	}
+
 	// Start the RPC service
 	eth.netRPCService = ethapi.NewNetAPI(eth.p2pServer, networkID)
 
@@ -373,6 +393,19 @@ func (s *Ethereum) APIs() []rpc.API {
 		apis = append(apis, footprint.GetAPIs(manager)...)
 	}
 
+	// Append the forwarding introspection if forwarding to a sequencer
+	if s.forwarder != nil {
+		apis = append(apis, forwarder.GetAPIs(s.forwarder)...)
+	}
+
+	// Append the conditional transaction API if enabled
+	if s.config.RollupSequencerTxConditionalEnabled {
+		apis = append(apis, rpc.API{
+			Namespace: "eth",
+			Service:   ethapi.NewTransactionConditionalAPI(s.APIBackend, s.config.RollupSequencerTxConditionalCostRateLimit),
+		})
+	}
+
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
@@ -393,6 +426,9 @@ func (s *Ethereum) APIs() []rpc.API {
 		}, {
 			Namespace: "debug",
 			Service:   NewDebugAPI(s),
+		}, {
+			Namespace: "rome",
+			Service:   NewRomeAPI(s),
 		}, {
 			Namespace: "net",
 			Service:   s.netRPCService,
@@ -563,6 +599,7 @@ func (s *Ethereum) Protocols() []p2p.Protocol {
 	if s.config.SnapshotCache > 0 {
 		protos = append(protos, snap.MakeProtocols((*snapHandler)(s.handler), s.snapDialCandidates)...)
 	}
+	protos = append(protos, rome.MakeProtocols((*romeHandler)(s.handler))...)
 	return protos
 }
 
@@ -605,9 +642,6 @@ func (s *Ethereum) Stop() error {
 	s.miner.Close()
 	s.blockchain.Stop()
 	s.engine.Close()
-	if s.seqRPCService != nil {
-		s.seqRPCService.Close()
-	}
 	if s.historicalRPCService != nil {
 		s.historicalRPCService.Close()
 	}
@@ -28,8 +28,10 @@ import (
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/log"
@@ -91,6 +93,8 @@ var caps = []string{
 	"engine_newPayloadV3",
 	"engine_getPayloadBodiesByHashV1",
 	"engine_getPayloadBodiesByRangeV1",
+	"engine_getRomePayloadBodiesByHashV1",
+	"engine_getRomePayloadBodiesByRangeV1",
 }
 
 type ConsensusAPI struct {
@@ -175,7 +179,7 @@ func newConsensusAPIWithoutHeartbeat(eth *eth.Ethereum) *ConsensusAPI {
 //
 // If there are payloadAttributes: we try to assemble a block with the payloadAttributes
 // and return its payloadID.
-func (api *ConsensusAPI) ForkchoiceUpdatedV1(update engine.ForkchoiceStateV1, payloadAttributes *engine.RomePayloadAttributes) (engine.ForkChoiceResponse, error) {
+func (api *ConsensusAPI) ForkchoiceUpdatedV1(ctx context.Context, update engine.ForkchoiceStateV1, payloadAttributes *engine.RomePayloadAttributes) (engine.ForkChoiceResponse, error) {
 	if payloadAttributes != nil {
 		if payloadAttributes.Withdrawals != nil {
 			return engine.STATUS_INVALID, engine.InvalidParams.With(errors.New("withdrawals not supported in V1"))
@@ -184,27 +188,27 @@ func (api *ConsensusAPI) ForkchoiceUpdatedV1(update engine.ForkchoiceStateV1, pa
 			return engine.STATUS_INVALID, engine.InvalidParams.With(errors.New("forkChoiceUpdateV1 called post-shanghai"))
 		}
 	}
-	return api.forkchoiceUpdated(update, payloadAttributes)
+	return api.forkchoiceUpdated(ctx, update, payloadAttributes)
 }
 
 // ForkchoiceUpdatedV2 is equivalent to V1 with the addition of withdrawals in the payload attributes.
-func (api *ConsensusAPI) ForkchoiceUpdatedV2(update engine.ForkchoiceStateV1, payloadAttributes *engine.RomePayloadAttributes) (engine.ForkChoiceResponse, error) {
+func (api *ConsensusAPI) ForkchoiceUpdatedV2(ctx context.Context, update engine.ForkchoiceStateV1, payloadAttributes *engine.RomePayloadAttributes) (engine.ForkChoiceResponse, error) {
 	if payloadAttributes != nil {
 		if err := api.verifyPayloadAttributes(payloadAttributes); err != nil {
 			return engine.STATUS_INVALID, engine.InvalidParams.With(err)
 		}
 	}
-	return api.forkchoiceUpdated(update, payloadAttributes)
+	return api.forkchoiceUpdated(ctx, update, payloadAttributes)
 }
 
 // ForkchoiceUpdatedV3 is equivalent to V2 with the addition of parent beacon block root in the payload attributes.
-func (api *ConsensusAPI) ForkchoiceUpdatedV3(update engine.ForkchoiceStateV1, payloadAttributes *engine.RomePayloadAttributes) (engine.ForkChoiceResponse, error) {
+func (api *ConsensusAPI) ForkchoiceUpdatedV3(ctx context.Context, update engine.ForkchoiceStateV1, payloadAttributes *engine.RomePayloadAttributes) (engine.ForkChoiceResponse, error) {
 	if payloadAttributes != nil {
 		if err := api.verifyPayloadAttributes(payloadAttributes); err != nil {
 			return engine.STATUS_INVALID, engine.InvalidParams.With(err)
 		}
 	}
-	return api.forkchoiceUpdated(update, payloadAttributes)
+	return api.forkchoiceUpdated(ctx, update, payloadAttributes)
 }
 
 func (api *ConsensusAPI) verifyPayloadAttributes(attr *engine.RomePayloadAttributes) error {
@@ -227,7 +231,14 @@ func checkAttribute(active func(*big.Int, uint64) bool, exists bool, block *big.
 	return nil
 }
 
-func (api *ConsensusAPI) forkchoiceUpdated(update engine.ForkchoiceStateV1, payloadAttributes *engine.RomePayloadAttributes) (engine.ForkChoiceResponse, error) {
+func (api *ConsensusAPI) forkchoiceUpdated(ctx context.Context, update engine.ForkchoiceStateV1, payloadAttributes *engine.RomePayloadAttributes) (engine.ForkChoiceResponse, error) {
+	ctx, span := log.GetTracer().Start(ctx, "forkchoiceUpdated",
+		trace.WithAttributes(
+			attribute.String("head", update.HeadBlockHash.Hex()),
+			attribute.Bool("attributes", payloadAttributes != nil),
+		))
+	defer span.End()
+
 	api.forkchoiceLock.Lock()
 	defer api.forkchoiceLock.Unlock()
 
@@ -361,23 +372,44 @@ func (api *ConsensusAPI) forkchoiceUpdated(update engine.ForkchoiceStateV1, payl
 	// sealed by the beacon client. The payload will be requested later, and we
 	// will replace it arbitrarily many times in between.
 	if payloadAttributes != nil {
+		if manager := api.eth.BlockChain().GetFootprintManager(); manager != nil {
+			if err := manager.Halted(); err != nil {
+				log.Error("Refusing to build payload", "err", err)
+				return valid(nil), engine.InvalidPayloadAttributes.With(err)
+			}
+		}
 		if api.eth.BlockChain().Config().Optimism != nil && payloadAttributes.GasLimit == nil {
 			return engine.STATUS_INVALID, engine.InvalidPayloadAttributes.With(errors.New("gasLimit parameter is required"))
 		}
 		transactions := make(types.Transactions, 0, len(payloadAttributes.Transactions))
-		tracer := log.GetTracer()
 		for i, otx := range payloadAttributes.Transactions {
 			var tx types.Transaction
 			if err := tx.UnmarshalBinary(otx); err != nil {
 				return engine.STATUS_INVALID, fmt.Errorf("transaction %d is not valid: %v", i, err)
 			}
-			_, span := tracer.Start(context.Background(), "forkchoiceUpdated",
-				trace.WithAttributes(
-					attribute.String("tx_hash", tx.Hash().Hex()),
-					attribute.String("timestamp", time.Now().Format(time.RFC3339Nano)),
-				))
+			span.AddEvent("transaction", trace.WithAttributes(attribute.String("tx_hash", tx.Hash().Hex())))
 			transactions = append(transactions, &tx)
-			span.End()
+		}
+		var head vm.TxContext
+		core.ReadSolanaBlockContext(api.eth.ChainDb(), block.Header(), &head)
+		if err := verifyRomeAttributes(payloadAttributes, transactions, head); err != nil {
+			log.Warn("Invalid Rome payload attributes", "err", err)
+			return engine.STATUS_INVALID, engine.InvalidPayloadAttributes.With(err)
+		}
+		// Only store the Solana blockhashes once the attributes are known to be
+		// valid, the payload building serves them to BLOCKHASH
+		var first, last *uint64
+		if slots := payloadAttributes.SolanaBlockNumbers; len(slots) > 0 {
+			first, last = &slots[0], &slots[len(slots)-1]
+		}
+		blockhashes, err := verifySolanaBlockhashes(payloadAttributes.SolanaBlockhashes, first, last)
+		if err != nil {
+			log.Warn("Invalid Rome payload attributes", "err", err)
+			return engine.STATUS_INVALID, engine.InvalidPayloadAttributes.With(err)
+		}
+		if err := api.eth.BlockChain().WriteSolanaBlockhashes(blockhashes); err != nil {
+			log.Warn("Conflicting Solana blockhashes", "err", err)
+			return engine.STATUS_INVALID, engine.InvalidPayloadAttributes.With(err)
 		}
 
 		var solanaBlockNumbers []*uint64
@@ -421,7 +453,7 @@ func (api *ConsensusAPI) forkchoiceUpdated(update engine.ForkchoiceStateV1, payl
 		if api.localBlocks.has(id) {
 			return valid(&id), nil
 		}
-		payload, err := api.eth.Miner().BuildPayload(args)
+		payload, err := api.eth.Miner().BuildPayload(ctx, args)
 		if err != nil {
 			log.Error("Failed to build payload", "err", err)
 			return valid(nil), engine.InvalidPayloadAttributes.With(err)
@@ -491,15 +523,15 @@ func (api *ConsensusAPI) getPayload(payloadID engine.PayloadID, full bool) (*eng
 
 
 // NewPayloadV1 creates an Eth1 block, inserts it in the chain, and returns the status of the chain.
-func (api *ConsensusAPI) NewPayloadV1(params engine.RomeExecutableData) (engine.PayloadStatusV1, error) {
+func (api *ConsensusAPI) NewPayloadV1(ctx context.Context, params engine.RomeExecutableData) (engine.PayloadStatusV1, error) {
 	if params.Withdrawals != nil {
 		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("withdrawals not supported in V1"))
 	}
-	return api.newPayload(params, nil, nil)
+	return api.newPayload(ctx, params, nil, nil)
 }
 
 // NewPayloadV2 creates an Eth1 block, inserts it in the chain, and returns the status of the chain.
-func (api *ConsensusAPI) NewPayloadV2(params engine.RomeExecutableData) (engine.PayloadStatusV1, error) {
+func (api *ConsensusAPI) NewPayloadV2(ctx context.Context, params engine.RomeExecutableData) (engine.PayloadStatusV1, error) {
 	if api.eth.BlockChain().Config().IsShanghai(new(big.Int).SetUint64(params.Number), params.Timestamp) {
 		if params.Withdrawals == nil {
 			return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("nil withdrawals post-shanghai"))
@@ -507,11 +539,11 @@ func (api *ConsensusAPI) NewPayloadV2(params engine.RomeExecutableData) (engine.
 	} else if params.Withdrawals != nil {
 		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("non-nil withdrawals pre-shanghai"))
 	}
-	return api.newPayload(params, nil, nil)
+	return api.newPayload(ctx, params, nil, nil)
 }
 
 // NewPayloadV3 creates an Eth1 block, inserts it in the chain, and returns the status of the chain.
-func (api *ConsensusAPI) NewPayloadV3(params engine.RomeExecutableData, versionedHashes []common.Hash, beaconRoot *common.Hash) (engine.PayloadStatusV1, error) {
+func (api *ConsensusAPI) NewPayloadV3(ctx context.Context, params engine.RomeExecutableData, versionedHashes []common.Hash, beaconRoot *common.Hash) (engine.PayloadStatusV1, error) {
 	if params.ExcessBlobGas == nil {
 		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("nil excessBlobGas post-cancun"))
 	}
@@ -529,10 +561,17 @@ func (api *ConsensusAPI) NewPayloadV3(params engine.RomeExecutableData, versione
 		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.UnsupportedFork.With(errors.New("newPayloadV3 called pre-cancun"))
 	}
 
-	return api.newPayload(params, versionedHashes, beaconRoot)
+	return api.newPayload(ctx, params, versionedHashes, beaconRoot)
 }
 
-func (api *ConsensusAPI) newPayload(params engine.RomeExecutableData, versionedHashes []common.Hash, beaconRoot *common.Hash) (engine.PayloadStatusV1, error) {
+func (api *ConsensusAPI) newPayload(ctx context.Context, params engine.RomeExecutableData, versionedHashes []common.Hash, beaconRoot *common.Hash) (engine.PayloadStatusV1, error) {
+	ctx, span := log.GetTracer().Start(ctx, "newPayload",
+		trace.WithAttributes(
+			attribute.Int64("block_number", int64(params.Number)),
+			attribute.String("block_hash", params.BlockHash.Hex()),
+		))
+	defer span.End()
+
 	// The locking here is, strictly, not required. Without these locks, this can happen:
 	//
 	// 1. NewPayload( execdata-N ) is invoked from the CL. It goes all the way down to
@@ -607,8 +646,32 @@ func (api *ConsensusAPI) newPayload(params engine.RomeExecutableData, versionedH
 		return engine.PayloadStatusV1{Status: engine.ACCEPTED}, nil
 	}
 
+	var parentContext vm.TxContext
+	core.ReadSolanaBlockContext(api.eth.ChainDb(), parent.Header(), &parentContext)
+	if err := verifyRomePayload(&params, block.Transactions(), parentContext); err != nil {
+		log.Warn("Invalid Rome payload", "number", params.Number, "hash", params.BlockHash, "err", err)
+		return api.invalid(err, parent.Header()), nil
+	}
+	// The Solana blockhashes are only staged for the import of the block, they
+	// are persisted along with it if it turns out valid
+	first := parentContext.SolanaBlockNumber
+	if first == nil {
+		first = (*uint64)(params.SolanaBlockNumber)
+	}
+	blockhashes, err := verifySolanaBlockhashes(params.SolanaBlockhashes, first, (*uint64)(params.SolanaBlockNumber))
+	if err != nil {
+		log.Warn("Invalid Rome payload", "number", params.Number, "hash", params.BlockHash, "err", err)
+		return api.invalid(err, parent.Header()), nil
+	}
+	if len(blockhashes) > 0 {
+		if err := api.eth.BlockChain().StageRomeInputs(block.Hash(), &core.RomeInputs{Blockhashes: blockhashes}); err != nil {
+			log.Warn("Conflicting Solana blockhashes", "number", params.Number, "hash", params.BlockHash, "err", err)
+			return api.invalid(err, parent.Header()), nil
+		}
+		defer api.eth.BlockChain().UnstageRomeInputs(block.Hash())
+	}
 	log.Trace("Inserting block without sethead", "hash", block.Hash(), "number", block.Number)
-	if err := api.eth.BlockChain().InsertBlockWithoutSetHead(block, params.RomeGasUsed, params.TxFootprints, params.RomeGasPrice); err != nil {
+	if err := api.eth.BlockChain().InsertBlockWithoutSetHead(ctx, block, params.RomeGasUsed, params.TxFootprints, params.RomeGasPrice, true); err != nil {
 		log.Warn("NewPayloadV1: inserting block failed", "error", err)
 
 		api.invalidLock.Lock()
@@ -847,6 +910,100 @@ func (api *ConsensusAPI) GetPayloadBodiesByRangeV1(start, count hexutil.Uint64)
 	return bodies, nil
 }
 
+// GetRomePayloadBodiesByHashV1 implements engine_getRomePayloadBodiesByHashV1
+// which retrieves a list of block bodies together with the Rome execution inputs
+// and Solana metadata they were imported with.
+func (api *ConsensusAPI) GetRomePayloadBodiesByHashV1(hashes []common.Hash) []*engine.RomePayloadBodyV1 {
+	bodies := make([]*engine.RomePayloadBodyV1, len(hashes))
+	for i, hash := range hashes {
+		block := api.eth.BlockChain().GetBlockByHash(hash)
+		bodies[i] = api.getRomeBody(block)
+	}
+	return bodies
+}
+
+// GetRomePayloadBodiesByRangeV1 implements engine_getRomePayloadBodiesByRangeV1
+// which retrieves a range of canonical block bodies together with the Rome
+// execution inputs and Solana metadata they were imported with.
+func (api *ConsensusAPI) GetRomePayloadBodiesByRangeV1(start, count hexutil.Uint64) ([]*engine.RomePayloadBodyV1, error) {
+	if start == 0 || count == 0 {
+		return nil, engine.InvalidParams.With(fmt.Errorf("invalid start or count, start: %v count: %v", start, count))
+	}
+	if count > 1024 {
+		return nil, engine.TooLargeRequest.With(fmt.Errorf("requested count too large: %v", count))
+	}
+	// limit count up until current
+	current := api.eth.BlockChain().CurrentBlock().Number.Uint64()
+	last := uint64(start) + uint64(count) - 1
+	if last > current {
+		last = current
+	}
+	bodies := make([]*engine.RomePayloadBodyV1, 0, uint64(count))
+	for i := uint64(start); i <= last; i++ {
+		block := api.eth.BlockChain().GetBlockByNumber(i)
+		bodies = append(bodies, api.getRomeBody(block))
+	}
+	return bodies, nil
+}
+
+// getRomeBody assembles the body of a block along with its stored Rome gas
+// vectors, footprints, the Solana context of its transactions and the Solana
+// blockhashes in their reach. Vectors are padded to the number of transactions
+// with the defaults used on re-execution.
+func (api *ConsensusAPI) getRomeBody(block *types.Block) *engine.RomePayloadBodyV1 {
+	body := getBody(block)
+	if body == nil {
+		return nil
+	}
+	var (
+		chain    = api.eth.BlockChain()
+		txs      = block.Transactions()
+		romeData = chain.GetRomeBlockData(block.Hash(), block.NumberU64())
+		result   = &engine.RomePayloadBodyV1{
+			TransactionData: body.TransactionData,
+			Withdrawals:     body.Withdrawals,
+			RomeGasUsed:     make([]uint64, len(txs)),
+			RomeGasPrice:    make([]uint64, len(txs)),
+			TxFootprints:    make([]string, len(txs)),
+			SolanaTxs:       make([]*engine.SolanaTxMetadata, len(txs)),
+		}
+		minSlot uint64
+	)
+	for i, tx := range txs {
+		result.RomeGasUsed[i], result.RomeGasPrice[i] = romeData.TxGas(i)
+		result.TxFootprints[i] = romeData.TxFootprint(i)
+
+		slot, timestamp, ok := chain.GetSolanaTxMetadata(tx.Hash())
+		if !ok {
+			continue
+		}
+		result.SolanaTxs[i] = &engine.SolanaTxMetadata{
+			Slot:      hexutil.Uint64(slot),
+			Timestamp: hexutil.Uint64(timestamp),
+		}
+		if result.SolanaBlockNumber == nil || slot < minSlot {
+			minSlot = slot
+		}
+		if result.SolanaBlockNumber == nil || slot > uint64(*result.SolanaBlockNumber) {
+			result.SolanaBlockNumber = (*hexutil.Uint64)(&slot)
+		}
+	}
+	// Past the blockhash fork, BLOCKHASH reads the Solana blockhashes preceding
+	// the slots of the transactions, which are needed to replay the block
+	if result.SolanaBlockNumber != nil && chain.Config().IsRomeBlockhash(block.Time()) {
+		from := uint64(0)
+		if minSlot > solanaBlockhashWindow {
+			from = minSlot - solanaBlockhashWindow
+		}
+		for slot := from; slot < uint64(*result.SolanaBlockNumber); slot++ {
+			if hash := chain.GetSolanaBlockhash(slot); hash != (common.Hash{}) {
+				result.SolanaBlockhashes = append(result.SolanaBlockhashes, engine.SolanaBlockhash{Slot: hexutil.Uint64(slot), Hash: hash})
+			}
+		}
+	}
+	return result
+}
+
 func getBody(block *types.Block) *engine.ExecutionPayloadBodyV1 {
 	if block == nil {
 		return nil
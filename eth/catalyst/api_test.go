@@ -37,6 +37,8 @@ import (
 	beaconConsensus "github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/footprint"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
@@ -173,7 +175,7 @@ func TestSetHeadBeforeTotalDifficulty(t *testing.T) {
 		SafeBlockHash:      common.Hash{},
 		FinalizedBlockHash: common.Hash{},
 	}
-	if resp, err := api.ForkchoiceUpdatedV1(fcState, nil); err != nil {
+	if resp, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, nil); err != nil {
 		t.Errorf("fork choice updated should not error: %v", err)
 	} else if resp.PayloadStatus.Status != engine.INVALID_TERMINAL_BLOCK.Status {
 		t.Errorf("fork choice updated before total terminal difficulty should be INVALID")
@@ -200,7 +202,7 @@ func TestEth2PrepareAndGetPayload(t *testing.T) {
 		SafeBlockHash:      common.Hash{},
 		FinalizedBlockHash: common.Hash{},
 	}
-	resp, err := api.ForkchoiceUpdatedV1(fcState, &blockParams)
+	resp, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, &blockParams)
 	if err != nil {
 		t.Fatalf("error preparing payload, err=%v", err)
 	}
@@ -282,7 +284,7 @@ func TestInvalidPayloadTimestamp(t *testing.T) {
 				SafeBlockHash:      common.Hash{},
 				FinalizedBlockHash: common.Hash{},
 			}
-			_, err := api.ForkchoiceUpdatedV1(fcState, &params)
+			_, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, &params)
 			if test.shouldErr && err == nil {
 				t.Fatalf("expected error preparing payload with invalid timestamp, err=%v", err)
 			} else if !test.shouldErr && err != nil {
@@ -326,7 +328,7 @@ func TestEth2NewBlock(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to convert executable data to block %v", err)
 		}
-		newResp, err := api.NewPayloadV1(*execData)
+		newResp, err := api.NewPayloadV1(context.Background(), *execData)
 		switch {
 		case err != nil:
 			t.Fatalf("Failed to insert block: %v", err)
@@ -341,7 +343,7 @@ func TestEth2NewBlock(t *testing.T) {
 			SafeBlockHash:      block.Hash(),
 			FinalizedBlockHash: block.Hash(),
 		}
-		if _, err := api.ForkchoiceUpdatedV1(fcState, nil); err != nil {
+		if _, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, nil); err != nil {
 			t.Fatalf("Failed to insert block: %v", err)
 		}
 		if have, want := ethservice.BlockChain().CurrentBlock().Number.Uint64(), block.NumberU64(); have != want {
@@ -368,7 +370,7 @@ func TestEth2NewBlock(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to convert executable data to block %v", err)
 		}
-		newResp, err := api.NewPayloadV1(*execData)
+		newResp, err := api.NewPayloadV1(context.Background(), *execData)
 		if err != nil || newResp.Status != "VALID" {
 			t.Fatalf("Failed to insert block: %v", err)
 		}
@@ -381,7 +383,7 @@ func TestEth2NewBlock(t *testing.T) {
 			SafeBlockHash:      block.Hash(),
 			FinalizedBlockHash: block.Hash(),
 		}
-		if _, err := api.ForkchoiceUpdatedV1(fcState, nil); err != nil {
+		if _, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, nil); err != nil {
 			t.Fatalf("Failed to insert block: %v", err)
 		}
 		if ethservice.BlockChain().CurrentBlock().Number.Uint64() != block.NumberU64() {
@@ -505,7 +507,7 @@ func setupBlocks(t *testing.T, ethservice *eth.Ethereum, n int, parent *types.He
 		}
 
 		payload := getNewPayload(t, api, parent, w)
-		execResp, err := api.NewPayloadV2(*payload)
+		execResp, err := api.NewPayloadV2(context.Background(), *payload)
 		if err != nil {
 			t.Fatalf("can't execute payload: %v", err)
 		}
@@ -517,7 +519,7 @@ func setupBlocks(t *testing.T, ethservice *eth.Ethereum, n int, parent *types.He
 			SafeBlockHash:      payload.ParentHash,
 			FinalizedBlockHash: payload.ParentHash,
 		}
-		if _, err := api.ForkchoiceUpdatedV1(fcState, nil); err != nil {
+		if _, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, nil); err != nil {
 			t.Fatalf("Failed to insert block: %v", err)
 		}
 		if ethservice.BlockChain().CurrentBlock().Number.Uint64() != payload.Number {
@@ -631,7 +633,7 @@ func TestNewPayloadOnInvalidChain(t *testing.T) {
 			err     error
 		)
 		for i := 0; ; i++ {
-			if resp, err = api.ForkchoiceUpdatedV1(fcState, &params); err != nil {
+			if resp, err = api.ForkchoiceUpdatedV1(context.Background(), fcState, &params); err != nil {
 				t.Fatalf("error preparing payload, err=%v", err)
 			}
 			if resp.PayloadStatus.Status != engine.VALID {
@@ -650,7 +652,7 @@ func TestNewPayloadOnInvalidChain(t *testing.T) {
 				t.Fatalf("payload should not be empty")
 			}
 		}
-		execResp, err := api.NewPayloadV1(*payload)
+		execResp, err := api.NewPayloadV1(context.Background(), *payload)
 		if err != nil {
 			t.Fatalf("can't execute payload: %v", err)
 		}
@@ -662,7 +664,7 @@ func TestNewPayloadOnInvalidChain(t *testing.T) {
 			SafeBlockHash:      payload.ParentHash,
 			FinalizedBlockHash: payload.ParentHash,
 		}
-		if _, err := api.ForkchoiceUpdatedV1(fcState, nil); err != nil {
+		if _, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, nil); err != nil {
 			t.Fatalf("Failed to insert block: %v", err)
 		}
 		if ethservice.BlockChain().CurrentBlock().Number.Uint64() != payload.Number {
@@ -681,7 +683,7 @@ func assembleBlock(api *ConsensusAPI, parentHash common.Hash, params *engine.Rom
 		Withdrawals:  params.Withdrawals,
 		BeaconRoot:   params.BeaconRoot,
 	}
-	payload, err := api.eth.Miner().BuildPayload(args)
+	payload, err := api.eth.Miner().BuildPayload(context.Background(), args)
 	if err != nil {
 		return nil, err
 	}
@@ -703,7 +705,7 @@ func TestEmptyBlocks(t *testing.T) {
 	// (1) check LatestValidHash by sending a normal payload (P1'')
 	payload := getNewPayload(t, api, commonAncestor, nil)
 
-	status, err := api.NewPayloadV1(*payload)
+	status, err := api.NewPayloadV1(context.Background(), *payload)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -719,7 +721,7 @@ func TestEmptyBlocks(t *testing.T) {
 	payload.GasUsed += 1
 	payload = setBlockhash(payload)
 	// Now latestValidHash should be the common ancestor
-	status, err = api.NewPayloadV1(*payload)
+	status, err = api.NewPayloadV1(context.Background(), *payload)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -737,7 +739,7 @@ func TestEmptyBlocks(t *testing.T) {
 	payload.ParentHash = common.Hash{1}
 	payload = setBlockhash(payload)
 	// Now latestValidHash should be the common ancestor
-	status, err = api.NewPayloadV1(*payload)
+	status, err = api.NewPayloadV1(context.Background(), *payload)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -849,7 +851,7 @@ func TestTrickRemoteBlockCache(t *testing.T) {
 
 	// feed the payloads to node B
 	for _, payload := range invalidChain {
-		status, err := apiB.NewPayloadV1(*payload)
+		status, err := apiB.NewPayloadV1(context.Background(), *payload)
 		if err != nil {
 			panic(err)
 		}
@@ -857,7 +859,7 @@ func TestTrickRemoteBlockCache(t *testing.T) {
 			t.Error("invalid status: VALID on an invalid chain")
 		}
 		// Now reorg to the head of the invalid chain
-		resp, err := apiB.ForkchoiceUpdatedV1(engine.ForkchoiceStateV1{HeadBlockHash: payload.BlockHash, SafeBlockHash: payload.BlockHash, FinalizedBlockHash: payload.ParentHash}, nil)
+		resp, err := apiB.ForkchoiceUpdatedV1(context.Background(), engine.ForkchoiceStateV1{HeadBlockHash: payload.BlockHash, SafeBlockHash: payload.BlockHash, FinalizedBlockHash: payload.ParentHash}, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -883,7 +885,7 @@ func TestInvalidBloom(t *testing.T) {
 	// (1) check LatestValidHash by sending a normal payload (P1'')
 	payload := getNewPayload(t, api, commonAncestor, nil)
 	payload.LogsBloom = append(payload.LogsBloom, byte(1))
-	status, err := api.NewPayloadV1(*payload)
+	status, err := api.NewPayloadV1(context.Background(), *payload)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -905,7 +907,7 @@ func TestNewPayloadOnInvalidTerminalBlock(t *testing.T) {
 		SafeBlockHash:      common.Hash{},
 		FinalizedBlockHash: common.Hash{},
 	}
-	resp, err := api.ForkchoiceUpdatedV1(fcState, nil)
+	resp, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, nil)
 	if err != nil {
 		t.Fatalf("error sending forkchoice, err=%v", err)
 	}
@@ -920,7 +922,7 @@ func TestNewPayloadOnInvalidTerminalBlock(t *testing.T) {
 		Random:       crypto.Keccak256Hash([]byte{byte(1)}),
 		FeeRecipient: parent.Coinbase(),
 	}
-	payload, err := api.eth.Miner().BuildPayload(args)
+	payload, err := api.eth.Miner().BuildPayload(context.Background(), args)
 	if err != nil {
 		t.Fatalf("error preparing payload, err=%v", err)
 	}
@@ -948,7 +950,7 @@ func TestNewPayloadOnInvalidTerminalBlock(t *testing.T) {
 	block := types.NewBlockWithHeader(header).WithBody(txs, nil /* uncles */)
 	data.BlockHash = block.Hash()
 	// Send the new payload
-	resp2, err := api.NewPayloadV1(data)
+	resp2, err := api.NewPayloadV1(context.Background(), data)
 	if err != nil {
 		t.Fatalf("error sending NewPayload, err=%v", err)
 	}
@@ -987,7 +989,7 @@ func TestSimultaneousNewBlock(t *testing.T) {
 			for ii := 0; ii < 10; ii++ {
 				go func() {
 					defer wg.Done()
-					if newResp, err := api.NewPayloadV1(*execData); err != nil {
+					if newResp, err := api.NewPayloadV1(context.Background(), *execData); err != nil {
 						errMu.Lock()
 						testErr = fmt.Errorf("Failed to insert block: %w", err)
 						errMu.Unlock()
@@ -1026,7 +1028,7 @@ func TestSimultaneousNewBlock(t *testing.T) {
 			for ii := 0; ii < 10; ii++ {
 				go func() {
 					defer wg.Done()
-					if _, err := api.ForkchoiceUpdatedV1(fcState, nil); err != nil {
+					if _, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, nil); err != nil {
 						errMu.Lock()
 						testErr = fmt.Errorf("Failed to insert block: %w", err)
 						errMu.Unlock()
@@ -1068,7 +1070,7 @@ func TestWithdrawals(t *testing.T) {
 	fcState := engine.ForkchoiceStateV1{
 		HeadBlockHash: parent.Hash(),
 	}
-	resp, err := api.ForkchoiceUpdatedV2(fcState, &blockParams)
+	resp, err := api.ForkchoiceUpdatedV2(context.Background(), fcState, &blockParams)
 	if err != nil {
 		t.Fatalf("error preparing payload, err=%v", err)
 	}
@@ -1096,7 +1098,7 @@ func TestWithdrawals(t *testing.T) {
 	}
 
 	// 10: verify locally built block
-	if status, err := api.NewPayloadV2(*execData.ExecutionPayload); err != nil {
+	if status, err := api.NewPayloadV2(context.Background(), *execData.ExecutionPayload); err != nil {
 		t.Fatalf("error validating payload: %v", err)
 	} else if status.Status != engine.VALID {
 		t.Fatalf("invalid payload")
@@ -1121,7 +1123,7 @@ func TestWithdrawals(t *testing.T) {
 		},
 	}
 	fcState.HeadBlockHash = execData.ExecutionPayload.BlockHash
-	resp, err = api.ForkchoiceUpdatedV2(fcState, &blockParams)
+	resp, err = api.ForkchoiceUpdatedV2(context.Background(), fcState, &blockParams)
 	if err != nil {
 		t.Fatalf("error preparing payload, err=%v", err)
 	}
@@ -1141,7 +1143,7 @@ func TestWithdrawals(t *testing.T) {
 	if err != nil {
 		t.Fatalf("error getting payload, err=%v", err)
 	}
-	if status, err := api.NewPayloadV2(*execData.ExecutionPayload); err != nil {
+	if status, err := api.NewPayloadV2(context.Background(), *execData.ExecutionPayload); err != nil {
 		t.Fatalf("error validating payload: %v", err)
 	} else if status.Status != engine.VALID {
 		t.Fatalf("invalid payload")
@@ -1149,7 +1151,7 @@ func TestWithdrawals(t *testing.T) {
 
 	// 11: set block as head.
 	fcState.HeadBlockHash = execData.ExecutionPayload.BlockHash
-	_, err = api.ForkchoiceUpdatedV2(fcState, nil)
+	_, err = api.ForkchoiceUpdatedV2(context.Background(), fcState, nil)
 	if err != nil {
 		t.Fatalf("error preparing payload, err=%v", err)
 	}
@@ -1249,7 +1251,7 @@ func TestNilWithdrawals(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := api.ForkchoiceUpdatedV2(fcState, &test.blockParams)
+		resp, err := api.ForkchoiceUpdatedV2(context.Background(), fcState, &test.blockParams)
 		if test.wantErr {
 			if err == nil {
 				t.Fatal("wanted error on fcuv2 with invalid withdrawals")
@@ -1275,7 +1277,7 @@ func TestNilWithdrawals(t *testing.T) {
 		if err != nil {
 			t.Fatalf("error getting payload, err=%v", err)
 		}
-		if status, err := api.NewPayloadV2(*execData.ExecutionPayload); err != nil {
+		if status, err := api.NewPayloadV2(context.Background(), *execData.ExecutionPayload); err != nil {
 			t.Fatalf("error validating payload: %v", err)
 		} else if status.Status != engine.VALID {
 			t.Fatalf("invalid payload")
@@ -1519,6 +1521,169 @@ func TestGetBlockBodiesByRangeInvalidParams(t *testing.T) {
 	}
 }
 
+func TestGetRomePayloadBodies(t *testing.T) {
+	genesis, _ := generateMergeChain(0, true)
+	genesis.Config.Rome = &params.RomeConfig{BlockhashTime: new(uint64)}
+	n, ethservice := startEthService(t, genesis, nil)
+	defer n.Close()
+	api := NewConsensusAPI(ethservice)
+
+	// Store a canonical block with Rome execution inputs and Solana metadata
+	// for its first transaction only.
+	var (
+		db  = ethservice.ChainDb()
+		txs = []*types.Transaction{
+			types.NewTx(&types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(1)}),
+			types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1)}),
+		}
+		block    = types.NewBlock(&types.Header{Number: big.NewInt(1), ParentHash: ethservice.BlockChain().Genesis().Hash()}, txs, nil, nil, trie.NewStackTrie(nil))
+		romeData = &types.RomeBlockData{GasUsed: []uint64{21000, 30000}, GasPrice: []uint64{7, 8}, Footprints: []string{"0x1", "0x2"}}
+	)
+	rawdb.WriteBlock(db, block)
+	rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+	ethservice.BlockChain().WriteRomeBlockData(block.Hash(), block.NumberU64(), romeData)
+	rawdb.WriteSolanaTxMetadata(db, txs[0].Hash(), 4242, 1700000000)
+
+	// Only the blockhashes in reach of BLOCKHASH from the transactions are served
+	rawdb.WriteSolanaBlockhash(db, 3985, common.Hash{0x01})
+	rawdb.WriteSolanaBlockhash(db, 3986, common.Hash{0x02})
+	rawdb.WriteSolanaBlockhash(db, 4241, common.Hash{0x03})
+	rawdb.WriteSolanaBlockhash(db, 4242, common.Hash{0x04})
+
+	bodies := api.GetRomePayloadBodiesByHashV1([]common.Hash{block.Hash(), {1, 2}, ethservice.BlockChain().Genesis().Hash()})
+	if len(bodies) != 3 {
+		t.Fatalf("invalid number of bodies: have %d, want 3", len(bodies))
+	}
+	if bodies[1] != nil {
+		t.Fatalf("unknown block has a body: %+v", bodies[1])
+	}
+	body := bodies[0]
+	if !equalBody(block.Body(), &engine.ExecutionPayloadBodyV1{TransactionData: body.TransactionData, Withdrawals: body.Withdrawals}) {
+		t.Fatalf("body mismatch: have %+v", body)
+	}
+	if !reflect.DeepEqual(body.RomeGasUsed, romeData.GasUsed) || !reflect.DeepEqual(body.RomeGasPrice, romeData.GasPrice) || !reflect.DeepEqual(body.TxFootprints, romeData.Footprints) {
+		t.Fatalf("rome vectors mismatch: have %v/%v/%v, want %v/%v/%v", body.RomeGasUsed, body.RomeGasPrice, body.TxFootprints, romeData.GasUsed, romeData.GasPrice, romeData.Footprints)
+	}
+	if body.SolanaBlockNumber == nil || *body.SolanaBlockNumber != 4242 {
+		t.Fatalf("solana block number mismatch: have %v, want 4242", body.SolanaBlockNumber)
+	}
+	if meta := body.SolanaTxs[0]; meta == nil || meta.Slot != 4242 || meta.Timestamp != 1700000000 {
+		t.Fatalf("solana tx metadata mismatch: have %+v", meta)
+	}
+	if meta := body.SolanaTxs[1]; meta != nil {
+		t.Fatalf("unknown solana tx metadata reported: %+v", meta)
+	}
+	want := []engine.SolanaBlockhash{{Slot: 3986, Hash: common.Hash{0x02}}, {Slot: 4241, Hash: common.Hash{0x03}}}
+	if !reflect.DeepEqual(body.SolanaBlockhashes, want) {
+		t.Fatalf("solana blockhashes mismatch: have %v, want %v", body.SolanaBlockhashes, want)
+	}
+	if genesis := bodies[2]; len(genesis.TransactionData) != 0 || len(genesis.RomeGasUsed) != 0 || genesis.SolanaBlockNumber != nil {
+		t.Fatalf("genesis body mismatch: have %+v", genesis)
+	}
+	// Range requests are capped at the current head.
+	ranged, err := api.GetRomePayloadBodiesByRangeV1(1, 2)
+	if err != nil {
+		t.Fatalf("range request failed: %v", err)
+	}
+	if len(ranged) != 0 {
+		t.Fatalf("range beyond head returned bodies: %+v", ranged)
+	}
+	if _, err := api.GetRomePayloadBodiesByRangeV1(0, 1); err == nil {
+		t.Fatal("expected error for invalid range")
+	}
+	if _, err := api.GetRomePayloadBodiesByRangeV1(1, 1025); err == nil {
+		t.Fatal("expected error for too large range")
+	}
+}
+
+// Tests that the footprint mismatch policy is enforced on the payloads imported
+// through the engine API: a block whose execution does not match its submitted
+// footprint is invalid under the reject and halt policies, the latter refusing
+// to build payloads until sequencing is resumed.
+func TestFootprintMismatchPolicy(t *testing.T) {
+	tests := []struct {
+		policy footprint.Policy
+		status string
+		halt   bool
+	}{
+		{footprint.PolicyLog, engine.VALID, false},
+		{footprint.PolicyAlert, engine.VALID, false},
+		{footprint.PolicyReject, engine.INVALID, false},
+		{footprint.PolicyHalt, engine.INVALID, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			testFootprintMismatchPolicy(t, tt.policy, tt.status, tt.halt)
+		})
+	}
+}
+
+func testFootprintMismatchPolicy(t *testing.T, policy footprint.Policy, status string, halt bool) {
+	genesis, _ := generateMergeChain(0, true)
+
+	// Build a block on a separate node, the footprints recorded while building
+	// take precedence over the submitted ones on import
+	builder, builderService := startEthService(t, genesis, nil)
+	defer builder.Close()
+
+	var (
+		parent = builderService.BlockChain().CurrentBlock()
+		signer = types.LatestSigner(genesis.Config)
+		tx, _  = types.SignTx(types.NewTransaction(0, common.Address{0x01}, big.NewInt(1), params.TxGas, big.NewInt(1), nil), signer, testKey)
+	)
+	payload, err := builderService.Miner().BuildPayload(context.Background(), &miner.BuildPayloadArgs{
+		Parent:       parent.Hash(),
+		Timestamp:    parent.Time + 1,
+		FeeRecipient: testAddr,
+		NoTxPool:     true,
+		Transactions: []*types.Transaction{tx},
+		GasPrice:     []uint64{1},
+		GasUsed:      []uint64{params.TxGas},
+	})
+	if err != nil {
+		t.Fatalf("failed to build payload: %v", err)
+	}
+	waitForPayloadToBuild(payload)
+	data := payload.ResolveFull().ExecutionPayload
+	if len(data.Transactions) != 1 {
+		t.Fatalf("payload transactions mismatch: have %d, want 1", len(data.Transactions))
+	}
+	ethcfg := &ethconfig.Config{Genesis: genesis, SyncMode: downloader.FullSync, TrieTimeout: time.Minute, TrieDirtyCache: 256, TrieCleanCache: 256, RomeFootprintPolicy: policy}
+	n, ethservice := startEthServiceWithConfigFn(t, nil, ethcfg)
+	defer n.Close()
+	api := NewConsensusAPI(ethservice)
+
+	// Import it with a footprint its execution does not match
+	data.RomeGasUsed, data.RomeGasPrice, data.TxFootprints = []uint64{params.TxGas}, []uint64{1}, []string{romeTestHash}
+
+	res, err := api.NewPayloadV2(context.Background(), *data)
+	if err != nil {
+		t.Fatalf("failed to import payload: %v", err)
+	}
+	if res.Status != status {
+		t.Fatalf("payload status mismatch: have %s, want %s", res.Status, status)
+	}
+	manager := ethservice.BlockChain().GetFootprintManager()
+	if manager.Mismatch(tx.Hash()) == nil {
+		t.Fatal("footprint mismatch not recorded")
+	}
+	if halted := manager.Halted() != nil; halted != halt {
+		t.Fatalf("halt mismatch: have %t, want %t", halted, halt)
+	}
+	// Payloads are only refused to be built while halted
+	fcState := engine.ForkchoiceStateV1{HeadBlockHash: parent.Hash()}
+	attrs := &engine.RomePayloadAttributes{Timestamp: parent.Time + 1, SuggestedFeeRecipient: testAddr, GasPrice: []uint64{}, GasUsed: []uint64{}}
+	if _, err := api.ForkchoiceUpdatedV2(context.Background(), fcState, attrs); (err != nil) != halt {
+		t.Fatalf("payload building error mismatch: have %v, want error %t", err, halt)
+	}
+	if halt {
+		manager.Resume()
+		if _, err := api.ForkchoiceUpdatedV2(context.Background(), fcState, attrs); err != nil {
+			t.Fatalf("payload building refused after resuming: %v", err)
+		}
+	}
+}
+
 func equalBody(a *types.Body, b *engine.ExecutionPayloadBodyV1) bool {
 	if a == nil && b == nil {
 		return true
@@ -1602,7 +1767,7 @@ func TestParentBeaconBlockRoot(t *testing.T) {
 	fcState := engine.ForkchoiceStateV1{
 		HeadBlockHash: parent.Hash(),
 	}
-	resp, err := api.ForkchoiceUpdatedV2(fcState, &blockParams)
+	resp, err := api.ForkchoiceUpdatedV2(context.Background(), fcState, &blockParams)
 	if err != nil {
 		t.Fatalf("error preparing payload, err=%v", err.(*engine.EngineAPIError).ErrorData())
 	}
@@ -1627,14 +1792,14 @@ func TestParentBeaconBlockRoot(t *testing.T) {
 	}
 
 	// 11: verify locally built block
-	if status, err := api.NewPayloadV3(*execData.ExecutionPayload, []common.Hash{}, &common.Hash{42}); err != nil {
+	if status, err := api.NewPayloadV3(context.Background(), *execData.ExecutionPayload, []common.Hash{}, &common.Hash{42}); err != nil {
 		t.Fatalf("error validating payload: %v", err)
 	} else if status.Status != engine.VALID {
 		t.Fatalf("invalid payload")
 	}
 
 	fcState.HeadBlockHash = execData.ExecutionPayload.BlockHash
-	resp, err = api.ForkchoiceUpdatedV3(fcState, nil)
+	resp, err = api.ForkchoiceUpdatedV3(context.Background(), fcState, nil)
 	if err != nil {
 		t.Fatalf("error preparing payload, err=%v", err.(*engine.EngineAPIError).ErrorData())
 	}
@@ -0,0 +1,192 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package catalyst
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// verifyRomeAttributes checks that the Rome execution vectors of the payload
+// attributes line up with the forced transactions and are well formed. The
+// gas and Solana vectors may be longer than the forced transactions if the
+// transaction pool is used, as pool transactions consume the trailing entries.
+// The Solana context of the head block, if known, bounds the first slot and
+// timestamp from below.
+func verifyRomeAttributes(attr *engine.RomePayloadAttributes, txs types.Transactions, head vm.TxContext) error {
+	if len(attr.GasUsed) != len(attr.GasPrice) {
+		return fmt.Errorf("gasUsed and gasPrices length mismatch: %d != %d", len(attr.GasUsed), len(attr.GasPrice))
+	}
+	if attr.NoTxPool && len(attr.GasUsed) != len(txs) {
+		return fmt.Errorf("gasUsed length mismatch: have %d, want %d", len(attr.GasUsed), len(txs))
+	}
+	if len(attr.GasUsed) < len(txs) {
+		return fmt.Errorf("gasUsed too short: have %d, want at least %d", len(attr.GasUsed), len(txs))
+	}
+	if err := verifyRomeGasPrices(attr.GasUsed, attr.GasPrice, txs); err != nil {
+		return err
+	}
+	if err := verifyFootprints(attr.TxFootprints, len(txs)); err != nil {
+		return err
+	}
+	slots, timestamps := attr.SolanaBlockNumbers, attr.SolanaTimestamps
+	if len(slots) == 0 && len(timestamps) == 0 {
+		return nil
+	}
+	if len(slots) != len(attr.GasUsed) {
+		return fmt.Errorf("solanaBlockNumbers length mismatch: have %d, want %d", len(slots), len(attr.GasUsed))
+	}
+	if len(timestamps) != len(attr.GasUsed) {
+		return fmt.Errorf("solanaTimestamps length mismatch: have %d, want %d", len(timestamps), len(attr.GasUsed))
+	}
+	for i := range slots {
+		if timestamps[i] < 0 {
+			return fmt.Errorf("negative solana timestamp %d for transaction %d", timestamps[i], i)
+		}
+		if i == 0 {
+			if head.SolanaBlockNumber != nil && slots[0] < *head.SolanaBlockNumber {
+				return fmt.Errorf("solana slot %d of transaction 0 precedes head slot %d", slots[0], *head.SolanaBlockNumber)
+			}
+			if head.SolanaTimestamp != nil && timestamps[0] < *head.SolanaTimestamp {
+				return fmt.Errorf("solana timestamp %d of transaction 0 precedes head timestamp %d", timestamps[0], *head.SolanaTimestamp)
+			}
+			continue
+		}
+		if slots[i] < slots[i-1] {
+			return fmt.Errorf("solana slot %d of transaction %d precedes slot %d of transaction %d", slots[i], i, slots[i-1], i-1)
+		}
+		if timestamps[i] < timestamps[i-1] {
+			return fmt.Errorf("solana timestamp %d of transaction %d precedes timestamp %d of transaction %d", timestamps[i], i, timestamps[i-1], i-1)
+		}
+	}
+	return nil
+}
+
+// verifyRomePayload checks that the Rome execution vectors of an execution
+// payload match its transactions one to one and are well formed. The Solana
+// slot of the payload may not precede the one of its parent.
+func verifyRomePayload(params *engine.RomeExecutableData, txs types.Transactions, parent vm.TxContext) error {
+	if len(params.RomeGasUsed) != len(txs) {
+		return fmt.Errorf("romeGasUsed length mismatch: have %d, want %d", len(params.RomeGasUsed), len(txs))
+	}
+	if len(params.RomeGasPrice) != len(txs) {
+		return fmt.Errorf("romeGasPrice length mismatch: have %d, want %d", len(params.RomeGasPrice), len(txs))
+	}
+	if err := verifyRomeGasPrices(params.RomeGasUsed, params.RomeGasPrice, txs); err != nil {
+		return err
+	}
+	if err := verifyFootprints(params.TxFootprints, len(txs)); err != nil {
+		return err
+	}
+	if params.SolanaBlockNumber != nil && parent.SolanaBlockNumber != nil && uint64(*params.SolanaBlockNumber) < *parent.SolanaBlockNumber {
+		return fmt.Errorf("solana block number %d precedes parent slot %d", uint64(*params.SolanaBlockNumber), *parent.SolanaBlockNumber)
+	}
+	return nil
+}
+
+// solanaBlockhashWindow is the number of Solana slots preceding the slot of a
+// transaction whose blockhashes are served by BLOCKHASH.
+const solanaBlockhashWindow = 256
+
+// verifySolanaBlockhashes checks that the Solana blockhashes delivered by the
+// consensus client are set and in reach of BLOCKHASH from transactions executed
+// in the slots first to last, returning them by slot. Blockhashes may only be
+// delivered along with the slots of the transactions referencing them.
+func verifySolanaBlockhashes(blockhashes []engine.SolanaBlockhash, first, last *uint64) (map[uint64]common.Hash, error) {
+	if len(blockhashes) == 0 {
+		return nil, nil
+	}
+	if first == nil || last == nil {
+		return nil, errors.New("solana blockhashes without solana slots")
+	}
+	var from uint64
+	if *first > solanaBlockhashWindow {
+		from = *first - solanaBlockhashWindow
+	}
+	hashes := make(map[uint64]common.Hash, len(blockhashes))
+	for _, blockhash := range blockhashes {
+		slot := uint64(blockhash.Slot)
+		if blockhash.Hash == (common.Hash{}) {
+			return nil, fmt.Errorf("empty blockhash for solana slot %d", slot)
+		}
+		if slot < from || slot >= *last {
+			return nil, fmt.Errorf("blockhash for solana slot %d out of reach [%d, %d)", slot, from, *last)
+		}
+		if known, ok := hashes[slot]; ok && known != blockhash.Hash {
+			return nil, fmt.Errorf("conflicting blockhashes for solana slot %d", slot)
+		}
+		hashes[slot] = blockhash.Hash
+	}
+	return hashes, nil
+}
+
+// verifyRomeGasPrices rejects entries charging gas at a zero price. Only
+// deposit transactions, which are paid for on L1, may consume gas for free.
+func verifyRomeGasPrices(gasUsed, gasPrice []uint64, txs types.Transactions) error {
+	for i := range gasUsed {
+		if gasUsed[i] == 0 || gasPrice[i] != 0 {
+			continue
+		}
+		if i < len(txs) && txs[i].IsDepositTx() {
+			continue
+		}
+		return fmt.Errorf("zero gas price for transaction %d using %d gas", i, gasUsed[i])
+	}
+	return nil
+}
+
+// verifyFootprints checks that the footprints, if any, cover every forced
+// transaction and are 0x-prefixed hex strings of at most 32 bytes.
+func verifyFootprints(footprints []string, n int) error {
+	if len(footprints) == 0 {
+		return nil
+	}
+	if len(footprints) != n {
+		return fmt.Errorf("txFootprints length mismatch: have %d, want %d", len(footprints), n)
+	}
+	for i, footprint := range footprints {
+		if err := verifyFootprint(footprint); err != nil {
+			return fmt.Errorf("invalid footprint %q for transaction %d: %w", footprint, i, err)
+		}
+	}
+	return nil
+}
+
+// verifyFootprint checks the format of a single footprint.
+func verifyFootprint(footprint string) error {
+	if len(footprint) < 2 || footprint[0] != '0' || (footprint[1] != 'x' && footprint[1] != 'X') {
+		return errors.New("missing 0x prefix")
+	}
+	digits := footprint[2:]
+	if len(digits) == 0 {
+		return errors.New("empty hex string")
+	}
+	if len(digits) > 64 {
+		return errors.New("longer than 32 bytes")
+	}
+	for _, c := range digits {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return fmt.Errorf("invalid hex character %q", c)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package catalyst
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+var (
+	romeTestTxs = types.Transactions{
+		types.NewTx(&types.LegacyTx{Nonce: 0}),
+		types.NewTx(&types.LegacyTx{Nonce: 1}),
+	}
+	romeTestDeposit = types.Transactions{types.NewTx(&types.DepositTx{})}
+	romeTestHash    = common.HexToHash("0x1234").Hex()
+)
+
+func TestVerifyRomeAttributes(t *testing.T) {
+	headSlot, headTimestamp := uint64(100), int64(1700000000)
+	head := vm.TxContext{SolanaBlockNumber: &headSlot, SolanaTimestamp: &headTimestamp}
+
+	tests := []struct {
+		name string
+		attr engine.RomePayloadAttributes
+		txs  types.Transactions
+		head vm.TxContext
+		err  string
+	}{
+		{
+			name: "empty",
+			attr: engine.RomePayloadAttributes{NoTxPool: true},
+		},
+		{
+			name: "valid",
+			attr: engine.RomePayloadAttributes{
+				NoTxPool:           true,
+				GasUsed:            []uint64{21000, 30000},
+				GasPrice:           []uint64{1, 2},
+				TxFootprints:       []string{"0x0", romeTestHash},
+				SolanaBlockNumbers: []uint64{100, 101},
+				SolanaTimestamps:   []int64{1700000000, 1700000000},
+			},
+			txs:  romeTestTxs,
+			head: head,
+		},
+		{
+			name: "pool entries",
+			attr: engine.RomePayloadAttributes{GasUsed: []uint64{21000, 21000, 21000}, GasPrice: []uint64{1, 1, 1}},
+			txs:  romeTestTxs,
+		},
+		{
+			name: "gas vector mismatch",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{21000, 21000}, GasPrice: []uint64{1}},
+			txs:  romeTestTxs,
+			err:  "gasUsed and gasPrices length mismatch: 2 != 1",
+		},
+		{
+			name: "missing gas entries",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{21000}, GasPrice: []uint64{1}},
+			txs:  romeTestTxs,
+			err:  "gasUsed length mismatch: have 1, want 2",
+		},
+		{
+			name: "extra gas entries without pool",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{21000, 21000, 21000}, GasPrice: []uint64{1, 1, 1}},
+			txs:  romeTestTxs,
+			err:  "gasUsed length mismatch: have 3, want 2",
+		},
+		{
+			name: "missing gas entries with pool",
+			attr: engine.RomePayloadAttributes{GasUsed: []uint64{21000}, GasPrice: []uint64{1}},
+			txs:  romeTestTxs,
+			err:  "gasUsed too short: have 1, want at least 2",
+		},
+		{
+			name: "zero gas price",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{21000, 21000}, GasPrice: []uint64{1, 0}},
+			txs:  romeTestTxs,
+			err:  "zero gas price for transaction 1 using 21000 gas",
+		},
+		{
+			name: "zero gas price without gas",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{0, 21000}, GasPrice: []uint64{0, 1}},
+			txs:  romeTestTxs,
+		},
+		{
+			name: "zero gas price deposit",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{21000}, GasPrice: []uint64{0}},
+			txs:  romeTestDeposit,
+		},
+		{
+			name: "footprint mismatch",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{1, 1}, GasPrice: []uint64{1, 1}, TxFootprints: []string{"0x0"}},
+			txs:  romeTestTxs,
+			err:  "txFootprints length mismatch: have 1, want 2",
+		},
+		{
+			name: "footprint without prefix",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{1, 1}, GasPrice: []uint64{1, 1}, TxFootprints: []string{"0x0", "1234"}},
+			txs:  romeTestTxs,
+			err:  `invalid footprint "1234" for transaction 1: missing 0x prefix`,
+		},
+		{
+			name: "empty footprint",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{1, 1}, GasPrice: []uint64{1, 1}, TxFootprints: []string{"0x", "0x0"}},
+			txs:  romeTestTxs,
+			err:  `invalid footprint "0x" for transaction 0: empty hex string`,
+		},
+		{
+			name: "footprint not hex",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{1, 1}, GasPrice: []uint64{1, 1}, TxFootprints: []string{"0x0", "0xzz"}},
+			txs:  romeTestTxs,
+			err:  `invalid footprint "0xzz" for transaction 1: invalid hex character 'z'`,
+		},
+		{
+			name: "footprint too long",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{1, 1}, GasPrice: []uint64{1, 1}, TxFootprints: []string{"0x0", romeTestHash + "00"}},
+			txs:  romeTestTxs,
+			err:  "longer than 32 bytes",
+		},
+		{
+			name: "slots without timestamps",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{1, 1}, GasPrice: []uint64{1, 1}, SolanaBlockNumbers: []uint64{1, 2}},
+			txs:  romeTestTxs,
+			err:  "solanaTimestamps length mismatch: have 0, want 2",
+		},
+		{
+			name: "slot vector mismatch",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{1, 1}, GasPrice: []uint64{1, 1}, SolanaBlockNumbers: []uint64{1}, SolanaTimestamps: []int64{1, 2}},
+			txs:  romeTestTxs,
+			err:  "solanaBlockNumbers length mismatch: have 1, want 2",
+		},
+		{
+			name: "negative timestamp",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{1, 1}, GasPrice: []uint64{1, 1}, SolanaBlockNumbers: []uint64{1, 2}, SolanaTimestamps: []int64{1, -2}},
+			txs:  romeTestTxs,
+			err:  "negative solana timestamp -2 for transaction 1",
+		},
+		{
+			name: "decreasing slots",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{1, 1}, GasPrice: []uint64{1, 1}, SolanaBlockNumbers: []uint64{5, 4}, SolanaTimestamps: []int64{1, 2}},
+			txs:  romeTestTxs,
+			err:  "solana slot 4 of transaction 1 precedes slot 5 of transaction 0",
+		},
+		{
+			name: "decreasing timestamps",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{1, 1}, GasPrice: []uint64{1, 1}, SolanaBlockNumbers: []uint64{4, 5}, SolanaTimestamps: []int64{2, 1}},
+			txs:  romeTestTxs,
+			err:  "solana timestamp 1 of transaction 1 precedes timestamp 2 of transaction 0",
+		},
+		{
+			name: "slot before head",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{1, 1}, GasPrice: []uint64{1, 1}, SolanaBlockNumbers: []uint64{99, 100}, SolanaTimestamps: []int64{1700000000, 1700000000}},
+			txs:  romeTestTxs,
+			head: head,
+			err:  "solana slot 99 of transaction 0 precedes head slot 100",
+		},
+		{
+			name: "timestamp before head",
+			attr: engine.RomePayloadAttributes{NoTxPool: true, GasUsed: []uint64{1, 1}, GasPrice: []uint64{1, 1}, SolanaBlockNumbers: []uint64{100, 100}, SolanaTimestamps: []int64{1699999999, 1700000000}},
+			txs:  romeTestTxs,
+			head: head,
+			err:  "solana timestamp 1699999999 of transaction 0 precedes head timestamp 1700000000",
+		},
+	}
+	for _, tt := range tests {
+		checkRomeValidation(t, tt.name, verifyRomeAttributes(&tt.attr, tt.txs, tt.head), tt.err)
+	}
+}
+
+func TestVerifyRomePayload(t *testing.T) {
+	var (
+		parentSlot = uint64(100)
+		parent     = vm.TxContext{SolanaBlockNumber: &parentSlot}
+		slot       = func(n uint64) *hexutil.Uint64 { return (*hexutil.Uint64)(&n) }
+	)
+	tests := []struct {
+		name    string
+		payload engine.RomeExecutableData
+		txs     types.Transactions
+		parent  vm.TxContext
+		err     string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			payload: engine.RomeExecutableData{
+				RomeGasUsed:       []uint64{21000, 30000},
+				RomeGasPrice:      []uint64{1, 2},
+				TxFootprints:      []string{romeTestHash, "0x0"},
+				SolanaBlockNumber: slot(100),
+			},
+			txs:    romeTestTxs,
+			parent: parent,
+		},
+		{
+			name:    "missing gas used",
+			payload: engine.RomeExecutableData{RomeGasUsed: []uint64{21000}, RomeGasPrice: []uint64{1, 1}},
+			txs:     romeTestTxs,
+			err:     "romeGasUsed length mismatch: have 1, want 2",
+		},
+		{
+			name:    "extra gas price",
+			payload: engine.RomeExecutableData{RomeGasUsed: []uint64{21000, 21000}, RomeGasPrice: []uint64{1, 1, 1}},
+			txs:     romeTestTxs,
+			err:     "romeGasPrice length mismatch: have 3, want 2",
+		},
+		{
+			name:    "vectors without transactions",
+			payload: engine.RomeExecutableData{RomeGasUsed: []uint64{21000}, RomeGasPrice: []uint64{1}},
+			err:     "romeGasUsed length mismatch: have 1, want 0",
+		},
+		{
+			name:    "zero gas price",
+			payload: engine.RomeExecutableData{RomeGasUsed: []uint64{21000, 21000}, RomeGasPrice: []uint64{0, 1}},
+			txs:     romeTestTxs,
+			err:     "zero gas price for transaction 0 using 21000 gas",
+		},
+		{
+			name:    "zero gas price deposit",
+			payload: engine.RomeExecutableData{RomeGasUsed: []uint64{21000}, RomeGasPrice: []uint64{0}},
+			txs:     romeTestDeposit,
+		},
+		{
+			name:    "bad footprint",
+			payload: engine.RomeExecutableData{RomeGasUsed: []uint64{1, 1}, RomeGasPrice: []uint64{1, 1}, TxFootprints: []string{"0x0", "0xg"}},
+			txs:     romeTestTxs,
+			err:     `invalid footprint "0xg" for transaction 1: invalid hex character 'g'`,
+		},
+		{
+			name:    "slot before parent",
+			payload: engine.RomeExecutableData{SolanaBlockNumber: slot(99)},
+			parent:  parent,
+			err:     "solana block number 99 precedes parent slot 100",
+		},
+	}
+	for _, tt := range tests {
+		checkRomeValidation(t, tt.name, verifyRomePayload(&tt.payload, tt.txs, tt.parent), tt.err)
+	}
+}
+
+func TestVerifySolanaBlockhashes(t *testing.T) {
+	var (
+		first, last = uint64(1000), uint64(1010)
+		hash        = common.HexToHash("0x5ea1")
+	)
+	tests := []struct {
+		name        string
+		blockhashes []engine.SolanaBlockhash
+		first, last *uint64
+		err         string
+	}{
+		{name: "none"},
+		{
+			name:        "in reach",
+			blockhashes: []engine.SolanaBlockhash{{Slot: 744, Hash: hash}, {Slot: 1009, Hash: hash}},
+			first:       &first,
+			last:        &last,
+		},
+		{
+			name:        "without slots",
+			blockhashes: []engine.SolanaBlockhash{{Slot: 1009, Hash: hash}},
+			err:         "solana blockhashes without solana slots",
+		},
+		{
+			name:        "empty",
+			blockhashes: []engine.SolanaBlockhash{{Slot: 1009}},
+			first:       &first,
+			last:        &last,
+			err:         "empty blockhash for solana slot 1009",
+		},
+		{
+			name:        "too old",
+			blockhashes: []engine.SolanaBlockhash{{Slot: 743, Hash: hash}},
+			first:       &first,
+			last:        &last,
+			err:         "blockhash for solana slot 743 out of reach [744, 1010)",
+		},
+		{
+			name:        "not yet produced",
+			blockhashes: []engine.SolanaBlockhash{{Slot: 1010, Hash: hash}},
+			first:       &first,
+			last:        &last,
+			err:         "blockhash for solana slot 1010 out of reach [744, 1010)",
+		},
+		{
+			name:        "conflicting",
+			blockhashes: []engine.SolanaBlockhash{{Slot: 1009, Hash: hash}, {Slot: 1009, Hash: common.HexToHash("0x01")}},
+			first:       &first,
+			last:        &last,
+			err:         "conflicting blockhashes for solana slot 1009",
+		},
+	}
+	for _, tt := range tests {
+		_, err := verifySolanaBlockhashes(tt.blockhashes, tt.first, tt.last)
+		checkRomeValidation(t, tt.name, err, tt.err)
+	}
+}
+
+func checkRomeValidation(t *testing.T, name string, err error, want string) {
+	t.Helper()
+	switch {
+	case want == "" && err != nil:
+		t.Errorf("%s: unexpected error: %v", name, err)
+	case want != "" && err == nil:
+		t.Errorf("%s: expected error %q, got none", name, want)
+	case want != "" && !strings.Contains(err.Error(), want):
+		t.Errorf("%s: error mismatch: have %q, want %q", name, err, want)
+	}
+}
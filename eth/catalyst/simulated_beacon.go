@@ -17,18 +17,31 @@
 package catalyst
 
 import (
+	"context"
 	"crypto/rand"
 	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -79,6 +92,8 @@ type SimulatedBeacon struct {
 	engineAPI          *ConsensusAPI
 	curForkchoiceState engine.ForkchoiceStateV1
 	lastBlockTime      uint64
+
+	rome atomic.Bool // Whether to fill the Rome payload attributes from local execution
 }
 
 func NewSimulatedBeacon(period uint64, eth *eth.Ethereum) (*SimulatedBeacon, error) {
@@ -92,7 +107,7 @@ func NewSimulatedBeacon(period uint64, eth *eth.Ethereum) (*SimulatedBeacon, err
 
 	// if genesis block, send forkchoiceUpdated to trigger transition to PoS
 	if block.Number.Sign() == 0 {
-		if _, err := engineAPI.ForkchoiceUpdatedV2(current, nil); err != nil {
+		if _, err := engineAPI.ForkchoiceUpdatedV2(context.Background(), current, nil); err != nil {
 			return nil, err
 		}
 	}
@@ -113,6 +128,13 @@ func (c *SimulatedBeacon) setFeeRecipient(feeRecipient common.Address) {
 	c.feeRecipientLock.Unlock()
 }
 
+// SetRomeMode toggles filling the Rome gas and footprint vectors of the sealed
+// blocks from local execution of the pending transactions, standing in for the
+// Rome indexer.
+func (c *SimulatedBeacon) SetRomeMode(enabled bool) {
+	c.rome.Store(enabled)
+}
+
 // Start invokes the SimulatedBeacon life-cycle function in a goroutine.
 func (c *SimulatedBeacon) Start() error {
 	if c.period == 0 {
@@ -148,12 +170,19 @@ func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal) error {
 
 	var random [32]byte
 	rand.Read(random[:])
-	fcResponse, err := c.engineAPI.ForkchoiceUpdatedV2(c.curForkchoiceState, &engine.RomePayloadAttributes{
+	attrs := &engine.RomePayloadAttributes{
 		Timestamp:             tstamp,
 		SuggestedFeeRecipient: feeRecipient,
 		Withdrawals:           withdrawals,
 		Random:                random,
-	})
+	}
+	rome := c.rome.Load()
+	if rome {
+		if err := c.fillRomeAttributes(attrs); err != nil {
+			return err
+		}
+	}
+	fcResponse, err := c.engineAPI.ForkchoiceUpdatedV2(context.Background(), c.curForkchoiceState, attrs)
 	if err != nil {
 		return err
 	}
@@ -169,6 +198,11 @@ func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal) error {
 		return err
 	}
 	payload := envelope.ExecutionPayload
+	if rome {
+		payload.RomeGasUsed = attrs.GasUsed
+		payload.RomeGasPrice = attrs.GasPrice
+		payload.TxFootprints = attrs.TxFootprints
+	}
 
 	var finalizedHash common.Hash
 	if payload.Number%devEpochLength == 0 {
@@ -182,18 +216,158 @@ func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal) error {
 	}
 
 	// Mark the payload as canon
-	if _, err = c.engineAPI.NewPayloadV2(*payload); err != nil {
+	if _, err = c.engineAPI.NewPayloadV2(context.Background(), *payload); err != nil {
 		return err
 	}
 	c.setCurrentState(payload.BlockHash, finalizedHash)
 	// Mark the block containing the payload as canonical
-	if _, err = c.engineAPI.ForkchoiceUpdatedV2(c.curForkchoiceState, nil); err != nil {
+	if _, err = c.engineAPI.ForkchoiceUpdatedV2(context.Background(), c.curForkchoiceState, nil); err != nil {
 		return err
 	}
 	c.lastBlockTime = payload.Timestamp
 	return nil
 }
 
+// fillRomeAttributes stands in for the Rome indexer: it executes the pending
+// transactions on top of the current head and forces them into the payload
+// along with the gas used, gas prices and state footprints of their execution.
+// The Solana slot and timestamp of each transaction are faked from the block.
+func (c *SimulatedBeacon) fillRomeAttributes(attrs *engine.RomePayloadAttributes) error {
+	if attrs.SuggestedFeeRecipient == (common.Address{}) {
+		// Rome only charges gas if there is a fee recipient
+		etherbase, err := c.eth.Etherbase()
+		if err != nil {
+			return fmt.Errorf("rome mode requires a fee recipient: %w", err)
+		}
+		attrs.SuggestedFeeRecipient = etherbase
+	}
+	var (
+		chain  = c.eth.BlockChain()
+		config = chain.Config()
+		parent = chain.CurrentBlock()
+	)
+	statedb, err := chain.StateAt(parent.Root)
+	if err != nil {
+		return err
+	}
+	// The header must match the one built by the miner, the footprints depend
+	// on the fees charged
+	number := new(big.Int).Add(parent.Number, common.Big1)
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     number,
+		GasLimit:   txpool.BlockGasLimit(config, parent, attrs.Timestamp, attrs.GasLimit),
+		Time:       attrs.Timestamp,
+		Coinbase:   attrs.SuggestedFeeRecipient,
+		Difficulty: common.Big0,
+		MixDigest:  attrs.Random,
+		BaseFee:    eip1559.RomeBaseFee(config, number),
+	}
+	if config.IsCancun(header.Number, header.Time) {
+		var excessBlobGas uint64
+		if config.IsCancun(parent.Number, parent.Time) {
+			excessBlobGas = eip4844.CalcExcessBlobGas(*parent.ExcessBlobGas, *parent.BlobGasUsed)
+		}
+		header.ExcessBlobGas = &excessBlobGas
+	}
+	version := config.FootprintVersion(header.Time)
+	hasher := state.NewFootprintHasher(version)
+	if hasher == nil {
+		return fmt.Errorf("unsupported footprint version %d", version)
+	}
+	misc.EnsureCreate2Deployer(config, header.Time, statedb)
+
+	var (
+		signer    = types.MakeSigner(config, header.Number, header.Time)
+		gp        = new(core.GasPool).AddGas(header.GasLimit)
+		vmenv     = vm.NewEVM(core.NewEVMBlockContext(header, chain, nil, config, statedb), vm.TxContext{}, statedb, config, vm.Config{})
+		slot      = header.Number.Uint64()
+		timestamp = int64(header.Time)
+	)
+	for _, tx := range c.pendingTransactions() {
+		msg, err := core.TransactionToMessage(tx, signer, header.BaseFee)
+		if err != nil {
+			continue
+		}
+		txContext := core.NewEVMTxContext(msg)
+		txContext.SolanaBlockNumber, txContext.SolanaTimestamp = &slot, &timestamp
+		statedb.SetTxContext(tx.Hash(), len(attrs.Transactions))
+
+		// Measure the gas consumed by the execution, then apply it for real
+		// with the gas vector to compute the footprint of the transaction
+		snap := statedb.Snapshot()
+		vmenv.Reset(txContext, statedb)
+		result, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(math.MaxUint64), 0, 0)
+		statedb.RevertToSnapshot(snap)
+		statedb.ResetTxFootprint()
+		if err != nil {
+			log.Debug("Skipping unexecutable transaction", "hash", tx.Hash(), "err", err)
+			continue
+		}
+		// Rome charges no intrinsic gas, stand in with the Ethereum base cost
+		gasUsed, gasPrice := params.TxGas+result.EVMGasUsed, msg.GasPrice.Uint64()
+		if msg.To == nil {
+			gasUsed = params.TxGasContractCreation + result.EVMGasUsed
+		}
+		if gp.Gas() < gasUsed {
+			break
+		}
+		var (
+			gas   = gp.Gas()
+			start = statedb.JournalLength()
+		)
+		snap = statedb.Snapshot()
+		vmenv.Reset(txContext, statedb)
+		if _, err := core.ApplyMessage(vmenv, msg, gp, gasUsed, gasPrice); err != nil {
+			log.Debug("Skipping unexecutable transaction", "hash", tx.Hash(), "err", err)
+			statedb.RevertToSnapshot(snap)
+			statedb.ResetTxFootprint()
+			gp.SetGas(gas)
+			continue
+		}
+		footprint, _ := statedb.CalculateTxFootPrint(start, hasher)
+		statedb.Finalise(true)
+
+		blob, err := tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		attrs.Transactions = append(attrs.Transactions, blob)
+		attrs.GasUsed = append(attrs.GasUsed, gasUsed)
+		attrs.GasPrice = append(attrs.GasPrice, gasPrice)
+		attrs.TxFootprints = append(attrs.TxFootprints, footprint.Hex())
+		attrs.SolanaBlockNumbers = append(attrs.SolanaBlockNumbers, slot)
+		attrs.SolanaTimestamps = append(attrs.SolanaTimestamps, timestamp)
+	}
+	attrs.NoTxPool = true
+	attrs.GasLimit = &header.GasLimit
+	return nil
+}
+
+// pendingTransactions returns the executable transactions of the pool, ordered
+// by the arrival of each sender's first transaction, then by nonce.
+func (c *SimulatedBeacon) pendingTransactions() types.Transactions {
+	var (
+		pending = c.eth.TxPool().Pending(false)
+		senders = make([]common.Address, 0, len(pending))
+	)
+	for addr := range pending {
+		senders = append(senders, addr)
+	}
+	sort.Slice(senders, func(i, j int) bool {
+		return pending[senders[i]][0].Time.Before(pending[senders[j]][0].Time)
+	})
+	var txs types.Transactions
+	for _, addr := range senders {
+		for _, ltx := range pending[addr] {
+			if tx := ltx.Resolve(); tx != nil && tx.Type() != types.BlobTxType {
+				txs = append(txs, tx)
+			}
+		}
+	}
+	return txs
+}
+
 // loopOnDemand runs the block production loop for "on-demand" configuration (period = 0)
 func (c *SimulatedBeacon) loopOnDemand() {
 	var (
@@ -139,3 +139,63 @@ func TestSimulatedBeaconSendWithdrawals(t *testing.T) {
 		}
 	}
 }
+
+// Tests that in Rome mode the simulated beacon fills the gas and footprint
+// vectors of the payload from local execution, and the sealed blocks pass the
+// footprint checks on import.
+func TestSimulatedBeaconRomeMode(t *testing.T) {
+	var (
+		testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+	)
+	genesis := core.DeveloperGenesisBlock(10_000_000, &testAddr)
+	node, ethService, mock := startSimulatedBeaconEthService(t, genesis)
+	defer node.Close()
+
+	mock.setFeeRecipient(common.Address{0xc0})
+	mock.SetRomeMode(true)
+
+	chainHeadCh := make(chan core.ChainHeadEvent, 10)
+	subscription := ethService.BlockChain().SubscribeChainHeadEvent(chainHeadCh)
+	defer subscription.Unsubscribe()
+
+	signer := types.LatestSigner(ethService.BlockChain().Config())
+	txs := make(map[common.Hash]struct{})
+	for i := 0; i < 5; i++ {
+		tx, err := types.SignNewTx(testKey, signer, &types.LegacyTx{Nonce: uint64(i), To: &common.Address{0xaa}, Value: big.NewInt(1000), Gas: params.TxGas, GasPrice: big.NewInt(params.InitialBaseFee)})
+		if err != nil {
+			t.Fatalf("error signing transaction, err=%v", err)
+		}
+		txs[tx.Hash()] = struct{}{}
+		if err := ethService.APIBackend.SendTx(context.Background(), tx); err != nil {
+			t.Fatal("SendTx failed", err)
+		}
+	}
+	timer := time.NewTimer(12 * time.Second)
+	for len(txs) > 0 {
+		select {
+		case evt := <-chainHeadCh:
+			block := evt.Block
+			receipts := ethService.BlockChain().GetReceiptsByHash(block.Hash())
+			romeData := ethService.BlockChain().GetRomeBlockData(block.Hash(), block.NumberU64())
+			for i, tx := range block.Transactions() {
+				delete(txs, tx.Hash())
+
+				if gasUsed, gasPrice := romeData.TxGas(i); gasUsed != params.TxGas || gasPrice != params.InitialBaseFee {
+					t.Errorf("tx %d: rome gas mismatch: have %d/%d, want %d/%d", i, gasUsed, gasPrice, params.TxGas, params.InitialBaseFee)
+				}
+				if receipts[i].GasUsed != params.TxGas {
+					t.Errorf("tx %d: receipt gas mismatch: have %d, want %d", i, receipts[i].GasUsed, params.TxGas)
+				}
+				if footprint := romeData.TxFootprint(i); common.HexToHash(footprint) == (common.Hash{}) {
+					t.Errorf("tx %d: missing footprint", i)
+				}
+				if mismatch := ethService.BlockChain().GetFootprintManager().Mismatch(tx.Hash()); mismatch != nil {
+					t.Errorf("tx %d: footprint mismatch: %v", i, mismatch)
+				}
+			}
+		case <-timer.C:
+			t.Fatal("timed out without including all txs")
+		}
+	}
+}
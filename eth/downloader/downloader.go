@@ -27,9 +27,11 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/rome"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
@@ -137,6 +139,9 @@ type Downloader struct {
 	SnapSyncer     *snap.Syncer // TODO(karalabe): make private! hack for now
 	stateSyncStart chan *stateSync
 
+	// Rome metadata sync
+	RomeSyncer *rome.Syncer // Fetches the Rome execution inputs of downloaded blocks
+
 	// Cancellation and termination
 	cancelPeer string         // Identifier of the peer currently being used as the master (cancel on drop)
 	cancelCh   chan struct{}  // Channel to cancel mid-flight syncs
@@ -210,6 +215,12 @@ type BlockChain interface {
 	// InsertReceiptChain inserts a batch of receipts into the local chain.
 	InsertReceiptChain(types.Blocks, []types.Receipts, uint64) (int, error)
 
+	// StageRomeInputs makes the Rome inputs of a block available to its import.
+	StageRomeInputs(common.Hash, *core.RomeInputs) error
+
+	// UnstageRomeInputs discards the Rome inputs staged for a block.
+	UnstageRomeInputs(common.Hash)
+
 	// Snapshots returns the blockchain snapshot tree to paused it during sync.
 	Snapshots() *snapshot.Tree
 
@@ -235,6 +246,7 @@ func New(stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, lightchai
 		quitCh:         make(chan struct{}),
 		SnapSyncer:     snap.NewSyncer(stateDb, chain.TrieDB().Scheme()),
 		stateSyncStart: make(chan *stateSync),
+		RomeSyncer:     rome.NewSyncer(stateDb, chain),
 		syncStartBlock: chain.CurrentSnapBlock().Number.Uint64(),
 		chainID:        chainID,
 	}
@@ -1505,10 +1517,17 @@ func (d *Downloader) importBlockResults(results []*fetchResult) error {
 	for i, result := range results {
 		blocks[i] = types.NewBlockWithHeader(result.Header).WithBody(result.Transactions, result.Uncles).WithWithdrawals(result.Withdrawals)
 	}
+	// Rome blocks only re-execute to the same state with the execution inputs
+	// of the sequencer, retrieve them from the network if not yet known.
+	if err := d.RomeSyncer.Sync(blocks, d.quitCh); err != nil {
+		return errCancelContentProcessing
+	}
 	// Downloaded blocks are always regarded as trusted after the
 	// transition. Because the downloaded chain is guided by the
 	// consensus-layer.
-	if index, err := d.blockchain.InsertChain(blocks); err != nil {
+	index, err := d.blockchain.InsertChain(blocks)
+	d.romeImported(blocks, index, err)
+	if err != nil {
 		if index < len(results) {
 			log.Debug("Downloaded item processing failed", "number", results[index].Header.Number, "hash", results[index].Header.Hash(), "err", err)
 
@@ -1724,13 +1743,34 @@ func (d *Downloader) commitSnapSyncData(results []*fetchResult, stateSync *state
 		blocks[i] = types.NewBlockWithHeader(result.Header).WithBody(result.Transactions, result.Uncles).WithWithdrawals(result.Withdrawals)
 		receipts[i] = correctReceipts(result.Receipts, result.Transactions, blocks[i].NumberU64(), d.chainID)
 	}
-	if index, err := d.blockchain.InsertReceiptChain(blocks, receipts, d.ancientLimit); err != nil {
+	// Snap synced blocks are not executed, but their Rome metadata is still
+	// needed to regenerate state or trace them later on.
+	if err := d.RomeSyncer.Sync(blocks, d.quitCh); err != nil {
+		return errCancelContentProcessing
+	}
+	index, err := d.blockchain.InsertReceiptChain(blocks, receipts, d.ancientLimit)
+	d.romeImported(blocks, index, err)
+	if err != nil {
 		log.Debug("Downloaded item processing failed", "number", results[index].Header.Number, "hash", results[index].Header.Hash(), "err", err)
 		return fmt.Errorf("%w: %v", errInvalidChain, err)
 	}
 	return nil
 }
 
+// romeImported releases the Rome metadata staged for a batch of blocks after
+// their import, dropping the peer that delivered the metadata of a block that
+// failed to import.
+func (d *Downloader) romeImported(blocks []*types.Block, index int, err error) {
+	id := d.RomeSyncer.Done(blocks, index, err)
+	if id == "" {
+		return
+	}
+	log.Warn("Dropping peer for bad Rome metadata", "peer", id, "number", blocks[index].Number(), "hash", blocks[index].Hash(), "err", err)
+	if d.dropPeer != nil {
+		d.dropPeer(id)
+	}
+}
+
 func (d *Downloader) commitPivotBlock(result *fetchResult) error {
 	block := types.NewBlockWithHeader(result.Header).WithBody(result.Transactions, result.Uncles).WithWithdrawals(result.Withdrawals)
 	log.Debug("Committing snap sync pivot as new head", "number", block.Number(), "hash", block.Hash())
@@ -1772,6 +1812,18 @@ func (d *Downloader) DeliverSnapPacket(peer *snap.Peer, packet snap.Packet) erro
 	}
 }
 
+// DeliverRomePacket is invoked from a peer's message handler when it transmits a
+// Rome metadata packet for the local node to consume.
+func (d *Downloader) DeliverRomePacket(peer *rome.Peer, packet rome.Packet) error {
+	switch packet := packet.(type) {
+	case *rome.RomeDataPacket:
+		return d.RomeSyncer.OnRomeData(peer, packet.ID, packet.Blocks)
+
+	default:
+		return fmt.Errorf("unexpected rome packet type: %T", packet)
+	}
+}
+
 // readHeaderRange returns a list of headers, using the given last header as the base,
 // and going backwards towards genesis. This method assumes that the caller already has
 // placed a reasonable cap on count.
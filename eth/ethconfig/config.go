@@ -27,9 +27,12 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/footprint"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/forwarder"
+	"github.com/ethereum/go-ethereum/eth/gasometer"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/miner"
@@ -67,7 +70,14 @@ var Defaults = Config{
 	RPCGasCap:          50000000,
 	RPCEVMTimeout:      5 * time.Second,
 	GPO:                FullNodeGPO,
+	Gasometer:          gasometer.DefaultConfig,
+	RollupForwarding:   forwarder.DefaultConfig,
 	RPCTxFeeCap:        1, // 1 ether
+
+	StateHistoryLookback:  params.FullImmutabilityThreshold,
+	StateHistoryReadLimit: 1000000,
+
+	RollupSequencerTxConditionalCostRateLimit: 5000,
 }
 
 //go:generate go run github.com/fjl/gencodec -type Config -formats toml -out gen_config.go
@@ -96,6 +106,20 @@ type Config struct {
 	TransactionHistory uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 	StateHistory       uint64 `toml:",omitempty"` // The maximum number of blocks from head whose state histories are reserved.
 
+	// StateHistoryArchive enables serving the historical states from the state
+	// histories in the path scheme, turning the node into an archive node for
+	// the blocks whose state histories are reserved, up to StateHistoryLookback
+	// blocks below the persisted state.
+	StateHistoryArchive bool `toml:",omitempty"`
+
+	// StateHistoryLookback is the number of state histories searched to serve
+	// a historical state, bounding the cost of each state lookup (0 = all).
+	StateHistoryLookback uint64 `toml:",omitempty"`
+
+	// StateHistoryReadLimit is the number of state histories an RPC request
+	// may search in total to serve a historical state (0 = unlimited).
+	StateHistoryReadLimit uint64 `toml:",omitempty"`
+
 	// State scheme represents the scheme used to store ethereum states and trie
 	// nodes on top. It can be 'hash', 'path', or none which means use the scheme
 	// consistent with persistent state.
@@ -139,6 +163,9 @@ type Config struct {
 	// Gas Price Oracle options
 	GPO gasprice.Config
 
+	// Rome gasometer client options
+	Gasometer gasometer.Config
+
 	// Enables tracking of SHA3 preimages in the VM
 	EnablePreimageRecording bool
 
@@ -171,11 +198,21 @@ type Config struct {
 	ApplySuperchainUpgrades bool `toml:",omitempty"`
 
 	RollupSequencerHTTP                     string
+	RollupForwarding                        forwarder.Config // Forwarding of transactions to RollupSequencerHTTP
 	RollupHistoricalRPC                     string
 	RollupHistoricalRPCTimeout              time.Duration
 	RollupDisableTxPoolGossip               bool
 	RollupDisableTxPoolAdmission            bool
 	RollupHaltOnIncompatibleProtocolVersion string
+
+	// RollupSequencerTxConditionalEnabled enables eth_sendRawTransactionConditional,
+	// whose requests are limited to the given number of conditional checks per second.
+	RollupSequencerTxConditionalEnabled       bool
+	RollupSequencerTxConditionalCostRateLimit int
+
+	// RomeFootprintPolicy is the enforcement policy applied to state footprint
+	// mismatches between Rome-EVM and the local execution.
+	RomeFootprintPolicy footprint.Policy
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain config.
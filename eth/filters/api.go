@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"sync"
 	"time"
@@ -28,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -38,11 +40,16 @@ var (
 	errFilterNotFound    = errors.New("filter not found")
 	errInvalidBlockRange = errors.New("invalid block range params")
 	errExceedMaxTopics   = errors.New("exceed max topics")
+	errInvalidSlotRange  = errors.New("invalid solana slot range params")
+	errSlotRangeFilter   = errors.New("solana slot range is only supported by eth_getLogs")
 )
 
 // The maximum number of topic criteria allowed, vm.LOG4 - vm.LOG0
 const maxTopics = 4
 
+// The maximum number of transactions a Solana slot range may resolve to
+const maxSolanaSlotTxs = 10000
+
 // filter is a helper struct that holds meta information over the filter type
 // and associated subscription in the event system.
 type filter struct {
@@ -341,6 +348,20 @@ func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*type
 	if len(crit.Topics) > maxTopics {
 		return nil, errExceedMaxTopics
 	}
+	// Resolve the Solana slot range into the transactions executed in it
+	var (
+		slotTxs             map[common.Hash]struct{}
+		slotFirst, slotLast int64
+		err                 error
+	)
+	if crit.FromSolanaSlot != nil || crit.ToSolanaSlot != nil {
+		if slotTxs, slotFirst, slotLast, err = api.solanaSlotTxs(crit.FromSolanaSlot, crit.ToSolanaSlot); err != nil {
+			return nil, err
+		}
+		if len(slotTxs) == 0 {
+			return []*types.Log{}, nil
+		}
+	}
 	var filter *Filter
 	if crit.BlockHash != nil {
 		// Block filter requested, construct a single-shot filter
@@ -358,6 +379,18 @@ func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*type
 		if begin > 0 && end > 0 && begin > end {
 			return nil, errInvalidBlockRange
 		}
+		// Narrow the range down to the blocks containing the Solana slots
+		if slotTxs != nil {
+			if crit.FromBlock == nil || (begin >= 0 && begin < slotFirst) {
+				begin = slotFirst
+			}
+			if end == rpc.LatestBlockNumber.Int64() || end == rpc.PendingBlockNumber.Int64() || (end >= 0 && end > slotLast) {
+				end = slotLast
+			}
+			if begin >= 0 && end >= 0 && begin > end {
+				return []*types.Log{}, nil
+			}
+		}
 		// Construct the range filter
 		filter = api.sys.NewRangeFilter(begin, end, crit.Addresses, crit.Topics)
 	}
@@ -366,9 +399,57 @@ func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*type
 	if err != nil {
 		return nil, err
 	}
+	if slotTxs != nil {
+		logs = filterSolanaSlotLogs(logs, slotTxs)
+	}
 	return returnLogs(logs), err
 }
 
+// solanaSlotTxs returns the canonical transactions executed in the given Solana
+// slot range, along with the first and last block containing any of them.
+func (api *FilterAPI) solanaSlotTxs(from, to *uint64) (map[common.Hash]struct{}, int64, int64, error) {
+	first, last := uint64(0), uint64(math.MaxUint64)
+	if from != nil {
+		first = *from
+	}
+	if to != nil {
+		last = *to
+	}
+	if first > last {
+		return nil, 0, 0, errInvalidSlotRange
+	}
+	var (
+		txs                   = make(map[common.Hash]struct{})
+		firstBlock, lastBlock = uint64(math.MaxUint64), uint64(0)
+		exceeded              bool
+	)
+	rawdb.IterateSolanaSlotIndex(api.sys.backend.ChainDb(), first, last, func(entry *rawdb.SolanaSlotEntry) bool {
+		if len(txs) == maxSolanaSlotTxs {
+			exceeded = true
+			return false
+		}
+		txs[entry.TxHash] = struct{}{}
+		firstBlock = min(firstBlock, entry.BlockNumber)
+		lastBlock = max(lastBlock, entry.BlockNumber)
+		return true
+	})
+	if exceeded {
+		return nil, 0, 0, fmt.Errorf("solana slot range exceeds %d transactions", maxSolanaSlotTxs)
+	}
+	return txs, int64(firstBlock), int64(lastBlock), nil
+}
+
+// filterSolanaSlotLogs returns the logs emitted by the given transactions.
+func filterSolanaSlotLogs(logs []*types.Log, txs map[common.Hash]struct{}) []*types.Log {
+	var ret []*types.Log
+	for _, log := range logs {
+		if _, ok := txs[log.TxHash]; ok {
+			ret = append(ret, log)
+		}
+	}
+	return ret
+}
+
 // UninstallFilter removes the filter with the given filter id.
 func (api *FilterAPI) UninstallFilter(id rpc.ID) bool {
 	api.filtersMu.Lock()
@@ -497,6 +578,9 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 		ToBlock   *rpc.BlockNumber `json:"toBlock"`
 		Addresses interface{}      `json:"address"`
 		Topics    []interface{}    `json:"topics"`
+
+		FromSolanaSlot *hexutil.Uint64 `json:"fromSolanaSlot"`
+		ToSolanaSlot   *hexutil.Uint64 `json:"toSolanaSlot"`
 	}
 
 	var raw input
@@ -520,6 +604,13 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	if raw.FromSolanaSlot != nil {
+		args.FromSolanaSlot = (*uint64)(raw.FromSolanaSlot)
+	}
+	if raw.ToSolanaSlot != nil {
+		args.ToSolanaSlot = (*uint64)(raw.ToSolanaSlot)
+	}
+
 	args.Addresses = []common.Address{}
 
 	if raw.Addresses != nil {
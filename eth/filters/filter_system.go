@@ -302,6 +302,11 @@ func (es *EventSystem) SubscribeLogs(crit ethereum.FilterQuery, logs chan []*typ
 	if len(crit.Topics) > maxTopics {
 		return nil, errExceedMaxTopics
 	}
+	// Subscriptions are not matched against Solana slots, only eth_getLogs
+	// resolves them through the slot index
+	if crit.FromSolanaSlot != nil || crit.ToSolanaSlot != nil {
+		return nil, errSlotRangeFilter
+	}
 	var from, to rpc.BlockNumber
 	if crit.FromBlock == nil {
 		from = rpc.LatestBlockNumber
@@ -40,6 +40,7 @@ import (
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
 )
 
 type testBackend struct {
@@ -423,6 +424,8 @@ func TestInvalidLogFilterCreation(t *testing.T) {
 		1: {FromBlock: big.NewInt(rpc.PendingBlockNumber.Int64()), ToBlock: big.NewInt(100)},
 		2: {FromBlock: big.NewInt(rpc.LatestBlockNumber.Int64()), ToBlock: big.NewInt(100)},
 		3: {Topics: [][]common.Hash{{}, {}, {}, {}, {}}},
+		4: {FromSolanaSlot: new(uint64)},
+		5: {ToSolanaSlot: new(uint64)},
 	}
 
 	for i, test := range testCases {
@@ -473,6 +476,78 @@ func TestInvalidGetRangeLogsRequest(t *testing.T) {
 	}
 }
 
+// TestGetLogsSolanaSlotRange tests getLogs restricted to a Solana slot range.
+func TestGetLogsSolanaSlotRange(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		_, sys = newTestFilterSystem(t, db, Config{})
+		api    = NewFilterAPI(sys, false)
+		addr   = common.HexToAddress("0x1111")
+		parent = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0)})
+		hashes []common.Hash
+	)
+	rawdb.WriteBlock(db, parent)
+	rawdb.WriteCanonicalHash(db, parent.Hash(), 0)
+
+	// Create a chain of three blocks, each holding a transaction executed in a
+	// distinct Solana slot and emitting a single log.
+	for i := uint64(1); i <= 3; i++ {
+		tx := types.NewTx(&types.LegacyTx{Nonce: i, GasPrice: big.NewInt(1)})
+		receipt := &types.Receipt{
+			Status: types.ReceiptStatusSuccessful,
+			Logs:   []*types.Log{{Address: addr, Topics: []common.Hash{common.BigToHash(new(big.Int).SetUint64(i))}}},
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+
+		header := &types.Header{Number: new(big.Int).SetUint64(i), ParentHash: parent.Hash()}
+		block := types.NewBlock(header, []*types.Transaction{tx}, nil, []*types.Receipt{receipt}, trie.NewStackTrie(nil))
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteReceipts(db, block.Hash(), i, types.Receipts{receipt})
+		rawdb.WriteCanonicalHash(db, block.Hash(), i)
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteSolanaTxMetadata(db, tx.Hash(), i*10, int64(i))
+		rawdb.WriteSolanaSlotIndex(db, &rawdb.SolanaSlotEntry{Slot: i * 10, TxHash: tx.Hash(), BlockNumber: i, BlockHash: block.Hash()})
+
+		parent = block
+		hashes = append(hashes, tx.Hash())
+	}
+	// Index a transaction of a non-canonical block, it must be ignored
+	rawdb.WriteSolanaSlotIndex(db, &rawdb.SolanaSlotEntry{Slot: 20, TxHash: common.Hash{0xff}, BlockNumber: 2, BlockHash: common.Hash{0xee}})
+
+	slot := func(n uint64) *uint64 { return &n }
+	tests := []struct {
+		crit FilterCriteria
+		want []common.Hash
+	}{
+		{FilterCriteria{FromSolanaSlot: slot(0), ToSolanaSlot: slot(100)}, hashes},
+		{FilterCriteria{FromSolanaSlot: slot(20), ToSolanaSlot: slot(20)}, hashes[1:2]},
+		{FilterCriteria{FromSolanaSlot: slot(15)}, hashes[1:]},
+		{FilterCriteria{ToSolanaSlot: slot(25)}, hashes[:2]},
+		{FilterCriteria{FromSolanaSlot: slot(10), ToSolanaSlot: slot(30), FromBlock: big.NewInt(2)}, hashes[1:]},
+		{FilterCriteria{FromSolanaSlot: slot(10), ToSolanaSlot: slot(30), ToBlock: big.NewInt(1)}, hashes[:1]},
+		{FilterCriteria{FromSolanaSlot: slot(21), ToSolanaSlot: slot(29)}, nil},
+		{FilterCriteria{FromSolanaSlot: slot(10), ToSolanaSlot: slot(10), FromBlock: big.NewInt(2)}, nil},
+	}
+	for i, test := range tests {
+		logs, err := api.GetLogs(context.Background(), test.crit)
+		if err != nil {
+			t.Fatalf("test %d: failed to get logs: %v", i, err)
+		}
+		var have []common.Hash
+		for _, log := range logs {
+			have = append(have, log.TxHash)
+		}
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("test %d: logs mismatch: have %v, want %v", i, have, test.want)
+		}
+	}
+	if _, err := api.GetLogs(context.Background(), FilterCriteria{FromSolanaSlot: slot(2), ToSolanaSlot: slot(1)}); err != errInvalidSlotRange {
+		t.Errorf("Expected Logs for invalid slot range return error, but got: %v", err)
+	}
+}
+
 // TestLogFilter tests whether log filters match the correct logs that are posted to the event feed.
 func TestLogFilter(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,37 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package forwarder
+
+import "github.com/ethereum/go-ethereum/rpc"
+
+// API exposes the introspection of the transaction forwarding.
+type API struct {
+	forwarder *Forwarder
+}
+
+// ForwardingStatus returns the forwarding activity towards the sequencer.
+func (api *API) ForwardingStatus() *Status {
+	return api.forwarder.Status()
+}
+
+// GetAPIs returns the RPC APIs of the transaction forwarding.
+func GetAPIs(forwarder *Forwarder) []rpc.API {
+	return []rpc.API{{
+		Namespace: "txpool",
+		Service:   &API{forwarder},
+	}}
+}
@@ -0,0 +1,401 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package forwarder implements the forwarding of the transactions submitted to
+// a replica node to the sequencer.
+package forwarder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrQueueFull is returned if the sequencer could not be reached and the retry
+// queue has no room for the transaction.
+var ErrQueueFull = errors.New("sequencer unreachable and forwarding queue full")
+
+// Config are the configuration parameters of the transaction forwarding.
+type Config struct {
+	Timeout       time.Duration // Timeout of a single forwarding request
+	Retries       int           // Number of retries of a transaction the sequencer could not be reached for
+	RetryInterval time.Duration // Delay between the retries of a queued transaction
+	QueueSize     int           // Maximum number of transactions queued for retry
+}
+
+// DefaultConfig contains the default transaction forwarding settings.
+var DefaultConfig = Config{
+	Timeout:       5 * time.Second,
+	Retries:       5,
+	RetryInterval: 2 * time.Second,
+	QueueSize:     1024,
+}
+
+// sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *Config) sanitize() Config {
+	conf := *config
+	if conf.Timeout <= 0 {
+		log.Warn("Sanitizing invalid forwarding timeout", "provided", conf.Timeout, "updated", DefaultConfig.Timeout)
+		conf.Timeout = DefaultConfig.Timeout
+	}
+	if conf.Retries < 0 {
+		log.Warn("Sanitizing invalid forwarding retries", "provided", conf.Retries, "updated", DefaultConfig.Retries)
+		conf.Retries = DefaultConfig.Retries
+	}
+	if conf.RetryInterval <= 0 {
+		log.Warn("Sanitizing invalid forwarding retry interval", "provided", conf.RetryInterval, "updated", DefaultConfig.RetryInterval)
+		conf.RetryInterval = DefaultConfig.RetryInterval
+	}
+	if conf.QueueSize < 0 {
+		log.Warn("Sanitizing invalid forwarding queue size", "provided", conf.QueueSize, "updated", DefaultConfig.QueueSize)
+		conf.QueueSize = DefaultConfig.QueueSize
+	}
+	return conf
+}
+
+// Error is an error response of the sequencer to a forwarded transaction. It
+// retains the JSON-RPC error code and data of the sequencer response.
+type Error struct {
+	Upstream string // Sequencer the transaction was forwarded to
+	err      rpc.Error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("sequencer %s: %v", e.Upstream, e.err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// ErrorCode returns the JSON-RPC error code of the sequencer response.
+func (e *Error) ErrorCode() int {
+	return e.err.ErrorCode()
+}
+
+// ErrorData returns the JSON-RPC error data of the sequencer response.
+func (e *Error) ErrorData() interface{} {
+	var dataErr rpc.DataError
+	if errors.As(e.err, &dataErr) {
+		return dataErr.ErrorData()
+	}
+	return nil
+}
+
+// Status is a snapshot of the forwarding activity, served by the
+// txpool_forwardingStatus endpoint.
+type Status struct {
+	Upstream      string         `json:"upstream"`
+	Forwarded     hexutil.Uint64 `json:"forwarded"` // Transactions accepted by the sequencer
+	Rejected      hexutil.Uint64 `json:"rejected"`  // Transactions rejected by the sequencer
+	Failed        hexutil.Uint64 `json:"failed"`    // Requests failing to reach the sequencer
+	Dropped       hexutil.Uint64 `json:"dropped"`   // Transactions given up on without reaching the sequencer
+	Queued        hexutil.Uint64 `json:"queued"`    // Transactions waiting to be retried
+	LastSuccess   *time.Time     `json:"lastSuccess,omitempty"`
+	LastError     string         `json:"lastError,omitempty"`
+	LastErrorTime *time.Time     `json:"lastErrorTime,omitempty"`
+}
+
+// queuedTx is a transaction waiting to be retried.
+type queuedTx struct {
+	tx       *types.Transaction
+	attempts int       // Number of failed forwarding attempts so far
+	next     time.Time // Time of the next attempt
+}
+
+// Forwarder forwards transactions to the sequencer over a long-lived
+// connection. Transactions the sequencer could not be reached for are queued
+// and retried in the background, while the sequencer rejecting a transaction
+// is reported to the submitter as is.
+type Forwarder struct {
+	config   Config
+	upstream string // Sequencer endpoint, stripped of credentials
+	client   *rpc.Client
+
+	lock  sync.Mutex
+	queue []*queuedTx
+
+	forwarded atomic.Uint64
+	rejected  atomic.Uint64
+	failed    atomic.Uint64
+	dropped   atomic.Uint64
+
+	statusLock    sync.Mutex
+	lastSuccess   time.Time
+	lastError     string
+	lastErrorTime time.Time
+
+	latencyTimer  metrics.Timer
+	rejectedMeter metrics.Meter
+	failureMeter  metrics.Meter
+	retryMeter    metrics.Meter
+	droppedMeter  metrics.Meter
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+// New creates a forwarder sending transactions to the sequencer over the given
+// connection. The retries of queued transactions start along with the node.
+func New(endpoint string, client *rpc.Client, config Config) *Forwarder {
+	upstream := redact(endpoint)
+	prefix := "rollup/forwarder/" + metricsName(upstream) + "/"
+	return &Forwarder{
+		config:        config.sanitize(),
+		upstream:      upstream,
+		client:        client,
+		latencyTimer:  metrics.NewRegisteredTimer(prefix+"latency", nil),
+		rejectedMeter: metrics.NewRegisteredMeter(prefix+"rejected", nil),
+		failureMeter:  metrics.NewRegisteredMeter(prefix+"failures", nil),
+		retryMeter:    metrics.NewRegisteredMeter(prefix+"retries", nil),
+		droppedMeter:  metrics.NewRegisteredMeter(prefix+"dropped", nil),
+		closeCh:       make(chan struct{}),
+	}
+}
+
+// Start implements node.Lifecycle, starting the retries of queued transactions.
+func (f *Forwarder) Start() error {
+	f.wg.Add(1)
+	go f.loop()
+	log.Info("Forwarding transactions to sequencer", "upstream", f.upstream)
+	return nil
+}
+
+// Stop implements node.Lifecycle, dropping the transactions still queued and
+// closing the sequencer connection.
+func (f *Forwarder) Stop() error {
+	close(f.closeCh)
+	f.wg.Wait()
+
+	f.lock.Lock()
+	if len(f.queue) > 0 {
+		log.Warn("Dropping transactions queued for forwarding", "count", len(f.queue))
+		f.drop(len(f.queue))
+		f.queue = nil
+	}
+	f.lock.Unlock()
+
+	f.client.Close()
+	return nil
+}
+
+// Forward sends the transaction to the sequencer. If the sequencer cannot be
+// reached, the transaction is queued for retrying and no error is returned,
+// unless the queue is full.
+func (f *Forwarder) Forward(ctx context.Context, tx *types.Transaction) error {
+	err := f.send(ctx, tx)
+	if err == nil || !unreachable(err) {
+		return err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if len(f.queue) >= f.config.QueueSize {
+		f.drop(1)
+		return fmt.Errorf("%w: %v", ErrQueueFull, err)
+	}
+	f.queue = append(f.queue, &queuedTx{tx: tx, attempts: 1, next: time.Now().Add(f.config.RetryInterval)})
+	log.Warn("Sequencer unreachable, queued transaction for forwarding", "hash", tx.Hash(), "upstream", f.upstream, "err", err)
+	return nil
+}
+
+// Status returns a snapshot of the forwarding activity.
+func (f *Forwarder) Status() *Status {
+	f.lock.Lock()
+	queued := len(f.queue)
+	f.lock.Unlock()
+
+	status := &Status{
+		Upstream:  f.upstream,
+		Forwarded: hexutil.Uint64(f.forwarded.Load()),
+		Rejected:  hexutil.Uint64(f.rejected.Load()),
+		Failed:    hexutil.Uint64(f.failed.Load()),
+		Dropped:   hexutil.Uint64(f.dropped.Load()),
+		Queued:    hexutil.Uint64(queued),
+	}
+	f.statusLock.Lock()
+	defer f.statusLock.Unlock()
+
+	if !f.lastSuccess.IsZero() {
+		t := f.lastSuccess
+		status.LastSuccess = &t
+	}
+	if f.lastError != "" {
+		t := f.lastErrorTime
+		status.LastError, status.LastErrorTime = f.lastError, &t
+	}
+	return status
+}
+
+// send performs a single forwarding request within the configured timeout,
+// forwarding conditional transactions along with their conditional.
+func (f *Forwarder) send(ctx context.Context, tx *types.Transaction) error {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, f.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	var hash common.Hash
+	switch cond := tx.Conditional(); {
+	case tx.Private():
+		err = f.client.CallContext(ctx, &hash, "eth_sendPrivateRawTransaction", hexutil.Encode(data))
+	case cond != nil:
+		err = f.client.CallContext(ctx, &hash, "eth_sendRawTransactionConditional", hexutil.Encode(data), cond)
+	default:
+		err = f.client.CallContext(ctx, &hash, "eth_sendRawTransaction", hexutil.Encode(data))
+	}
+	f.latencyTimer.UpdateSince(start)
+
+	f.statusLock.Lock()
+	defer f.statusLock.Unlock()
+
+	switch {
+	case err == nil:
+		f.forwarded.Add(1)
+		f.lastSuccess = time.Now()
+		return nil
+
+	case unreachable(err):
+		f.failed.Add(1)
+		f.failureMeter.Mark(1)
+
+	default:
+		f.rejected.Add(1)
+		f.rejectedMeter.Mark(1)
+
+		var rpcErr rpc.Error
+		errors.As(err, &rpcErr)
+		err = &Error{Upstream: f.upstream, err: rpcErr}
+	}
+	f.lastError, f.lastErrorTime = err.Error(), time.Now()
+	return err
+}
+
+// loop periodically retries the queued transactions.
+func (f *Forwarder) loop() {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(f.config.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			f.retry()
+		case <-f.closeCh:
+			return
+		}
+	}
+}
+
+// retry attempts to forward all queued transactions due for a retry. The ones
+// the sequencer still cannot be reached for are requeued until they run out
+// of retries.
+func (f *Forwarder) retry() {
+	f.lock.Lock()
+	var (
+		now     = time.Now()
+		due     []*queuedTx
+		waiting []*queuedTx
+	)
+	for _, queued := range f.queue {
+		if queued.next.After(now) {
+			waiting = append(waiting, queued)
+		} else {
+			due = append(due, queued)
+		}
+	}
+	f.queue = waiting
+	f.lock.Unlock()
+
+	for _, queued := range due {
+		f.retryMeter.Mark(1)
+
+		err := f.send(context.Background(), queued.tx)
+		switch {
+		case err == nil:
+			log.Info("Forwarded queued transaction", "hash", queued.tx.Hash(), "attempts", queued.attempts+1)
+			continue
+		case !unreachable(err):
+			log.Warn("Sequencer rejected queued transaction", "hash", queued.tx.Hash(), "err", err)
+			continue
+		}
+		queued.attempts++
+		if queued.attempts > f.config.Retries {
+			log.Warn("Dropping transaction after failing to forward", "hash", queued.tx.Hash(), "attempts", queued.attempts, "err", err)
+			f.lock.Lock()
+			f.drop(1)
+			f.lock.Unlock()
+			continue
+		}
+		queued.next = time.Now().Add(f.config.RetryInterval)
+		f.lock.Lock()
+		f.queue = append(f.queue, queued)
+		f.lock.Unlock()
+	}
+}
+
+// drop accounts for transactions given up on. The lock must be held.
+func (f *Forwarder) drop(count int) {
+	f.dropped.Add(uint64(count))
+	f.droppedMeter.Mark(int64(count))
+}
+
+// unreachable reports whether an error signals a failure to reach the sequencer,
+// as opposed to an error response of the sequencer itself.
+func unreachable(err error) bool {
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+// redact strips the credentials and query off an endpoint URL, so it can be
+// reported and logged.
+func redact(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return endpoint
+	}
+	return u.Scheme + "://" + u.Host + u.Path
+}
+
+// metricsName converts an upstream endpoint into a metric name component.
+func metricsName(upstream string) string {
+	if u, err := url.Parse(upstream); err == nil && u.Host != "" {
+		upstream = u.Host
+	}
+	return strings.Map(func(r rune) rune {
+		if ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, upstream)
+}
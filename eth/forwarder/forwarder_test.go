@@ -0,0 +1,249 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package forwarder
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rejectionError is the error the test sequencer rejects transactions with.
+type rejectionError struct{}
+
+func (rejectionError) Error() string          { return "nonce too low" }
+func (rejectionError) ErrorCode() int         { return -32000 }
+func (rejectionError) ErrorData() interface{} { return "0x01" }
+
+// testSequencer is a sequencer accepting or rejecting the forwarded
+// transactions.
+type testSequencer struct {
+	lock        sync.Mutex
+	reject      bool
+	received    []common.Hash
+	conditional []*types.TransactionConditional
+	private     []bool
+}
+
+func (s *testSequencer) SendRawTransaction(input hexutil.Bytes) (common.Hash, error) {
+	return s.receive(input, nil, false)
+}
+
+func (s *testSequencer) SendRawTransactionConditional(input hexutil.Bytes, cond types.TransactionConditional) (common.Hash, error) {
+	return s.receive(input, &cond, false)
+}
+
+func (s *testSequencer) SendPrivateRawTransaction(input hexutil.Bytes) (common.Hash, error) {
+	return s.receive(input, nil, true)
+}
+
+func (s *testSequencer) receive(input hexutil.Bytes, cond *types.TransactionConditional, private bool) (common.Hash, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.reject {
+		return common.Hash{}, rejectionError{}
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	s.received = append(s.received, tx.Hash())
+	s.conditional = append(s.conditional, cond)
+	s.private = append(s.private, private)
+	return tx.Hash(), nil
+}
+
+// newTestSequencer starts a sequencer serving over HTTP.
+func newTestSequencer(t *testing.T) (*testSequencer, *httptest.Server) {
+	sequencer := new(testSequencer)
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", sequencer); err != nil {
+		t.Fatalf("failed to register sequencer: %v", err)
+	}
+	httpsrv := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpsrv.Close()
+		server.Stop()
+	})
+	return sequencer, httpsrv
+}
+
+// newTestForwarder creates a forwarder towards the given endpoint, without
+// starting its retry loop.
+func newTestForwarder(t *testing.T, endpoint string, config Config) *Forwarder {
+	client, err := rpc.DialHTTP(endpoint)
+	if err != nil {
+		t.Fatalf("failed to dial sequencer: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return New(endpoint, client, config)
+}
+
+func newTestTransaction(t *testing.T, nonce uint64) *types.Transaction {
+	key, _ := crypto.GenerateKey()
+	tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(1), params.TxGas, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	return tx
+}
+
+// Tests that transactions, along with their conditional and privacy, reach the
+// sequencer.
+func TestForward(t *testing.T) {
+	sequencer, server := newTestSequencer(t)
+	forwarder := newTestForwarder(t, server.URL, DefaultConfig)
+
+	plain := newTestTransaction(t, 0)
+	if err := forwarder.Forward(context.Background(), plain); err != nil {
+		t.Fatalf("failed to forward transaction: %v", err)
+	}
+	number := big.NewInt(10)
+	conditional := newTestTransaction(t, 1)
+	conditional.SetConditional(&types.TransactionConditional{BlockNumberMax: number})
+	if err := forwarder.Forward(context.Background(), conditional); err != nil {
+		t.Fatalf("failed to forward conditional transaction: %v", err)
+	}
+	private := newTestTransaction(t, 2)
+	private.SetPrivate(true)
+	if err := forwarder.Forward(context.Background(), private); err != nil {
+		t.Fatalf("failed to forward private transaction: %v", err)
+	}
+	if len(sequencer.received) != 3 || sequencer.received[0] != plain.Hash() || sequencer.received[1] != conditional.Hash() || sequencer.received[2] != private.Hash() {
+		t.Fatalf("sequencer received mismatch: have %v, want [%x %x %x]", sequencer.received, plain.Hash(), conditional.Hash(), private.Hash())
+	}
+	if sequencer.conditional[0] != nil {
+		t.Errorf("plain transaction forwarded with conditional")
+	}
+	if cond := sequencer.conditional[1]; cond == nil || cond.BlockNumberMax.Cmp(number) != 0 {
+		t.Errorf("conditional mismatch: have %v, want block number maximum %v", cond, number)
+	}
+	if sequencer.private[0] || sequencer.private[1] || !sequencer.private[2] {
+		t.Errorf("privacy mismatch: have %v, want [false false true]", sequencer.private)
+	}
+	status := forwarder.Status()
+	if status.Forwarded != 3 || status.Rejected != 0 || status.Failed != 0 || status.Queued != 0 {
+		t.Errorf("status mismatch: %+v", status)
+	}
+	if status.LastSuccess == nil {
+		t.Errorf("last success not reported")
+	}
+}
+
+// Tests that the sequencer rejecting a transaction is reported as is, without
+// queueing the transaction for retrying.
+func TestForwardRejected(t *testing.T) {
+	sequencer, server := newTestSequencer(t)
+	sequencer.reject = true
+	forwarder := newTestForwarder(t, server.URL, DefaultConfig)
+
+	err := forwarder.Forward(context.Background(), newTestTransaction(t, 0))
+
+	var fwdErr *Error
+	if !errors.As(err, &fwdErr) {
+		t.Fatalf("error type mismatch: have %T (%v), want %T", err, err, fwdErr)
+	}
+	if code := fwdErr.ErrorCode(); code != -32000 {
+		t.Errorf("error code mismatch: have %d, want %d", code, -32000)
+	}
+	if data := fwdErr.ErrorData(); data != "0x01" {
+		t.Errorf("error data mismatch: have %v, want %v", data, "0x01")
+	}
+	status := forwarder.Status()
+	if status.Rejected != 1 || status.Queued != 0 || status.LastError == "" {
+		t.Errorf("status mismatch: %+v", status)
+	}
+}
+
+// Tests that transactions the sequencer cannot be reached for are queued,
+// retried and eventually dropped.
+func TestForwardUnreachable(t *testing.T) {
+	_, server := newTestSequencer(t)
+	config := Config{
+		Timeout:       time.Second,
+		Retries:       2,
+		RetryInterval: time.Nanosecond,
+		QueueSize:     1,
+	}
+	forwarder := newTestForwarder(t, server.URL, config)
+	server.Close()
+
+	if err := forwarder.Forward(context.Background(), newTestTransaction(t, 0)); err != nil {
+		t.Fatalf("failed to queue transaction: %v", err)
+	}
+	if err := forwarder.Forward(context.Background(), newTestTransaction(t, 1)); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("queue overflow error mismatch: have %v, want %v", err, ErrQueueFull)
+	}
+	if status := forwarder.Status(); status.Queued != 1 || status.Dropped != 1 || status.Failed != 2 {
+		t.Fatalf("status mismatch after queueing: %+v", status)
+	}
+	// Retry until the queued transaction runs out of retries
+	for i := 0; i < config.Retries; i++ {
+		forwarder.retry()
+	}
+	status := forwarder.Status()
+	if status.Queued != 0 || status.Dropped != 2 || status.Failed != 4 || status.Forwarded != 0 {
+		t.Fatalf("status mismatch after retries: %+v", status)
+	}
+	if status.LastError == "" || status.LastErrorTime == nil {
+		t.Errorf("last error not reported")
+	}
+}
+
+// Tests that queued transactions are forwarded once the sequencer is reachable.
+func TestForwardRetry(t *testing.T) {
+	sequencer, server := newTestSequencer(t)
+	config := Config{
+		Timeout:       time.Second,
+		Retries:       2,
+		RetryInterval: time.Nanosecond,
+		QueueSize:     1,
+	}
+	forwarder := newTestForwarder(t, "http://127.0.0.1:1", config)
+	tx := newTestTransaction(t, 0)
+	if err := forwarder.Forward(context.Background(), tx); err != nil {
+		t.Fatalf("failed to queue transaction: %v", err)
+	}
+	// Point the forwarder at the live sequencer and retry
+	client, err := rpc.DialHTTP(server.URL)
+	if err != nil {
+		t.Fatalf("failed to dial sequencer: %v", err)
+	}
+	defer client.Close()
+	forwarder.client = client
+	forwarder.retry()
+
+	if len(sequencer.received) != 1 || sequencer.received[0] != tx.Hash() {
+		t.Fatalf("sequencer received mismatch: have %v, want [%x]", sequencer.received, tx.Hash())
+	}
+	if status := forwarder.Status(); status.Queued != 0 || status.Forwarded != 1 || status.Failed != 1 {
+		t.Errorf("status mismatch: %+v", status)
+	}
+}
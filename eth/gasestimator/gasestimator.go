@@ -26,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/log"
@@ -43,6 +44,9 @@ type Options struct {
 	Header *types.Header       // Header defining the block context to execute in
 	State  *state.StateDB      // Pre-state on top of which to estimate the gas
 
+	SolanaBlockNumber *uint64 // Solana slot to execute in, nil if unknown
+	SolanaTimestamp   *int64  // Solana timestamp to execute in, nil if unknown
+
 	ErrorRatio float64 // Allowed overestimation ratio for faster estimation termination
 }
 
@@ -56,7 +60,7 @@ func Estimate(ctx context.Context, call *core.Message, opts *Options, gasCap uin
 		hi uint64 // lowest-known gas limit where tx execution succeeds
 	)
 	// Determine the highest gas limit can be used during the estimation.
-	hi = opts.Header.GasLimit
+	hi = txpool.EffectiveGasLimit(opts.Config, opts.Header)
 	if call.GasLimit >= params.TxGas {
 		hi = call.GasLimit
 	}
@@ -211,8 +215,11 @@ func run(ctx context.Context, call *core.Message, opts *Options) (*core.Executio
 		evmContext = core.NewEVMBlockContext(opts.Header, opts.Chain, nil, opts.Config, opts.State)
 
 		dirtyState = opts.State.Copy()
-		evm        = vm.NewEVM(evmContext, msgContext, dirtyState, opts.Config, vm.Config{NoBaseFee: true})
 	)
+	msgContext.SolanaBlockNumber = opts.SolanaBlockNumber
+	msgContext.SolanaTimestamp = opts.SolanaTimestamp
+	evm := vm.NewEVM(evmContext, msgContext, dirtyState, opts.Config, vm.Config{NoBaseFee: true})
+
 	// Monitor the outer context and interrupt the EVM upon cancellation. To avoid
 	// a dangling goroutine until the outer estimation finishes, create an internal
 	// context for the lifetime of this method call.
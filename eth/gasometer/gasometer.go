@@ -0,0 +1,433 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package gasometer implements the client of the Rome gasometer, which prices,
+// estimates and emulates transactions against the Solana side of the chain.
+package gasometer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// ErrUnavailable is returned if the gasometer could not be reached, as
+	// opposed to the gasometer rejecting a request.
+	ErrUnavailable = errors.New("gasometer unavailable")
+
+	errNotConfigured = fmt.Errorf("%w: no gasometer configured", ErrUnavailable)
+	errCircuitOpen   = fmt.Errorf("%w: circuit breaker open", ErrUnavailable)
+)
+
+var (
+	requestTimer        = metrics.NewRegisteredTimer("gasometer/requests", nil)
+	failureMeter        = metrics.NewRegisteredMeter("gasometer/failures", nil)
+	retryMeter          = metrics.NewRegisteredMeter("gasometer/retries", nil)
+	fallbackMeter       = metrics.NewRegisteredMeter("gasometer/fallbacks", nil)
+	skipEmulationMeter  = metrics.NewRegisteredMeter("gasometer/emulation/skipped", nil)
+	breakerOpenMeter    = metrics.NewRegisteredMeter("gasometer/breaker/open", nil)
+	breakerRejectMeter  = metrics.NewRegisteredMeter("gasometer/breaker/rejected", nil)
+	priceCacheHitMeter  = metrics.NewRegisteredMeter("gasometer/pricecache/hit", nil)
+	priceCacheMissMeter = metrics.NewRegisteredMeter("gasometer/pricecache/miss", nil)
+)
+
+// Gasometer prices, estimates and emulates transactions on behalf of Rome. It
+// is either served remotely by the Rome gasometer or by local EVM execution.
+type Gasometer interface {
+	// GasPrice returns the gas price suggested for legacy transactions.
+	GasPrice(ctx context.Context) (*big.Int, error)
+
+	// EstimateGas returns the gas needed by the transaction call.
+	EstimateGas(ctx context.Context, args interface{}) (uint64, error)
+
+	// EmulateTx emulates the signed transaction, returning an error if it would
+	// not be executable.
+	EmulateTx(ctx context.Context, input hexutil.Bytes) error
+
+	// Emulate emulates the signed transaction and returns the outcome of its
+	// execution. A failing execution is reported in the result, an error is
+	// only returned if the transaction could not be emulated at all.
+	Emulate(ctx context.Context, input hexutil.Bytes) (*EmulationResult, error)
+
+	// Fallback reports whether a failed pricing or estimation request should be
+	// served locally.
+	Fallback(err error) bool
+
+	// SkipEmulation reports whether a transaction whose emulation failed should
+	// be submitted without being emulated.
+	SkipEmulation(err error) bool
+}
+
+// EmulationResult is the outcome of emulating a transaction on Rome.
+type EmulationResult struct {
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`              // Rome gas the transaction is expected to use
+	Footprint  string         `json:"footprint,omitempty"`  // Expected state footprint of the transaction
+	Logs       []*EmulatedLog `json:"logs"`                 // Logs emitted by the transaction
+	ReturnData hexutil.Bytes  `json:"returnData,omitempty"` // Returned data, the revert data of reverted executions
+	Error      string         `json:"error,omitempty"`      // Execution error, empty if the transaction succeeds
+}
+
+// Failed reports whether the emulated execution failed.
+func (r *EmulationResult) Failed() bool {
+	return r.Error != ""
+}
+
+// EmulatedLog is a log emitted by an emulated transaction.
+type EmulatedLog struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+}
+
+// Config are the configuration parameters of the gasometer client.
+type Config struct {
+	URL              string        `toml:",omitempty"` // Endpoint of the gasometer, disabled if empty
+	Local            bool          `toml:",omitempty"` // Serve gasometer requests from local EVM execution instead
+	Timeout          time.Duration // Timeout of a single gasometer request
+	Retries          int           // Number of retries of a request failing to reach the gasometer
+	RetryBackoff     time.Duration // Delay before the first retry, doubled on every further retry
+	PriceCacheTTL    time.Duration // Duration a fetched gas price is served from cache
+	BreakerThreshold int           // Number of consecutive failures opening the circuit breaker
+	BreakerCooldown  time.Duration // Duration the circuit breaker stays open before probing again
+	Fallback         bool          // Whether to fall back to local pricing/estimation if unavailable
+	SkipEmulation    bool          // Whether to submit transactions unemulated if unavailable
+}
+
+// DefaultConfig contains the default gasometer client settings.
+var DefaultConfig = Config{
+	Timeout:          5 * time.Second,
+	Retries:          2,
+	RetryBackoff:     100 * time.Millisecond,
+	PriceCacheTTL:    2 * time.Second,
+	BreakerThreshold: 5,
+	BreakerCooldown:  30 * time.Second,
+}
+
+// sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *Config) sanitize() Config {
+	conf := *config
+	if conf.Timeout <= 0 {
+		log.Warn("Sanitizing invalid gasometer timeout", "provided", conf.Timeout, "updated", DefaultConfig.Timeout)
+		conf.Timeout = DefaultConfig.Timeout
+	}
+	if conf.Retries < 0 {
+		log.Warn("Sanitizing invalid gasometer retries", "provided", conf.Retries, "updated", DefaultConfig.Retries)
+		conf.Retries = DefaultConfig.Retries
+	}
+	if conf.BreakerThreshold <= 0 {
+		log.Warn("Sanitizing invalid gasometer breaker threshold", "provided", conf.BreakerThreshold, "updated", DefaultConfig.BreakerThreshold)
+		conf.BreakerThreshold = DefaultConfig.BreakerThreshold
+	}
+	return conf
+}
+
+// Client is a long-lived connection to the Rome gasometer. It is registered as
+// a node lifecycle, retries requests that fail to reach the gasometer, caches
+// gas prices and stops hammering an unreachable gasometer via a circuit breaker.
+type Client struct {
+	config Config
+
+	lock   sync.Mutex
+	client *rpc.Client // Lazily (re)dialed connection, nil if disconnected
+
+	priceLock sync.Mutex
+	price     *big.Int  // Last fetched gas price
+	priceTime time.Time // Time the gas price was fetched at
+
+	breakerLock sync.Mutex
+	failures    int       // Consecutive failures to reach the gasometer
+	openUntil   time.Time // Time the circuit breaker closes again, zero if closed
+	probing     bool      // Whether a request is probing a half-open breaker
+}
+
+var _ Gasometer = (*Client)(nil)
+
+// New creates a gasometer client. The connection is established on Start.
+func New(config Config) *Client {
+	return &Client{config: config.sanitize()}
+}
+
+// Start implements node.Lifecycle, establishing the gasometer connection. An
+// unreachable gasometer does not prevent the node from starting, the connection
+// is retried on demand.
+func (c *Client) Start() error {
+	if c.config.URL == "" {
+		log.Info("No gasometer configured")
+		return nil
+	}
+	if _, err := c.conn(); err != nil {
+		log.Warn("Failed to connect to gasometer", "url", c.config.URL, "err", err)
+	} else {
+		log.Info("Connected to gasometer", "url", c.config.URL)
+	}
+	return nil
+}
+
+// Stop implements node.Lifecycle, closing the gasometer connection.
+func (c *Client) Stop() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.client != nil {
+		c.client.Close()
+		c.client = nil
+	}
+	return nil
+}
+
+// GasPrice returns the gas price suggested by the gasometer, served from cache
+// if fetched recently.
+func (c *Client) GasPrice(ctx context.Context) (*big.Int, error) {
+	if c == nil || c.config.URL == "" {
+		return nil, errNotConfigured
+	}
+	c.priceLock.Lock()
+	defer c.priceLock.Unlock()
+
+	if c.price != nil && time.Since(c.priceTime) < c.config.PriceCacheTTL {
+		priceCacheHitMeter.Mark(1)
+		return new(big.Int).Set(c.price), nil
+	}
+	priceCacheMissMeter.Mark(1)
+
+	var price hexutil.Big
+	if err := c.call(ctx, &price, "eth_gasPrice"); err != nil {
+		return nil, err
+	}
+	c.price, c.priceTime = price.ToInt(), time.Now()
+	return new(big.Int).Set(c.price), nil
+}
+
+// EstimateGas returns the gas needed by the transaction call on Rome.
+func (c *Client) EstimateGas(ctx context.Context, args interface{}) (uint64, error) {
+	var gas hexutil.Uint64
+	if err := c.call(ctx, &gas, "eth_estimateGas", args); err != nil {
+		return 0, err
+	}
+	return uint64(gas), nil
+}
+
+// EmulateTx emulates the signed transaction on Rome, returning an error if it
+// would not be executable.
+func (c *Client) EmulateTx(ctx context.Context, input hexutil.Bytes) error {
+	var result interface{}
+	if err := c.call(ctx, &result, "rome_emulateTx", input); err != nil {
+		return fmt.Errorf("call to rome_emulateTx failed: %w", err)
+	}
+	return nil
+}
+
+// Emulate emulates the signed transaction on Rome and returns the outcome of
+// its execution.
+func (c *Client) Emulate(ctx context.Context, input hexutil.Bytes) (*EmulationResult, error) {
+	var raw json.RawMessage
+	if err := c.call(ctx, &raw, "rome_emulateTx", input); err != nil {
+		return nil, fmt.Errorf("call to rome_emulateTx failed: %w", err)
+	}
+	result := new(EmulationResult)
+	if err := json.Unmarshal(raw, result); err != nil {
+		log.Debug("Unexpected gasometer emulation result", "result", string(raw), "err", err)
+		return nil, fmt.Errorf("invalid rome_emulateTx result: %w", err)
+	}
+	return result, nil
+}
+
+// Fallback reports whether a failed gasometer request should be served locally
+// instead, i.e. the configured gasometer was unreachable and fallbacks are
+// enabled.
+func (c *Client) Fallback(err error) bool {
+	if c == nil || !c.config.Fallback || !unavailable(err) {
+		return false
+	}
+	fallbackMeter.Mark(1)
+	return true
+}
+
+// SkipEmulation reports whether a transaction should be submitted without being
+// emulated, i.e. the configured gasometer was unreachable and skipping the
+// emulation is enabled.
+func (c *Client) SkipEmulation(err error) bool {
+	if c == nil || !c.config.SkipEmulation || !unavailable(err) {
+		return false
+	}
+	skipEmulationMeter.Mark(1)
+	return true
+}
+
+// unavailable reports whether the error is caused by a configured gasometer not
+// being reachable. A missing gasometer configuration is never papered over.
+func unavailable(err error) bool {
+	return errors.Is(err, ErrUnavailable) && !errors.Is(err, errNotConfigured)
+}
+
+// call invokes a gasometer method, retrying if the gasometer cannot be reached.
+// Errors returned by the gasometer itself are passed through as is, failures to
+// reach it are wrapped into ErrUnavailable.
+func (c *Client) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if c == nil || c.config.URL == "" {
+		return errNotConfigured
+	}
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	var (
+		backoff = c.config.RetryBackoff
+		err     error
+	)
+	for attempt := 0; attempt <= c.config.Retries; attempt++ {
+		if attempt > 0 {
+			retryMeter.Mark(1)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				c.abort()
+				return ctx.Err()
+			}
+			backoff *= 2
+		}
+		err = c.attempt(ctx, result, method, args...)
+		if err == nil || !unreachable(err) {
+			c.release(true)
+			return err
+		}
+		if ctx.Err() != nil {
+			// The caller gave up, don't hold it against the gasometer
+			c.abort()
+			return ctx.Err()
+		}
+		log.Debug("Gasometer request failed", "method", method, "attempt", attempt+1, "err", err)
+	}
+	failureMeter.Mark(1)
+	c.release(false)
+	return fmt.Errorf("%w: %v", ErrUnavailable, err)
+}
+
+// attempt performs a single gasometer request within the configured timeout.
+func (c *Client) attempt(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	client, err := c.conn()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err = client.CallContext(ctx, result, method, args...)
+	requestTimer.UpdateSince(start)
+
+	if err != nil && unreachable(err) {
+		c.disconnect(client)
+	}
+	return err
+}
+
+// conn returns the gasometer connection, dialing it if necessary.
+func (c *Client) conn() (*rpc.Client, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.client != nil {
+		return c.client, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+
+	client, err := rpc.DialContext(ctx, c.config.URL)
+	if err != nil {
+		return nil, err
+	}
+	c.client = client
+	return client, nil
+}
+
+// disconnect drops the given connection, so the next request redials.
+func (c *Client) disconnect(client *rpc.Client) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.client == client {
+		c.client.Close()
+		c.client = nil
+	}
+}
+
+// acquire checks whether the circuit breaker admits a request. Once the
+// cooldown of an open breaker passed, a single probing request is admitted.
+func (c *Client) acquire() error {
+	c.breakerLock.Lock()
+	defer c.breakerLock.Unlock()
+
+	if c.openUntil.IsZero() {
+		return nil
+	}
+	if time.Now().Before(c.openUntil) || c.probing {
+		breakerRejectMeter.Mark(1)
+		return errCircuitOpen
+	}
+	c.probing = true
+	return nil
+}
+
+// release reports the outcome of an admitted request to the circuit breaker.
+func (c *Client) release(reached bool) {
+	c.breakerLock.Lock()
+	defer c.breakerLock.Unlock()
+
+	c.probing = false
+	if reached {
+		if !c.openUntil.IsZero() {
+			log.Info("Gasometer reachable again, closing circuit breaker")
+		}
+		c.failures, c.openUntil = 0, time.Time{}
+		return
+	}
+	c.failures++
+	if c.failures >= c.config.BreakerThreshold {
+		if c.openUntil.IsZero() {
+			log.Warn("Gasometer unreachable, opening circuit breaker", "failures", c.failures, "cooldown", c.config.BreakerCooldown)
+			breakerOpenMeter.Mark(1)
+		}
+		c.openUntil = time.Now().Add(c.config.BreakerCooldown)
+	}
+}
+
+// abort releases an admitted request without reporting an outcome to the
+// circuit breaker.
+func (c *Client) abort() {
+	c.breakerLock.Lock()
+	defer c.breakerLock.Unlock()
+
+	c.probing = false
+}
+
+// unreachable reports whether an error signals a failure to reach the gasometer,
+// as opposed to an error response of the gasometer itself.
+func unreachable(err error) bool {
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
@@ -0,0 +1,184 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasometer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type testService struct {
+	calls atomic.Int32
+}
+
+func (s *testService) GasPrice() *hexutil.Big {
+	s.calls.Add(1)
+	return (*hexutil.Big)(hexutil.MustDecodeBig("0x3b9aca00"))
+}
+
+func (s *testService) EstimateGas(args map[string]interface{}) (hexutil.Uint64, error) {
+	s.calls.Add(1)
+	return 0, errors.New("execution reverted")
+}
+
+type testRomeService struct{}
+
+func (s *testRomeService) EmulateTx(input hexutil.Bytes) interface{} {
+	if len(input) == 0 {
+		return "unexpected"
+	}
+	return map[string]interface{}{
+		"gasUsed":   "0x5208",
+		"footprint": "0x01",
+		"logs":      []map[string]interface{}{{"address": "0x0000000000000000000000000000000000001000", "topics": []string{}, "data": "0x"}},
+		"error":     "execution reverted",
+	}
+}
+
+func newTestServer(t *testing.T) (*testService, *httptest.Server) {
+	service := new(testService)
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.RegisterName("rome", new(testRomeService)); err != nil {
+		t.Fatal(err)
+	}
+	httpsrv := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpsrv.Close()
+		server.Stop()
+	})
+	return service, httpsrv
+}
+
+// Tests that gas prices are cached and gasometer errors are passed through
+// without retries.
+func TestGasometerCalls(t *testing.T) {
+	service, server := newTestServer(t)
+
+	config := DefaultConfig
+	config.URL = server.URL
+	client := New(config)
+	client.Start()
+	defer client.Stop()
+
+	for i := 0; i < 3; i++ {
+		price, err := client.GasPrice(context.Background())
+		if err != nil {
+			t.Fatalf("failed to fetch gas price: %v", err)
+		}
+		if price.Uint64() != 1000000000 {
+			t.Fatalf("gas price mismatch: have %v, want %v", price, 1000000000)
+		}
+	}
+	if calls := service.calls.Load(); calls != 1 {
+		t.Fatalf("cached gas price refetched: have %d calls, want 1", calls)
+	}
+	_, err := client.EstimateGas(context.Background(), map[string]interface{}{})
+	if err == nil || errors.Is(err, ErrUnavailable) {
+		t.Fatalf("unexpected estimation error: %v", err)
+	}
+	if client.Fallback(err) {
+		t.Fatal("fallback on gasometer error")
+	}
+	if calls := service.calls.Load(); calls != 2 {
+		t.Fatalf("gasometer error retried: have %d calls, want 2", calls)
+	}
+	res, err := client.Emulate(context.Background(), hexutil.Bytes{0x01})
+	if err != nil {
+		t.Fatalf("failed to emulate transaction: %v", err)
+	}
+	if res.GasUsed != 21000 || res.Footprint != "0x01" || len(res.Logs) != 1 || !res.Failed() {
+		t.Fatalf("unexpected emulation result: %+v", res)
+	}
+	if res, err := client.Emulate(context.Background(), hexutil.Bytes{}); err == nil {
+		t.Fatalf("malformed emulation result accepted: %+v", res)
+	}
+}
+
+// Tests that fallbacks are opt-in and never cover for a missing gasometer.
+func TestGasometerFallback(t *testing.T) {
+	unavailable := fmt.Errorf("%w: connection refused", ErrUnavailable)
+
+	client := New(DefaultConfig)
+	if client.Fallback(unavailable) || client.SkipEmulation(unavailable) {
+		t.Fatal("fallback without being enabled")
+	}
+	client = New(Config{URL: "http://localhost", Fallback: true, SkipEmulation: true})
+	if !client.Fallback(unavailable) || !client.SkipEmulation(unavailable) {
+		t.Fatal("no fallback on unavailable gasometer")
+	}
+	if client.Fallback(errors.New("execution reverted")) || client.SkipEmulation(errors.New("execution reverted")) {
+		t.Fatal("fallback on gasometer error")
+	}
+	_, err := New(Config{Fallback: true, SkipEmulation: true}).GasPrice(context.Background())
+	if client.Fallback(err) || client.SkipEmulation(err) {
+		t.Fatalf("fallback on missing gasometer: %v", err)
+	}
+}
+
+// Tests that an unreachable gasometer opens the circuit breaker, which is closed
+// again by a successful probe after the cooldown.
+func TestGasometerCircuitBreaker(t *testing.T) {
+	_, server := newTestServer(t)
+	url := server.URL
+	server.Close()
+
+	client := New(Config{
+		URL:              url,
+		Timeout:          time.Second,
+		Retries:          1,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Hour,
+		Fallback:         true,
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := client.GasPrice(context.Background()); !errors.Is(err, ErrUnavailable) || errors.Is(err, errCircuitOpen) {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+	}
+	_, err := client.GasPrice(context.Background())
+	if !errors.Is(err, errCircuitOpen) {
+		t.Fatalf("circuit breaker not open: %v", err)
+	}
+	if !client.Fallback(err) {
+		t.Fatal("no fallback on unavailable gasometer")
+	}
+	if client.SkipEmulation(err) {
+		t.Fatal("emulation skipped without being enabled")
+	}
+	// Bring the gasometer back and let the cooldown pass
+	_, server = newTestServer(t)
+	client.config.URL = server.URL
+	client.openUntil = time.Now()
+
+	if _, err := client.GasPrice(context.Background()); err != nil {
+		t.Fatalf("probe failed: %v", err)
+	}
+	if !client.openUntil.IsZero() || client.failures != 0 {
+		t.Fatal("circuit breaker not closed after successful probe")
+	}
+}
@@ -264,28 +264,33 @@ func (oracle *Oracle) getBlockValues(ctx context.Context, blockNum uint64, limit
 	}
 	signer := types.MakeSigner(oracle.backend.ChainConfig(), block.Number(), block.Time())
 
-	// Sort the transaction by effective tip in ascending sort.
+	// Retrieve the receipts holding the gas prices charged by Rome. Missing
+	// receipts fall back to the tips derived from the transactions.
 	txs := block.Transactions()
-	sortedTxs := make([]*types.Transaction, len(txs))
-	copy(sortedTxs, txs)
-	baseFee := block.BaseFee()
-	slices.SortFunc(sortedTxs, func(a, b *types.Transaction) int {
-		// It's okay to discard the error because a tx would never be
-		// accepted into a block with an invalid effective tip.
-		tip1, _ := a.EffectiveGasTip(baseFee)
-		tip2, _ := b.EffectiveGasTip(baseFee)
-		return tip1.Cmp(tip2)
+	receipts, _ := oracle.backend.GetReceipts(ctx, block.Hash())
+	if len(receipts) != len(txs) {
+		receipts = make(types.Receipts, len(txs))
+	}
+	// Sort the transaction by effective tip in ascending sort.
+	var (
+		baseFee   = block.BaseFee()
+		sortedTxs = make([]txTip, len(txs))
+	)
+	for i, tx := range txs {
+		sortedTxs[i] = txTip{tx: tx, tip: romeTip(tx, receipts[i], baseFee)}
+	}
+	slices.SortFunc(sortedTxs, func(a, b txTip) int {
+		return a.tip.Cmp(b.tip)
 	})
 
 	var prices []*big.Int
 	for _, tx := range sortedTxs {
-		tip, _ := tx.EffectiveGasTip(baseFee)
-		if ignoreUnder != nil && tip.Cmp(ignoreUnder) == -1 {
+		if ignoreUnder != nil && tx.tip.Cmp(ignoreUnder) == -1 {
 			continue
 		}
-		sender, err := types.Sender(signer, tx)
+		sender, err := types.Sender(signer, tx.tx)
 		if err == nil && sender != block.Coinbase() {
-			prices = append(prices, tip)
+			prices = append(prices, tx.tip)
 			if len(prices) >= limit {
 				break
 			}
@@ -297,6 +302,12 @@ func (oracle *Oracle) getBlockValues(ctx context.Context, blockNum uint64, limit
 	}
 }
 
+// txTip is a transaction along with the effective tip it paid.
+type txTip struct {
+	tx  *types.Transaction
+	tip *big.Int
+}
+
 type bigIntArray []*big.Int
 
 func (s bigIntArray) Len() int           { return len(s) }
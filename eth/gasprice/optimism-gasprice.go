@@ -6,6 +6,7 @@ import (
 	"sort"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -53,13 +54,15 @@ func (oracle *Oracle) SuggestOptimismPriorityFee(ctx context.Context, h *types.H
 		}
 	}
 
-	// sanity check the max gas used value
-	if maxTxGasUsed > h.GasLimit {
+	// sanity check the max gas used value. Rome blocks held to the gas limit proxy never reach
+	// capacity, leaving the minimum suggestion until the chain follows the header gas limits.
+	gasLimit := txpool.EffectiveGasLimit(oracle.backend.ChainConfig(), h)
+	if maxTxGasUsed > gasLimit {
 		log.Error("found tx consuming more gas than the block limit", "gas", maxTxGasUsed)
 		return suggestion
 	}
 
-	if h.GasUsed+maxTxGasUsed > h.GasLimit {
+	if h.GasUsed+maxTxGasUsed > gasLimit {
 		// A block is "at capacity" if, when it is built, there is a pending tx in the txpool that
 		// could not be included because the block's gas limit would be exceeded. Since we don't
 		// have access to the txpool, we instead adopt the following heuristic: consider a block as
@@ -85,7 +88,7 @@ func (oracle *Oracle) SuggestOptimismPriorityFee(ctx context.Context, h *types.H
 		}
 		tips := bigIntArray(make([]*big.Int, len(txs)))
 		for i := range txs {
-			tips[i] = txs[i].EffectiveGasTipValue(baseFee)
+			tips[i] = romeTip(txs[i], receipts[i], baseFee)
 		}
 		sort.Sort(tips)
 		median := tips[len(tips)/2]
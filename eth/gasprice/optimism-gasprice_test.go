@@ -41,6 +41,7 @@ type testTxData struct {
 }
 
 type opTestBackend struct {
+	config   *params.ChainConfig
 	block    *types.Block
 	receipts []*types.Receipt
 }
@@ -62,7 +63,7 @@ func (b *opTestBackend) PendingBlockAndReceipts() (*types.Block, types.Receipts)
 }
 
 func (b *opTestBackend) ChainConfig() *params.ChainConfig {
-	return params.OptimismTestConfig
+	return b.config
 }
 
 func (b *opTestBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
@@ -101,7 +102,11 @@ func newOpTestBackend(t *testing.T, txs []testTxData) *opTestBackend {
 	}
 	hasher := trie.NewStackTrie(nil)
 	b := types.NewBlock(&header, ts, nil, nil, hasher)
-	return &opTestBackend{block: b, receipts: rs}
+
+	// follow the header gas limits from genesis, the block capacity is not known otherwise
+	config := *params.OptimismTestConfig
+	config.Rome = &params.RomeConfig{GasLimitTime: new(uint64)}
+	return &opTestBackend{config: &config, block: b, receipts: rs}
 }
 
 func TestSuggestOptimismPriorityFee(t *testing.T) {
@@ -0,0 +1,42 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// romeTip returns the priority fee per gas a transaction effectively paid. Rome
+// charges the gas price quoted by the Rome-EVM rather than the fee caps of the
+// transaction, and persists it in the receipt, so the tip is the charged price
+// in excess of the base fee. Transactions executed without a Rome gas price pay
+// the tip derived from their own fee caps.
+func romeTip(tx *types.Transaction, receipt *types.Receipt, baseFee *big.Int) *big.Int {
+	if receipt == nil || receipt.EffectiveGasPrice == nil || receipt.EffectiveGasPrice.Sign() == 0 {
+		return tx.EffectiveGasTipValue(baseFee)
+	}
+	tip := new(big.Int).Set(receipt.EffectiveGasPrice)
+	if baseFee != nil {
+		tip.Sub(tip, baseFee)
+	}
+	if tip.Sign() < 0 {
+		tip.SetInt64(0)
+	}
+	return tip
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// newRomeTestBackend creates a backend with a single block whose receipts hold
+// the given Rome gas prices, zero standing for a missing Rome gas price.
+func newRomeTestBackend(t *testing.T, txs []testTxData, romePrices []int64) *opTestBackend {
+	backend := newOpTestBackend(t, txs)
+	for i, price := range romePrices {
+		backend.receipts[i].EffectiveGasPrice = big.NewInt(price)
+	}
+	return backend
+}
+
+func TestRomeFeeHistoryRewards(t *testing.T) {
+	backend := newRomeTestBackend(t,
+		[]testTxData{{params.GWei, 10000}, {params.GWei, 30000}, {2 * params.GWei, 20000}},
+		[]int64{5 * params.GWei, 10 * params.GWei, 0},
+	)
+	oracle := NewOracle(backend, Config{})
+
+	// Rome blocks are built with a zero base fee
+	header := backend.block.Header()
+	header.Number, header.BaseFee = big.NewInt(1), new(big.Int)
+
+	bf := &blockFees{
+		blockNumber: header.Number.Uint64(),
+		header:      header,
+		block:       backend.block,
+		receipts:    backend.receipts,
+	}
+	oracle.processBlock(bf, []float64{0, 40, 60, 100})
+	if bf.err != nil {
+		t.Fatalf("failed to process block: %v", bf.err)
+	}
+	// Rewards are sorted by the charged prices and weighted by the Rome gas used:
+	// 20000 gas at 2 gwei (own tip), 10000 at 5 gwei and 30000 at 10 gwei
+	want := []int64{2 * params.GWei, 5 * params.GWei, 10 * params.GWei, 10 * params.GWei}
+	for i, reward := range bf.results.reward {
+		if reward.Int64() != want[i] {
+			t.Errorf("reward %d mismatch: have %v, want %d", i, reward, want[i])
+		}
+	}
+	if have, want := bf.results.gasUsedRatio, float64(60000)/float64(blockGasLimit); have != want {
+		t.Errorf("gas used ratio mismatch: have %v, want %v", have, want)
+	}
+}
+
+func TestSuggestRomePriorityFee(t *testing.T) {
+	// A full block whose transactions were charged Rome gas prices far from
+	// their declared tips, 10% over the median charged price is suggested
+	backend := newRomeTestBackend(t,
+		[]testTxData{{params.GWei, 21000}, {params.GWei, 21000}, {params.GWei, 21000}},
+		[]int64{3 * params.GWei, 20 * params.GWei, 7 * params.GWei},
+	)
+	oracle := NewOracle(backend, Config{})
+
+	got := oracle.SuggestOptimismPriorityFee(context.Background(), backend.block.Header(), backend.block.Hash())
+	if want := big.NewInt(77 * params.GWei / 10); got.Cmp(want) != 0 {
+		t.Errorf("tip suggestion mismatch: have %v, want %v", got, want)
+	}
+}
+
+func TestSuggestRomePriorityFeeGasLimitProxy(t *testing.T) {
+	// Until the header gas limit fork, blocks are held to the gas limit proxy
+	// and never reach capacity: the minimum tip is suggested even for a block
+	// full by its header, and barely any of the proxy is reported used.
+	backend := newRomeTestBackend(t,
+		[]testTxData{{params.GWei, 21000}, {params.GWei, 21000}, {params.GWei, 21000}},
+		[]int64{3 * params.GWei, 20 * params.GWei, 7 * params.GWei},
+	)
+	fork := backend.block.Time() + 1
+	backend.config.Rome = &params.RomeConfig{GasLimitTime: &fork}
+
+	minSuggestion := big.NewInt(params.GWei / 10)
+	oracle := NewOracle(backend, Config{MinSuggestedPriorityFee: minSuggestion})
+
+	got := oracle.SuggestOptimismPriorityFee(context.Background(), backend.block.Header(), backend.block.Hash())
+	if got.Cmp(minSuggestion) != 0 {
+		t.Errorf("tip suggestion mismatch: have %v, want %v", got, minSuggestion)
+	}
+	header := backend.block.Header()
+	header.Number, header.BaseFee = big.NewInt(1), new(big.Int)
+
+	bf := &blockFees{
+		blockNumber: header.Number.Uint64(),
+		header:      header,
+		block:       backend.block,
+		receipts:    backend.receipts,
+	}
+	oracle.processBlock(bf, nil)
+	if bf.err != nil {
+		t.Fatalf("failed to process block: %v", bf.err)
+	}
+	if have, want := bf.results.gasUsedRatio, float64(63000)/float64(48_000_000_000_000); have != want {
+		t.Errorf("gas used ratio mismatch: have %v, want %v", have, want)
+	}
+}
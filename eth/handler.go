@@ -17,6 +17,7 @@
 package eth
 
 import (
+	"context"
 	"errors"
 	"math"
 	"math/big"
@@ -272,7 +273,7 @@ func newHandler(config *handlerConfig) (*handler, error) {
 					log.Info("Filtered out non-terminal pow block", "number", block.NumberU64(), "hash", block.Hash())
 					return 0, nil
 				}
-				if err := h.chain.InsertBlockWithoutSetHead(block, make([]uint64, 0), make([]string, 0), make([]uint64, 0)); err != nil {
+				if err := h.chain.InsertBlockWithoutSetHead(context.Background(), block, make([]uint64, 0), make([]string, 0), make([]uint64, 0), false); err != nil {
 					return i, err
 				}
 			}
@@ -42,11 +42,25 @@ type NilPool struct{}
 // NilPool Get always returns nil
 func (n NilPool) Get(hash common.Hash) *types.Transaction { return nil }
 
+// publicPool satisfies the TxPool interface but does not return the privately
+// submitted txs in the pool. It is used to withhold them from the peers.
+type publicPool struct {
+	eth.TxPool
+}
+
+// publicPool Get returns the tx in the pool unless it is private
+func (p publicPool) Get(hash common.Hash) *types.Transaction {
+	if tx := p.TxPool.Get(hash); tx != nil && !tx.Private() {
+		return tx
+	}
+	return nil
+}
+
 func (h *ethHandler) TxPool() eth.TxPool {
 	if h.noTxGossip {
 		return &NilPool{}
 	}
-	return h.txpool
+	return publicPool{h.txpool}
 }
 
 // RunPeer is invoked when a peer joins on the `eth` protocol.
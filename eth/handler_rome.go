@@ -0,0 +1,68 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/eth/protocols/rome"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// romeHandler implements the rome.Backend interface to handle the various network
+// packets that are sent as replies or broadcasts.
+type romeHandler handler
+
+// romePeerInfo represents a short summary of the `rome` sub-protocol metadata known
+// about a connected peer.
+type romePeerInfo struct {
+	Version uint `json:"version"` // Rome protocol version negotiated
+}
+
+func (h *romeHandler) Chain() *core.BlockChain { return h.chain }
+
+// RunPeer is invoked when a peer joins on the `rome` protocol. Rome peers only
+// serve block metadata to the downloader, so they are tracked independently of
+// the `eth` peerset.
+func (h *romeHandler) RunPeer(peer *rome.Peer, hand rome.Handler) error {
+	if !(*handler)(h).incHandlers() {
+		return p2p.DiscQuitting
+	}
+	defer (*handler)(h).decHandlers()
+
+	if err := h.downloader.RomeSyncer.Register(peer); err != nil {
+		peer.Log().Debug("Rome peer registration failed", "err", err)
+		return err
+	}
+	defer h.downloader.RomeSyncer.Unregister(peer.ID())
+
+	return hand(peer)
+}
+
+// PeerInfo retrieves all known `rome` information about a peer.
+func (h *romeHandler) PeerInfo(id enode.ID) interface{} {
+	if p := h.downloader.RomeSyncer.Peer(id.String()); p != nil {
+		return &romePeerInfo{Version: p.Version()}
+	}
+	return nil
+}
+
+// Handle is invoked from a peer's message handler when it receives a new remote
+// message that the handler couldn't consume and serve itself.
+func (h *romeHandler) Handle(peer *rome.Peer, packet rome.Packet) error {
+	return h.downloader.DeliverRomePacket(peer, packet)
+}
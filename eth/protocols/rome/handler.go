@@ -0,0 +1,238 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rome
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+const (
+	// softResponseLimit is the target maximum size of replies to data retrievals.
+	softResponseLimit = 2 * 1024 * 1024
+
+	// maxRomeDataServe is the maximum number of blocks to serve the metadata
+	// of. This number is there to limit the number of disk lookups.
+	maxRomeDataServe = 128
+
+	// blockhashWindow is the number of Solana slots before a transaction whose
+	// blockhashes are reachable through the BLOCKHASH opcode.
+	blockhashWindow = 256
+)
+
+// Handler is a callback to invoke from an outside runner after the boilerplate
+// exchanges have passed.
+type Handler func(peer *Peer) error
+
+// Backend defines the data retrieval methods to serve remote requests and the
+// callback methods to invoke on remote deliveries.
+type Backend interface {
+	// Chain retrieves the blockchain object to serve data.
+	Chain() *core.BlockChain
+
+	// RunPeer is invoked when a peer joins on the `rome` protocol. The handler
+	// should do any peer maintenance work, handshakes and validations. If all
+	// is passed, control should be given back to the `handler` to process the
+	// inbound messages going forward.
+	RunPeer(peer *Peer, handler Handler) error
+
+	// PeerInfo retrieves all known `rome` information about a peer.
+	PeerInfo(id enode.ID) interface{}
+
+	// Handle is a callback to be invoked when a data packet is received from
+	// the remote peer. Only packets not consumed by the protocol handler will
+	// be forwarded to the backend.
+	Handle(peer *Peer, packet Packet) error
+}
+
+// MakeProtocols constructs the P2P protocol definitions for `rome`.
+func MakeProtocols(backend Backend) []p2p.Protocol {
+	protocols := make([]p2p.Protocol, len(ProtocolVersions))
+	for i, version := range ProtocolVersions {
+		version := version // Closure
+
+		protocols[i] = p2p.Protocol{
+			Name:    ProtocolName,
+			Version: version,
+			Length:  protocolLengths[version],
+			Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+				return backend.RunPeer(NewPeer(version, p, rw), func(peer *Peer) error {
+					return Handle(backend, peer)
+				})
+			},
+			NodeInfo: func() interface{} {
+				return nodeInfo(backend.Chain())
+			},
+			PeerInfo: func(id enode.ID) interface{} {
+				return backend.PeerInfo(id)
+			},
+		}
+	}
+	return protocols
+}
+
+// Handle is the callback invoked to manage the life cycle of a `rome` peer.
+// When this function terminates, the peer is disconnected.
+func Handle(backend Backend, peer *Peer) error {
+	for {
+		if err := HandleMessage(backend, peer); err != nil {
+			peer.Log().Debug("Message handling failed in `rome`", "err", err)
+			return err
+		}
+	}
+}
+
+// HandleMessage is invoked whenever an inbound message is received from a
+// remote peer on the `rome` protocol. The remote connection is torn down upon
+// returning any error.
+func HandleMessage(backend Backend, peer *Peer) error {
+	// Read the next message from the remote peer, and ensure it's fully consumed
+	msg, err := peer.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Size > maxMessageSize {
+		return fmt.Errorf("%w: %v > %v", errMsgTooLarge, msg.Size, maxMessageSize)
+	}
+	defer msg.Discard()
+	start := time.Now()
+	// Track the amount of time it takes to serve the request and run the handler
+	if metrics.Enabled {
+		h := fmt.Sprintf("%s/%s/%d/%#02x", p2p.HandleHistName, ProtocolName, peer.Version(), msg.Code)
+		defer func(start time.Time) {
+			sampler := func() metrics.Sample {
+				return metrics.ResettingSample(
+					metrics.NewExpDecaySample(1028, 0.015),
+				)
+			}
+			metrics.GetOrRegisterHistogramLazy(h, nil, sampler).Update(time.Since(start).Microseconds())
+		}(start)
+	}
+	// Handle the message depending on its contents
+	switch {
+	case msg.Code == GetRomeDataMsg:
+		// Decode the Rome metadata retrieval request
+		var req GetRomeDataPacket
+		if err := msg.Decode(&req); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		// Service the request, potentially returning nothing in case of errors
+		blocks := ServiceGetRomeDataQuery(backend.Chain(), &req)
+
+		// Send back anything accumulated (or empty in case of errors)
+		return p2p.Send(peer.rw, RomeDataMsg, &RomeDataPacket{
+			ID:     req.ID,
+			Blocks: blocks,
+		})
+
+	case msg.Code == RomeDataMsg:
+		// A batch of Rome metadata arrived to one of our previous requests
+		res := new(RomeDataPacket)
+		if err := msg.Decode(res); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		requestTracker.Fulfil(peer.id, peer.version, RomeDataMsg, res.ID)
+
+		return backend.Handle(peer, res)
+
+	default:
+		return fmt.Errorf("%w: %v", errInvalidMsgCode, msg.Code)
+	}
+}
+
+// ServiceGetRomeDataQuery assembles the response to a Rome metadata query.
+// It is exposed to allow external packages to test protocol behavior.
+func ServiceGetRomeDataQuery(chain *core.BlockChain, req *GetRomeDataPacket) []*BlockRomeData {
+	var (
+		bytes  int
+		blocks []*BlockRomeData
+	)
+	for lookups, hash := range req.Hashes {
+		if bytes >= softResponseLimit || len(blocks) >= maxRomeDataServe || lookups >= 2*maxRomeDataServe {
+			break
+		}
+		header := chain.GetHeaderByHash(hash)
+		if header == nil {
+			continue
+		}
+		body := chain.GetBody(hash)
+		if body == nil {
+			continue
+		}
+		block := &BlockRomeData{Hash: hash}
+		if data := chain.GetRomeBlockData(hash, header.Number.Uint64()); data != nil {
+			block.Data = *data
+		}
+		// Collect the Solana context of the transactions, tracking the slots
+		// they span to look up the blockhashes in their reach
+		var minSlot, maxSlot uint64
+		for i, tx := range body.Transactions {
+			slot, timestamp, ok := chain.GetSolanaTxMetadata(tx.Hash())
+			if !ok {
+				continue
+			}
+			if len(block.SolanaTxs) == 0 || slot < minSlot {
+				minSlot = slot
+			}
+			if slot > maxSlot {
+				maxSlot = slot
+			}
+			block.SolanaTxs = append(block.SolanaTxs, SolanaTxData{Index: uint64(i), Slot: slot, Timestamp: uint64(timestamp)})
+		}
+		if len(block.SolanaTxs) > 0 && chain.Config().IsRomeBlockhash(header.Time) {
+			from := uint64(0)
+			if minSlot > blockhashWindow {
+				from = minSlot - blockhashWindow
+			}
+			for slot := from; slot < maxSlot; slot++ {
+				if hash := chain.GetSolanaBlockhash(slot); hash != (common.Hash{}) {
+					block.Blockhashes = append(block.Blockhashes, SolanaBlockhash{Slot: slot, Hash: hash})
+				}
+			}
+		}
+		if len(block.Data.GasUsed) == 0 && len(block.Data.GasPrice) == 0 && len(block.Data.Footprints) == 0 && len(block.SolanaTxs) == 0 {
+			continue
+		}
+		blocks = append(blocks, block)
+		bytes += block.size()
+	}
+	return blocks
+}
+
+// size returns the approximate memory size of the block metadata.
+func (b *BlockRomeData) size() int {
+	size := common.HashLength + 8*(len(b.Data.GasUsed)+len(b.Data.GasPrice)) + 24*len(b.SolanaTxs) + 40*len(b.Blockhashes)
+	for _, footprint := range b.Data.Footprints {
+		size += len(footprint)
+	}
+	return size
+}
+
+// NodeInfo represents a short summary of the `rome` sub-protocol metadata
+// known about the host peer.
+type NodeInfo struct{}
+
+// nodeInfo retrieves some `rome` protocol metadata about the running host node.
+func nodeInfo(chain *core.BlockChain) *NodeInfo {
+	return &NodeInfo{}
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rome
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	ROME1 = 1
+)
+
+// ProtocolName is the official short name of the `rome` protocol used during
+// devp2p capability negotiation.
+const ProtocolName = "rome"
+
+// ProtocolVersions are the supported versions of the `rome` protocol (first
+// is primary).
+var ProtocolVersions = []uint{ROME1}
+
+// protocolLengths are the number of implemented message corresponding to
+// different protocol versions.
+var protocolLengths = map[uint]uint64{ROME1: 2}
+
+// maxMessageSize is the maximum cap on the size of a protocol message.
+const maxMessageSize = 10 * 1024 * 1024
+
+const (
+	GetRomeDataMsg = 0x00
+	RomeDataMsg    = 0x01
+)
+
+var (
+	errMsgTooLarge    = errors.New("message too long")
+	errDecode         = errors.New("invalid message")
+	errInvalidMsgCode = errors.New("invalid message code")
+)
+
+// Packet represents a p2p message in the `rome` protocol.
+type Packet interface {
+	Name() string // Name returns a string corresponding to the message type.
+	Kind() byte   // Kind returns the message type.
+}
+
+// GetRomeDataPacket represents a Rome metadata query for a batch of blocks.
+type GetRomeDataPacket struct {
+	ID     uint64        // Request ID to match up responses with
+	Hashes []common.Hash // Hashes of the blocks to retrieve the metadata of
+}
+
+// RomeDataPacket represents a Rome metadata query response. Blocks unknown to
+// the remote peer or without any Rome metadata are omitted.
+type RomeDataPacket struct {
+	ID     uint64           // ID of the request this is a response for
+	Blocks []*BlockRomeData // Metadata of the requested blocks
+}
+
+// BlockRomeData is the metadata needed to re-execute a Rome block to the same
+// state: the execution inputs supplied by the Rome sequencer, the Solana
+// context of its transactions and the Solana blockhashes visible to them.
+type BlockRomeData struct {
+	Hash        common.Hash         // Hash of the block the metadata belongs to
+	Data        types.RomeBlockData // Rome gas vectors and footprints
+	SolanaTxs   []SolanaTxData      // Solana context of the transactions that have one
+	Blockhashes []SolanaBlockhash   // Solana blockhashes in reach of BLOCKHASH
+}
+
+// SolanaTxData is the Solana slot and timestamp a transaction was executed at.
+type SolanaTxData struct {
+	Index     uint64 // Position of the transaction in the block
+	Slot      uint64 // Solana slot of the transaction
+	Timestamp uint64 // Solana timestamp of the transaction
+}
+
+// SolanaBlockhash is the blockhash of a Solana slot.
+type SolanaBlockhash struct {
+	Slot uint64
+	Hash common.Hash
+}
+
+func (*GetRomeDataPacket) Name() string { return "GetRomeData" }
+func (*GetRomeDataPacket) Kind() byte   { return GetRomeDataMsg }
+
+func (*RomeDataPacket) Name() string { return "RomeData" }
+func (*RomeDataPacket) Kind() byte   { return RomeDataMsg }
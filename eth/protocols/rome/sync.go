@@ -0,0 +1,371 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rome
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// maxRomeDataFetch is the number of blocks to request the metadata of in a
+	// single query.
+	maxRomeDataFetch = 64
+
+	// requestTimeout is the maximum time a peer is allowed to spend serving a
+	// single metadata request.
+	requestTimeout = 10 * time.Second
+)
+
+var (
+	// ErrCancelled is returned from Sync if the operation was prematurely
+	// terminated.
+	ErrCancelled = errors.New("sync cancelled")
+
+	errPeerDropped = errors.New("peer dropped")
+	errTimeout     = errors.New("request timed out")
+)
+
+// request tracks a pending Rome metadata request to a peer.
+type request struct {
+	peer    string                // Peer to which this request is assigned
+	deliver chan []*BlockRomeData // Channel to deliver the response on
+	drop    chan struct{}         // Channel closed if the peer disconnects
+}
+
+// Chain is the local chain the retrieved metadata is staged into. The staged
+// metadata is only persisted by the chain once the block it belongs to imports.
+type Chain interface {
+	// StageRomeInputs makes the Rome inputs of a block available to its import.
+	StageRomeInputs(hash common.Hash, inputs *core.RomeInputs) error
+
+	// UnstageRomeInputs discards the Rome inputs staged for a block, if they
+	// were not yet persisted by importing it.
+	UnstageRomeInputs(hash common.Hash)
+}
+
+// Syncer retrieves the Rome metadata of downloaded blocks from `rome` peers,
+// staging it in the local chain so that the blocks re-execute to the same state
+// as on the nodes that imported them through the engine API.
+type Syncer struct {
+	db    ethdb.KeyValueStore // Database to check for already known metadata
+	chain Chain               // Chain to stage the retrieved metadata into
+
+	peers  map[string]*Peer       // Currently active peers to fetch from
+	reqs   map[uint64]*request    // Pending requests by request ID
+	staged map[common.Hash]string // Peers the staged metadata was retrieved from, by block hash
+	lock   sync.RWMutex           // Protects the peer, request and staged sets
+}
+
+// NewSyncer creates a new Rome metadata syncer.
+func NewSyncer(db ethdb.KeyValueStore, chain Chain) *Syncer {
+	return &Syncer{
+		db:     db,
+		chain:  chain,
+		peers:  make(map[string]*Peer),
+		reqs:   make(map[uint64]*request),
+		staged: make(map[common.Hash]string),
+	}
+}
+
+// Register injects a new data source into the syncer's peerset.
+func (s *Syncer) Register(peer *Peer) error {
+	id := peer.ID()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.peers[id]; ok {
+		peer.Log().Error("Rome peer already registered", "id", id)
+		return errors.New("already registered")
+	}
+	s.peers[id] = peer
+	return nil
+}
+
+// Unregister removes a data source from the syncer's peerset, failing any of
+// its pending requests.
+func (s *Syncer) Unregister(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.peers[id]; !ok {
+		log.Error("Rome peer not registered", "id", id)
+		return errors.New("not registered")
+	}
+	delete(s.peers, id)
+
+	for reqid, req := range s.reqs {
+		if req.peer == id {
+			delete(s.reqs, reqid)
+			close(req.drop)
+		}
+	}
+	return nil
+}
+
+// Peer retrieves the registered peer with the given id.
+func (s *Syncer) Peer(id string) *Peer {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.peers[id]
+}
+
+// OnRomeData is a callback method to invoke when a batch of Rome metadata is
+// received from a remote peer.
+func (s *Syncer) OnRomeData(peer *Peer, id uint64, blocks []*BlockRomeData) error {
+	s.lock.Lock()
+	req, ok := s.reqs[id]
+	if ok && req.peer == peer.ID() {
+		delete(s.reqs, id)
+	}
+	s.lock.Unlock()
+
+	if !ok || req.peer != peer.ID() {
+		peer.Log().Warn("Unexpected Rome data packet", "reqid", id)
+		return nil
+	}
+	req.deliver <- blocks
+	return nil
+}
+
+// Sync retrieves the Rome metadata of the given blocks that carry transactions
+// but have none stored locally, staging it for their import. Peers are tried in
+// turn until every block is covered; blocks none of the peers have metadata for
+// are left as they are. Done must be called once the blocks were imported.
+func (s *Syncer) Sync(blocks []*types.Block, cancel <-chan struct{}) error {
+	var (
+		missing = make(map[common.Hash]*types.Block)
+		pending []common.Hash
+	)
+	for _, block := range blocks {
+		if len(block.Transactions()) == 0 {
+			continue
+		}
+		if len(rawdb.ReadRomeBlockDataRLP(s.db, block.Hash(), block.NumberU64())) > 0 {
+			continue
+		}
+		missing[block.Hash()] = block
+		pending = append(pending, block.Hash())
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	peers := s.peerIDs()
+	if len(peers) == 0 {
+		log.Debug("No Rome peers to fetch block metadata from", "blocks", len(pending))
+		return nil
+	}
+	for _, id := range peers {
+		var unanswered []common.Hash
+		for len(pending) > 0 {
+			batch := pending[:min(len(pending), maxRomeDataFetch)]
+			pending = pending[len(batch):]
+
+			res, err := s.fetch(id, batch, cancel)
+			if errors.Is(err, ErrCancelled) {
+				return err
+			}
+			var answered map[common.Hash]bool
+			if err == nil {
+				answered, err = s.process(id, res, batch, missing)
+			}
+			if err != nil {
+				log.Debug("Failed to retrieve Rome metadata", "peer", id, "err", err)
+				unanswered = append(append(unanswered, batch...), pending...)
+				pending = nil
+				break
+			}
+			for _, hash := range batch {
+				if !answered[hash] {
+					unanswered = append(unanswered, hash)
+				}
+			}
+		}
+		if pending = unanswered; len(pending) == 0 {
+			return nil
+		}
+	}
+	log.Warn("Rome metadata unavailable from peers", "blocks", len(pending), "first", missing[pending[0]].Number())
+	return nil
+}
+
+// peerIDs returns the identifiers of the registered peers in a stable order.
+func (s *Syncer) peerIDs() []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ids := make([]string, 0, len(s.peers))
+	for id := range s.peers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// fetch requests the metadata of a batch of blocks from a peer and waits for
+// the response.
+func (s *Syncer) fetch(id string, hashes []common.Hash, cancel <-chan struct{}) ([]*BlockRomeData, error) {
+	s.lock.Lock()
+	peer := s.peers[id]
+	if peer == nil {
+		s.lock.Unlock()
+		return nil, errPeerDropped
+	}
+	reqid := uint64(rand.Int63())
+	for _, ok := s.reqs[reqid]; ok; _, ok = s.reqs[reqid] {
+		reqid = uint64(rand.Int63())
+	}
+	req := &request{
+		peer:    id,
+		deliver: make(chan []*BlockRomeData, 1),
+		drop:    make(chan struct{}),
+	}
+	s.reqs[reqid] = req
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		delete(s.reqs, reqid)
+		s.lock.Unlock()
+	}()
+	if err := peer.RequestRomeData(reqid, hashes); err != nil {
+		return nil, err
+	}
+	timer := time.NewTimer(requestTimeout)
+	defer timer.Stop()
+
+	select {
+	case res := <-req.deliver:
+		return res, nil
+	case <-req.drop:
+		return nil, errPeerDropped
+	case <-timer.C:
+		return nil, errTimeout
+	case <-cancel:
+		return nil, ErrCancelled
+	}
+}
+
+// process validates a metadata response against the requested blocks and
+// stages it, returning the set of blocks the response covered. Nothing is
+// staged if any of the entries is invalid.
+func (s *Syncer) process(peer string, res []*BlockRomeData, requested []common.Hash, missing map[common.Hash]*types.Block) (map[common.Hash]bool, error) {
+	wanted := make(map[common.Hash]bool, len(requested))
+	for _, hash := range requested {
+		wanted[hash] = true
+	}
+	answered := make(map[common.Hash]bool, len(res))
+	for _, data := range res {
+		if !wanted[data.Hash] || answered[data.Hash] {
+			return nil, fmt.Errorf("unrequested block %x", data.Hash)
+		}
+		if err := data.verify(missing[data.Hash]); err != nil {
+			return nil, fmt.Errorf("invalid metadata for block %x: %w", data.Hash, err)
+		}
+		answered[data.Hash] = true
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for i, data := range res {
+		block := missing[data.Hash]
+		inputs := &core.RomeInputs{
+			Data:        types.NewRomeBlockData(data.Data.GasUsed, data.Data.GasPrice, data.Data.Footprints),
+			SolanaTxs:   make(map[common.Hash]core.SolanaTxContext, len(data.SolanaTxs)),
+			Blockhashes: make(map[uint64]common.Hash, len(data.Blockhashes)),
+		}
+		txs := block.Transactions()
+		for _, tx := range data.SolanaTxs {
+			inputs.SolanaTxs[txs[tx.Index].Hash()] = core.SolanaTxContext{Slot: tx.Slot, Timestamp: int64(tx.Timestamp)}
+		}
+		for _, blockhash := range data.Blockhashes {
+			inputs.Blockhashes[blockhash.Slot] = blockhash.Hash
+		}
+		if err := s.chain.StageRomeInputs(block.Hash(), inputs); err != nil {
+			for _, data := range res[:i] {
+				s.chain.UnstageRomeInputs(data.Hash)
+				delete(s.staged, data.Hash)
+			}
+			return nil, fmt.Errorf("invalid metadata for block %x: %w", data.Hash, err)
+		}
+		s.staged[block.Hash()] = peer
+	}
+	return answered, nil
+}
+
+// Done releases the metadata staged for a batch of synced blocks once the chain
+// finished importing them, with the index and error returned by the import. The
+// metadata of the imported blocks was persisted along with them, the rest is
+// discarded. If the import failed on a block executed with staged metadata, the
+// peer it was retrieved from is returned so that it can be dropped.
+func (s *Syncer) Done(blocks []*types.Block, index int, err error) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var bad string
+	if err != nil && index >= 0 && index < len(blocks) {
+		bad = s.staged[blocks[index].Hash()]
+	}
+	for _, block := range blocks {
+		if _, ok := s.staged[block.Hash()]; ok {
+			s.chain.UnstageRomeInputs(block.Hash())
+			delete(s.staged, block.Hash())
+		}
+	}
+	return bad
+}
+
+// verify checks that the metadata lines up with the transactions of a block.
+func (b *BlockRomeData) verify(block *types.Block) error {
+	n := len(block.Transactions())
+	if len(b.Data.GasUsed) != 0 && len(b.Data.GasUsed) != n {
+		return fmt.Errorf("gas used length mismatch: have %d, want %d", len(b.Data.GasUsed), n)
+	}
+	if len(b.Data.GasPrice) != len(b.Data.GasUsed) {
+		return fmt.Errorf("gas price length mismatch: have %d, want %d", len(b.Data.GasPrice), len(b.Data.GasUsed))
+	}
+	if len(b.Data.Footprints) != 0 && len(b.Data.Footprints) != n {
+		return fmt.Errorf("footprints length mismatch: have %d, want %d", len(b.Data.Footprints), n)
+	}
+	for i, tx := range b.SolanaTxs {
+		if tx.Index >= uint64(n) {
+			return fmt.Errorf("solana context for transaction %d out of %d", tx.Index, n)
+		}
+		if i > 0 && tx.Index <= b.SolanaTxs[i-1].Index {
+			return fmt.Errorf("solana context for transaction %d out of order", tx.Index)
+		}
+	}
+	for _, blockhash := range b.Blockhashes {
+		if blockhash.Hash == (common.Hash{}) {
+			return fmt.Errorf("empty blockhash for solana slot %d", blockhash.Slot)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rome
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// testBackend is a `rome` backend serving a chain and delivering responses to
+// a syncer.
+type testBackend struct {
+	chain  *core.BlockChain
+	syncer *Syncer
+}
+
+func (b *testBackend) Chain() *core.BlockChain                   { return b.chain }
+func (b *testBackend) RunPeer(peer *Peer, handler Handler) error { return handler(peer) }
+func (b *testBackend) PeerInfo(id enode.ID) interface{}          { return nil }
+func (b *testBackend) Handle(peer *Peer, packet Packet) error {
+	res := packet.(*RomeDataPacket)
+	return b.syncer.OnRomeData(peer, res.ID, res.Blocks)
+}
+
+// newTestServer creates a chain with a single Rome block on top of genesis,
+// returning the chain and the block.
+func newTestServer(t *testing.T) (*core.BlockChain, *types.Block) {
+	config := *params.TestChainConfig
+	config.Rome = &params.RomeConfig{BlockhashTime: new(uint64)}
+
+	db := rawdb.NewMemoryDatabase()
+	chain, err := core.NewBlockChain(db, nil, &core.Genesis{Config: &config}, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	txs := []*types.Transaction{
+		types.NewTx(&types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(1)}),
+		types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1)}),
+		types.NewTx(&types.LegacyTx{Nonce: 2, GasPrice: big.NewInt(1)}),
+	}
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1), ParentHash: chain.Genesis().Hash()}, txs, nil, nil, trie.NewStackTrie(nil))
+	rawdb.WriteBlock(db, block)
+	chain.WriteRomeBlockData(block.Hash(), block.NumberU64(), &types.RomeBlockData{
+		GasUsed:    []uint64{21000, 22000, 23000},
+		GasPrice:   []uint64{1, 2, 3},
+		Footprints: []string{"0x1", "0x2", "0x3"},
+	})
+	rawdb.WriteSolanaTxMetadata(db, txs[0].Hash(), 300, 1700000000)
+	rawdb.WriteSolanaTxMetadata(db, txs[2].Hash(), 301, 1700000001)
+	rawdb.WriteSolanaBlockhash(db, 43, common.Hash{0x43}) // out of reach
+	rawdb.WriteSolanaBlockhash(db, 44, common.Hash{0x44})
+	rawdb.WriteSolanaBlockhash(db, 300, common.Hash{0x03})
+	rawdb.WriteSolanaBlockhash(db, 301, common.Hash{0x01}) // not yet visible
+
+	return chain, block
+}
+
+func TestServiceGetRomeData(t *testing.T) {
+	chain, block := newTestServer(t)
+
+	res := ServiceGetRomeDataQuery(chain, &GetRomeDataPacket{
+		Hashes: []common.Hash{{0x01}, chain.Genesis().Hash(), block.Hash()},
+	})
+	if len(res) != 1 {
+		t.Fatalf("unexpected number of results: have %d, want 1", len(res))
+	}
+	want := &BlockRomeData{
+		Hash: block.Hash(),
+		Data: *chain.GetRomeBlockData(block.Hash(), block.NumberU64()),
+		SolanaTxs: []SolanaTxData{
+			{Index: 0, Slot: 300, Timestamp: 1700000000},
+			{Index: 2, Slot: 301, Timestamp: 1700000001},
+		},
+		Blockhashes: []SolanaBlockhash{
+			{Slot: 44, Hash: common.Hash{0x44}},
+			{Slot: 300, Hash: common.Hash{0x03}},
+		},
+	}
+	if !reflect.DeepEqual(res[0], want) {
+		t.Fatalf("metadata mismatch:\nhave %+v\nwant %+v", res[0], want)
+	}
+}
+
+// newTestClient creates an empty chain with the same genesis as the serving one,
+// and a syncer staging into it, connected to the serving chain.
+func newTestClient(t *testing.T, server *core.BlockChain) (*core.BlockChain, *Syncer, *Peer) {
+	db := rawdb.NewMemoryDatabase()
+	chain, err := core.NewBlockChain(db, nil, &core.Genesis{Config: server.Config()}, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	// Connect a syncer to the serving chain over a message pipe
+	var (
+		syncer   = NewSyncer(db, chain)
+		app, net = p2p.MsgPipe()
+		client   = NewFakePeer(ROME1, "server-peer", app)
+		peer     = NewFakePeer(ROME1, "client-peer", net)
+		backend  = &testBackend{chain: server, syncer: syncer}
+	)
+	t.Cleanup(func() {
+		app.Close()
+		net.Close()
+		chain.Stop()
+	})
+	go Handle(backend, peer)
+	go Handle(backend, client)
+
+	if err := syncer.Register(client); err != nil {
+		t.Fatalf("failed to register peer: %v", err)
+	}
+	return chain, syncer, client
+}
+
+func TestSync(t *testing.T) {
+	server, block := newTestServer(t)
+	chain, syncer, peer := newTestClient(t, server)
+
+	empty := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2), ParentHash: block.Hash()})
+	if err := syncer.Sync([]*types.Block{block, empty}, nil); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	// The metadata is staged for the import, but not persisted yet
+	db := chain.StateCache().DiskDB()
+	if data := rawdb.ReadRomeBlockData(db, block.Hash(), block.NumberU64()); data != nil {
+		t.Fatalf("rome data persisted before import: %+v", data)
+	}
+	if have, want := chain.GetRomeBlockData(block.Hash(), block.NumberU64()), server.GetRomeBlockData(block.Hash(), block.NumberU64()); !reflect.DeepEqual(have, want) {
+		t.Fatalf("rome data mismatch: have %+v, want %+v", have, want)
+	}
+	for i, tx := range block.Transactions() {
+		wantSlot, wantTimestamp, wantOk := server.GetSolanaTxMetadata(tx.Hash())
+		slot, timestamp, ok := chain.GetSolanaTxMetadata(tx.Hash())
+		if slot != wantSlot || timestamp != wantTimestamp || ok != wantOk {
+			t.Errorf("tx %d: solana metadata mismatch: have %d/%d/%v, want %d/%d/%v", i, slot, timestamp, ok, wantSlot, wantTimestamp, wantOk)
+		}
+	}
+	for slot, want := range map[uint64]common.Hash{43: {}, 44: {0x44}, 300: {0x03}, 301: {}} {
+		if have := chain.GetSolanaBlockhash(slot); have != want {
+			t.Errorf("slot %d: blockhash mismatch: have %x, want %x", slot, have, want)
+		}
+	}
+	// A failed import discards the staged metadata and reports its peer
+	if bad := syncer.Done([]*types.Block{block, empty}, 0, errors.New("invalid merkle root")); bad != peer.ID() {
+		t.Fatalf("bad peer mismatch: have %q, want %q", bad, peer.ID())
+	}
+	if data := chain.GetRomeBlockData(block.Hash(), block.NumberU64()); data != nil {
+		t.Fatalf("rome data not discarded: %+v", data)
+	}
+	if hash := chain.GetSolanaBlockhash(44); hash != (common.Hash{}) {
+		t.Fatalf("blockhash not discarded: %x", hash)
+	}
+	// A dropped peer leaves the blocks as they are
+	syncer.Unregister(peer.ID())
+	if err := syncer.Sync([]*types.Block{block}, nil); err != nil {
+		t.Fatalf("resync failed: %v", err)
+	}
+}
+
+// Tests that metadata contradicting the known Solana blockhashes is rejected.
+func TestSyncBlockhashConflict(t *testing.T) {
+	server, block := newTestServer(t)
+	chain, syncer, _ := newTestClient(t, server)
+
+	db := chain.StateCache().DiskDB()
+	rawdb.WriteSolanaBlockhash(db, 44, common.Hash{0xff})
+
+	if err := syncer.Sync([]*types.Block{block}, nil); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if data := chain.GetRomeBlockData(block.Hash(), block.NumberU64()); data != nil {
+		t.Fatalf("conflicting rome data staged: %+v", data)
+	}
+	if hash := chain.GetSolanaBlockhash(44); hash != (common.Hash{0xff}) {
+		t.Fatalf("known blockhash overwritten: %x", hash)
+	}
+}
+
+func TestVerifyRomeData(t *testing.T) {
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{
+		types.NewTx(&types.LegacyTx{Nonce: 0}),
+		types.NewTx(&types.LegacyTx{Nonce: 1}),
+	}, nil, nil, trie.NewStackTrie(nil))
+
+	tests := []struct {
+		data  BlockRomeData
+		valid bool
+	}{
+		{BlockRomeData{}, true},
+		{BlockRomeData{Data: types.RomeBlockData{GasUsed: []uint64{1, 2}, GasPrice: []uint64{1, 2}}}, true},
+		{BlockRomeData{Data: types.RomeBlockData{GasUsed: []uint64{1}, GasPrice: []uint64{1}}}, false},
+		{BlockRomeData{Data: types.RomeBlockData{GasUsed: []uint64{1, 2}, GasPrice: []uint64{1}}}, false},
+		{BlockRomeData{Data: types.RomeBlockData{Footprints: []string{"0x0"}}}, false},
+		{BlockRomeData{SolanaTxs: []SolanaTxData{{Index: 1}, {Index: 0}}}, false},
+		{BlockRomeData{SolanaTxs: []SolanaTxData{{Index: 2}}}, false},
+		{BlockRomeData{Blockhashes: []SolanaBlockhash{{Slot: 1}}}, false},
+	}
+	for i, tt := range tests {
+		if err := tt.data.verify(block); (err == nil) != tt.valid {
+			t.Errorf("test %d: validity mismatch: have %v, want valid %v", i, err, tt.valid)
+		}
+	}
+}
@@ -145,7 +145,8 @@ func (eth *Ethereum) hashState(ctx context.Context, block *types.Block, reexec u
 		if current = eth.blockchain.GetBlockByNumber(next); current == nil {
 			return nil, nil, fmt.Errorf("block #%d not found", next)
 		}
-		_, _, _, err := eth.blockchain.Processor().Process(current, statedb, vm.Config{}, make([]uint64, 0), make([]uint64, 0), make([]string, 0))
+		// Rome execution inputs are resolved from the database by the processor
+		_, _, _, err := eth.blockchain.Processor().Process(context.Background(), current, statedb, vm.Config{}, nil, nil, nil)
 		if err != nil {
 			return nil, nil, fmt.Errorf("processing block %d failed: %v", current.NumberU64(), err)
 		}
@@ -180,10 +181,16 @@ func (eth *Ethereum) pathState(block *types.Block) (*state.StateDB, func(), erro
 	if err == nil {
 		return statedb, noopReleaser, nil
 	}
-	// TODO historic state is not supported in path-based scheme.
-	// Fully archive node in pbss will be implemented by relying
-	// on state history, but needs more work on top.
-	return nil, nil, errors.New("historical state not available in path scheme yet")
+	// Otherwise serve the historical state from the state histories, if
+	// the node is configured as an archive node.
+	if !eth.config.StateHistoryArchive {
+		return nil, nil, errors.New("historical state not available in path scheme without state history archive")
+	}
+	statedb, err = eth.blockchain.HistoricState(block.Root(), eth.config.StateHistoryReadLimit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("historical state unavailable: %w", err)
+	}
+	return statedb, noopReleaser, nil
 }
 
 // stateAtBlock retrieves the state database associated with a certain block.
@@ -236,7 +243,10 @@ func (eth *Ethereum) stateAtTransaction(ctx context.Context, block *types.Block,
 		return nil, vm.BlockContext{}, statedb, release, nil
 	}
 	// Recompute transactions up to the target index.
-	signer := types.MakeSigner(eth.blockchain.Config(), block.Number(), block.Time())
+	var (
+		signer   = types.MakeSigner(eth.blockchain.Config(), block.Number(), block.Time())
+		romeData = eth.blockchain.GetRomeBlockData(block.Hash(), block.NumberU64())
+	)
 	for idx, tx := range block.Transactions() {
 		// Assemble the transaction call message and return if the requested offset
 		msg, _ := core.TransactionToMessage(tx, signer, block.BaseFee())
@@ -246,9 +256,11 @@ func (eth *Ethereum) stateAtTransaction(ctx context.Context, block *types.Block,
 			return msg, context, statedb, release, nil
 		}
 		// Not yet the searched for transaction, execute on top of the current state
+		core.ReadSolanaTxContext(eth.chainDb, tx.Hash(), &txContext)
 		vmenv := vm.NewEVM(context, txContext, statedb, eth.blockchain.Config(), vm.Config{})
 		statedb.SetTxContext(tx.Hash(), idx)
-		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(tx.Gas()), 0, 0); err != nil {
+		romeGasUsed, romeGasPrice := romeData.TxGas(idx)
+		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(max(tx.Gas(), romeGasUsed)), romeGasUsed, romeGasPrice); err != nil {
 			return nil, vm.BlockContext{}, nil, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
 		}
 		// Ensure any modifications are committed to the state
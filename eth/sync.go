@@ -38,6 +38,9 @@ func (h *handler) syncTransactions(p *eth.Peer) {
 	var hashes []common.Hash
 	for _, batch := range h.txpool.Pending(false) {
 		for _, tx := range batch {
+			if tx.Tx != nil && tx.Tx.Private() {
+				continue // withheld from the network
+			}
 			hashes = append(hashes, tx.Hash)
 		}
 	}
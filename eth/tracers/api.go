@@ -105,6 +105,12 @@ func (api *API) chainContext(ctx context.Context) core.ChainContext {
 	return ethapi.NewChainContext(ctx, api.backend)
 }
 
+// romeBlockData retrieves the Rome execution inputs the block was originally
+// processed with, so re-executed transactions are charged identically.
+func (api *API) romeBlockData(block *types.Block) *types.RomeBlockData {
+	return rawdb.ReadRomeBlockData(api.backend.ChainDb(), block.Hash(), block.NumberU64())
+}
+
 // blockByNumber is the wrapper of the chain access function offered by the backend.
 // It will return an error if the block is not found.
 func (api *API) blockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
@@ -268,6 +274,7 @@ func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed
 				var (
 					signer   = types.MakeSigner(api.backend.ChainConfig(), task.block.Number(), task.block.Time())
 					blockCtx = core.NewEVMBlockContext(task.block.Header(), api.chainContext(ctx), nil, api.backend.ChainConfig(), task.statedb)
+					romeData = api.romeBlockData(task.block)
 				)
 				// Trace all the transactions contained within
 				for i, tx := range task.block.Transactions() {
@@ -278,7 +285,8 @@ func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed
 						TxIndex:     i,
 						TxHash:      tx.Hash(),
 					}
-					res, err := api.traceTx(ctx, msg, txctx, blockCtx, task.statedb, config)
+					romeGasUsed, romeGasPrice := romeData.TxGas(i)
+					res, err := api.traceTx(ctx, msg, txctx, blockCtx, task.statedb, config, romeGasUsed, romeGasPrice)
 					if err != nil {
 						task.results[i] = &txTraceResult{TxHash: tx.Hash(), Error: err.Error()}
 						log.Warn("Tracing failed", "hash", tx.Hash(), "block", task.block.NumberU64(), "err", err)
@@ -555,6 +563,7 @@ func (api *API) IntermediateRoots(ctx context.Context, hash common.Hash, config
 		chainConfig        = api.backend.ChainConfig()
 		vmctx              = core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil, chainConfig, statedb)
 		deleteEmptyObjects = chainConfig.IsEIP158(block.Number())
+		romeData           = api.romeBlockData(block)
 	)
 	for i, tx := range block.Transactions() {
 		if err := ctx.Err(); err != nil {
@@ -563,10 +572,12 @@ func (api *API) IntermediateRoots(ctx context.Context, hash common.Hash, config
 		var (
 			msg, _    = core.TransactionToMessage(tx, signer, block.BaseFee())
 			txContext = core.NewEVMTxContext(msg)
-			vmenv     = vm.NewEVM(vmctx, txContext, statedb, chainConfig, vm.Config{})
 		)
+		core.ReadSolanaTxContext(api.backend.ChainDb(), tx.Hash(), &txContext)
+		vmenv := vm.NewEVM(vmctx, txContext, statedb, chainConfig, vm.Config{})
 		statedb.SetTxContext(tx.Hash(), i)
-		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit), 0, 0); err != nil {
+		romeGasUsed, romeGasPrice := romeData.TxGas(i)
+		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(max(msg.GasLimit, romeGasUsed)), romeGasUsed, romeGasPrice); err != nil {
 			log.Warn("Tracing intermediate roots did not complete", "txindex", i, "txhash", tx.Hash(), "err", err)
 			// We intentionally don't return the error here: if we do, then the RPC server will not
 			// return the roots. Most likely, the caller already knows that a certain transaction fails to
@@ -632,6 +643,7 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 		blockCtx  = core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil, api.backend.ChainConfig(), statedb)
 		signer    = types.MakeSigner(api.backend.ChainConfig(), block.Number(), block.Time())
 		results   = make([]*txTraceResult, len(txs))
+		romeData  = api.romeBlockData(block)
 	)
 	for i, tx := range txs {
 		// Generate the next state snapshot fast without tracing
@@ -642,7 +654,8 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 			TxIndex:     i,
 			TxHash:      tx.Hash(),
 		}
-		res, err := api.traceTx(ctx, msg, txctx, blockCtx, statedb, config)
+		romeGasUsed, romeGasPrice := romeData.TxGas(i)
+		res, err := api.traceTx(ctx, msg, txctx, blockCtx, statedb, config, romeGasUsed, romeGasPrice)
 		if err != nil {
 			return nil, err
 		}
@@ -664,6 +677,7 @@ func (api *API) traceBlockParallel(ctx context.Context, block *types.Block, stat
 		blockHash = block.Hash()
 		signer    = types.MakeSigner(api.backend.ChainConfig(), block.Number(), block.Time())
 		results   = make([]*txTraceResult, len(txs))
+		romeData  = api.romeBlockData(block)
 		pend      sync.WaitGroup
 	)
 	threads := runtime.NumCPU()
@@ -685,7 +699,8 @@ func (api *API) traceBlockParallel(ctx context.Context, block *types.Block, stat
 					TxIndex:     task.index,
 					TxHash:      txs[task.index].Hash(),
 				}
-				res, err := api.traceTx(ctx, msg, txctx, blockCtx, task.statedb, config)
+				romeGasUsed, romeGasPrice := romeData.TxGas(task.index)
+				res, err := api.traceTx(ctx, msg, txctx, blockCtx, task.statedb, config, romeGasUsed, romeGasPrice)
 				if err != nil {
 					results[task.index] = &txTraceResult{TxHash: txs[task.index].Hash(), Error: err.Error()}
 					continue
@@ -712,8 +727,11 @@ txloop:
 		// Generate the next state snapshot fast without tracing
 		msg, _ := core.TransactionToMessage(tx, signer, block.BaseFee())
 		statedb.SetTxContext(tx.Hash(), i)
-		vmenv := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), statedb, api.backend.ChainConfig(), vm.Config{})
-		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit), 0, 0); err != nil {
+		txContext := core.NewEVMTxContext(msg)
+		core.ReadSolanaTxContext(api.backend.ChainDb(), tx.Hash(), &txContext)
+		vmenv := vm.NewEVM(blockCtx, txContext, statedb, api.backend.ChainConfig(), vm.Config{})
+		romeGasUsed, romeGasPrice := romeData.TxGas(i)
+		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(max(msg.GasLimit, romeGasUsed)), romeGasUsed, romeGasPrice); err != nil {
 			failed = err
 			break txloop
 		}
@@ -777,6 +795,7 @@ func (api *API) standardTraceBlockToFile(ctx context.Context, block *types.Block
 		chainConfig = api.backend.ChainConfig()
 		vmctx       = core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil, chainConfig, statedb)
 		canon       = true
+		romeData    = api.romeBlockData(block)
 	)
 	// Check if there are any overrides: the caller may wish to enable a future
 	// fork when executing this block. Note, such overrides are only applicable to the
@@ -818,9 +837,11 @@ func (api *API) standardTraceBlockToFile(ctx context.Context, block *types.Block
 			}
 		}
 		// Execute the transaction and flush any traces to disk
+		core.ReadSolanaTxContext(api.backend.ChainDb(), tx.Hash(), &txContext)
 		vmenv := vm.NewEVM(vmctx, txContext, statedb, chainConfig, vmConf)
 		statedb.SetTxContext(tx.Hash(), i)
-		_, err = core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit), 0, 0)
+		romeGasUsed, romeGasPrice := romeData.TxGas(i)
+		_, err = core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(max(msg.GasLimit, romeGasUsed)), romeGasUsed, romeGasPrice)
 		if writer != nil {
 			writer.Flush()
 		}
@@ -900,7 +921,8 @@ func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *
 		TxIndex:     int(index),
 		TxHash:      hash,
 	}
-	return api.traceTx(ctx, msg, txctx, vmctx, statedb, config)
+	romeGasUsed, romeGasPrice := api.romeBlockData(block).TxGas(int(index))
+	return api.traceTx(ctx, msg, txctx, vmctx, statedb, config, romeGasUsed, romeGasPrice)
 }
 
 // TraceCall lets you trace a given eth_call. It collects the structured logs
@@ -957,36 +979,54 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 	}
 	defer release()
 
-	vmctx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil, api.backend.ChainConfig(), statedb)
+	var (
+		vmctx     = core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil, api.backend.ChainConfig(), statedb)
+		solanaCtx vm.TxContext
+	)
+	// Simulate the call in the latest Solana context known at the block
+	core.ReadSolanaBlockContext(api.backend.ChainDb(), block.Header(), &solanaCtx)
+
 	// Apply the customization rules if required.
 	if config != nil {
 		if err := config.StateOverrides.Apply(statedb); err != nil {
 			return nil, err
 		}
 		config.BlockOverrides.Apply(&vmctx)
+		config.BlockOverrides.ApplySolana(&solanaCtx)
 	}
 	// Execute the trace
 	msg, err := args.ToMessage(api.backend.RPCGasCap(), block.BaseFee())
 	if err != nil {
 		return nil, err
 	}
+	txContext := core.NewEVMTxContext(msg)
+	txContext.SolanaBlockNumber, txContext.SolanaTimestamp = solanaCtx.SolanaBlockNumber, solanaCtx.SolanaTimestamp
 
 	var traceConfig *TraceConfig
 	if config != nil {
 		traceConfig = &config.TraceConfig
 	}
-	return api.traceTx(ctx, msg, new(Context), vmctx, statedb, traceConfig)
+	return api.traceTxWithContext(ctx, msg, txContext, new(Context), vmctx, statedb, traceConfig, 0, 0)
 }
 
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
-func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
+func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig, romeGasUsed uint64, romeGasPrice uint64) (interface{}, error) {
+	txContext := core.NewEVMTxContext(message)
+	if txctx.TxHash != (common.Hash{}) {
+		core.ReadSolanaTxContext(api.backend.ChainDb(), txctx.TxHash, &txContext)
+	}
+	return api.traceTxWithContext(ctx, message, txContext, txctx, vmctx, statedb, config, romeGasUsed, romeGasPrice)
+}
+
+// traceTxWithContext is like traceTx, but executes the message in the provided
+// transaction context.
+func (api *API) traceTxWithContext(ctx context.Context, message *core.Message, txContext vm.TxContext, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig, romeGasUsed uint64, romeGasPrice uint64) (interface{}, error) {
 	var (
-		tracer    Tracer
-		err       error
-		timeout   = defaultTraceTimeout
-		txContext = core.NewEVMTxContext(message)
+		tracer  Tracer
+		err     error
+		timeout = defaultTraceTimeout
 	)
 	if config == nil {
 		config = &TraceConfig{}
@@ -1020,7 +1060,7 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 
 	// Call Prepare to clear out the statedb access list
 	statedb.SetTxContext(txctx.TxHash, txctx.TxIndex)
-	if _, err = core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.GasLimit), 0, 0); err != nil {
+	if _, err = core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(max(message.GasLimit, romeGasUsed)), romeGasUsed, romeGasPrice); err != nil {
 		return nil, fmt.Errorf("tracing failed: %w", err)
 	}
 	return tracer.GetResult()
@@ -277,6 +277,23 @@ func (ec *Client) TransactionSender(ctx context.Context, tx *types.Transaction,
 	return meta.From, nil
 }
 
+// TransactionSolanaContext returns the Solana slot and slot timestamp the given
+// mined transaction was executed in. ethereum.NotFound is returned if the node
+// does not know the Solana context of the transaction.
+func (ec *Client) TransactionSolanaContext(ctx context.Context, txHash common.Hash) (slot uint64, timestamp uint64, err error) {
+	var meta *struct {
+		SolanaSlot      *hexutil.Uint64 `json:"solanaSlot"`
+		SolanaTimestamp *hexutil.Uint64 `json:"solanaTimestamp"`
+	}
+	if err = ec.c.CallContext(ctx, &meta, "eth_getTransactionByHash", txHash); err != nil {
+		return 0, 0, err
+	}
+	if meta == nil || meta.SolanaSlot == nil || meta.SolanaTimestamp == nil {
+		return 0, 0, ethereum.NotFound
+	}
+	return uint64(*meta.SolanaSlot), uint64(*meta.SolanaTimestamp), nil
+}
+
 // TransactionCount returns the total number of transactions in the given block.
 func (ec *Client) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
 	var num hexutil.Uint
@@ -469,6 +486,12 @@ func toFilterArg(q ethereum.FilterQuery) (interface{}, error) {
 		}
 		arg["toBlock"] = toBlockNumArg(q.ToBlock)
 	}
+	if q.FromSolanaSlot != nil {
+		arg["fromSolanaSlot"] = hexutil.Uint64(*q.FromSolanaSlot)
+	}
+	if q.ToSolanaSlot != nil {
+		arg["toSolanaSlot"] = hexutil.Uint64(*q.ToSolanaSlot)
+	}
 	return arg, nil
 }
 
@@ -305,6 +305,11 @@ type BlockOverrides struct {
 	Random common.Hash
 	// BaseFee overrides the block base fee.
 	BaseFee *big.Int
+	// SolanaSlot overrides the Solana slot which feeds into the NUMBER opcode.
+	SolanaSlot *uint64
+	// SolanaTimestamp overrides the Solana timestamp which feeds into the
+	// TIMESTAMP opcode.
+	SolanaTimestamp *uint64
 }
 
 func (o BlockOverrides) MarshalJSON() ([]byte, error) {
@@ -316,14 +321,19 @@ func (o BlockOverrides) MarshalJSON() ([]byte, error) {
 		Coinbase   *common.Address `json:"coinbase,omitempty"`
 		Random     *common.Hash    `json:"random,omitempty"`
 		BaseFee    *hexutil.Big    `json:"baseFee,omitempty"`
+
+		SolanaSlot      *hexutil.Uint64 `json:"solanaSlot,omitempty"`
+		SolanaTimestamp *hexutil.Uint64 `json:"solanaTimestamp,omitempty"`
 	}
 
 	output := override{
-		Number:     (*hexutil.Big)(o.Number),
-		Difficulty: (*hexutil.Big)(o.Difficulty),
-		Time:       hexutil.Uint64(o.Time),
-		GasLimit:   hexutil.Uint64(o.GasLimit),
-		BaseFee:    (*hexutil.Big)(o.BaseFee),
+		Number:          (*hexutil.Big)(o.Number),
+		Difficulty:      (*hexutil.Big)(o.Difficulty),
+		Time:            hexutil.Uint64(o.Time),
+		GasLimit:        hexutil.Uint64(o.GasLimit),
+		BaseFee:         (*hexutil.Big)(o.BaseFee),
+		SolanaSlot:      (*hexutil.Uint64)(o.SolanaSlot),
+		SolanaTimestamp: (*hexutil.Uint64)(o.SolanaTimestamp),
 	}
 	if o.Coinbase != (common.Address{}) {
 		output.Coinbase = &o.Coinbase
@@ -522,6 +522,13 @@ func TestBlockOverridesMarshal(t *testing.T) {
 			},
 			want: `{"number":"0x1","difficulty":"0x2","time":"0x3","gasLimit":"0x4","baseFee":"0x5"}`,
 		},
+		{
+			bo: BlockOverrides{
+				SolanaSlot:      func() *uint64 { v := uint64(6); return &v }(),
+				SolanaTimestamp: func() *uint64 { v := uint64(7); return &v }(),
+			},
+			want: `{"solanaSlot":"0x6","solanaTimestamp":"0x7"}`,
+		},
 	} {
 		marshalled, err := json.Marshal(&tt.bo)
 		if err != nil {
@@ -32,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/filters"
@@ -641,6 +642,34 @@ func (t *Transaction) RawReceipt(ctx context.Context) (hexutil.Bytes, error) {
 	return receipt.MarshalBinary()
 }
 
+// solanaMetadata returns the Solana slot and timestamp a mined transaction was
+// executed at, if known.
+func (t *Transaction) solanaMetadata(ctx context.Context) (uint64, int64, bool) {
+	_, block := t.resolve(ctx)
+	if block == nil {
+		return 0, 0, false
+	}
+	return rawdb.ReadSolanaTxMetadata(t.r.backend.ChainDb(), t.hash)
+}
+
+func (t *Transaction) SolanaSlot(ctx context.Context) *hexutil.Uint64 {
+	slot, _, ok := t.solanaMetadata(ctx)
+	if !ok {
+		return nil
+	}
+	ret := hexutil.Uint64(slot)
+	return &ret
+}
+
+func (t *Transaction) SolanaTimestamp(ctx context.Context) *hexutil.Uint64 {
+	_, timestamp, ok := t.solanaMetadata(ctx)
+	if !ok {
+		return nil
+	}
+	ret := hexutil.Uint64(timestamp)
+	return &ret
+}
+
 type BlockType int
 
 // Block represents an Ethereum block.
@@ -1092,6 +1121,51 @@ func (b *Block) ExcessBlobGas(ctx context.Context) (*hexutil.Uint64, error) {
 	return &ret, nil
 }
 
+// solanaMetadata returns the latest Solana slot and timestamp among the
+// transactions of the block, if any of them are known.
+func (b *Block) solanaMetadata(ctx context.Context) (uint64, int64, bool, error) {
+	block, err := b.resolve(ctx)
+	if err != nil || block == nil {
+		return 0, 0, false, err
+	}
+	var (
+		slot, timestamp = uint64(0), int64(0)
+		found           bool
+	)
+	for _, tx := range block.Transactions() {
+		txSlot, txTimestamp, ok := rawdb.ReadSolanaTxMetadata(b.r.backend.ChainDb(), tx.Hash())
+		if !ok {
+			continue
+		}
+		if !found || txSlot > slot {
+			slot = txSlot
+		}
+		if !found || txTimestamp > timestamp {
+			timestamp = txTimestamp
+		}
+		found = true
+	}
+	return slot, timestamp, found, nil
+}
+
+func (b *Block) SolanaSlot(ctx context.Context) (*hexutil.Uint64, error) {
+	slot, _, ok, err := b.solanaMetadata(ctx)
+	if err != nil || !ok {
+		return nil, err
+	}
+	ret := hexutil.Uint64(slot)
+	return &ret, nil
+}
+
+func (b *Block) SolanaTimestamp(ctx context.Context) (*hexutil.Uint64, error) {
+	_, timestamp, ok, err := b.solanaMetadata(ctx)
+	if err != nil || !ok {
+		return nil, err
+	}
+	ret := hexutil.Uint64(timestamp)
+	return &ret, nil
+}
+
 // BlockFilterCriteria encapsulates criteria passed to a `logs` accessor inside
 // a block.
 type BlockFilterCriteria struct {
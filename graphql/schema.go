@@ -170,6 +170,13 @@ const schema string = `
         rawReceipt: Bytes!
         # BlobVersionedHashes is a set of hash outputs from the blobs in the transaction.
         blobVersionedHashes: [Bytes32!]
+        # SolanaSlot is the Solana slot the transaction was executed in. This
+        # will be null if the transaction is pending or the slot is unknown.
+        solanaSlot: Long
+        # SolanaTimestamp is the unix timestamp of the Solana slot the
+        # transaction was executed in. This will be null if the transaction is
+        # pending or the slot is unknown.
+        solanaTimestamp: Long
     }
 
     # BlockFilterCriteria encapsulates log filter criteria for a filter applied
@@ -279,6 +286,12 @@ const schema string = `
         blobGasUsed: Long
         # ExcessBlobGas is a running total of blob gas consumed in excess of the target, prior to the block.
         excessBlobGas: Long
+        # SolanaSlot is the latest Solana slot among the transactions of this
+        # block. If it is unknown, this field will be null.
+        solanaSlot: Long
+        # SolanaTimestamp is the latest Solana slot timestamp among the
+        # transactions of this block. If it is unknown, this field will be null.
+        solanaTimestamp: Long
     }
 
     # CallData represents the data associated with a local contract call.
@@ -157,6 +157,9 @@ type FilterQuery struct {
 	ToBlock   *big.Int         // end of the range, nil means latest block
 	Addresses []common.Address // restricts matches to events created by specific contracts
 
+	FromSolanaSlot *uint64 // used by eth_getLogs, restricts matches to transactions executed from this Solana slot on
+	ToSolanaSlot   *uint64 // used by eth_getLogs, restricts matches to transactions executed up to this Solana slot
+
 	// The Topic list restricts matches to particular event topics. Each event has a list
 	// of topics. Topics matches a prefix of that list. An empty element slice matches any
 	// topic. Non-empty elements represent an alternative that matches any of the
@@ -22,7 +22,6 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"os"
 	"strings"
 	"time"
 
@@ -43,12 +42,14 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/footprint"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/gasestimator"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/params"
@@ -71,35 +72,28 @@ func NewEthereumAPI(b Backend) *EthereumAPI {
 	return &EthereumAPI{b}
 }
 
-// GasPrice returns a suggestion for a gas price for legacy transactions.
+// GasPrice returns a suggestion for a gas price for legacy transactions. The
+// price is quoted by the Rome gasometer, or suggested by the local gas price
+// oracle if the gasometer is unavailable and fallbacks are enabled.
 func (s *EthereumAPI) GasPrice(ctx context.Context) (*hexutil.Big, error) {
-	price, err := fetchRomeGasPrice(ctx)
-	if err != nil {
-		log.Error("rpc_GasPrice: fetchRomeGasPrice failed", "err", err)
-		return nil, err
-	}
-	return price, nil
-}
-
-func fetchRomeGasPrice(ctx context.Context) (*hexutil.Big, error) {
-	gasometerUrl := os.Getenv("ROME_GASOMETER_URL")
-	if gasometerUrl == "" {
-		return nil, fmt.Errorf("ROME_GASOMETER_URL ennvar is not set")
+	gasometer := romeGasometer(s.b)
+	price, err := gasometer.GasPrice(ctx)
+	if err == nil {
+		return (*hexutil.Big)(price), nil
 	}
-	client, err := rpc.Dial(gasometerUrl)
-	if err != nil {
-		log.Error("Failed to connect to the Ethereum client: %v", err)
-		return nil, fmt.Errorf("failed to connect to gasometer: %w", err)
+	if !gasometer.Fallback(err) {
+		log.Error("rpc_GasPrice: gasometer request failed", "err", err)
+		return nil, err
 	}
-	defer client.Close()
-
-	var estimatedGas hexutil.Big
-	err = client.CallContext(ctx, &estimatedGas, "eth_gasPrice")
+	log.Warn("Gasometer unavailable, suggesting local gas price", "err", err)
+	tipcap, err := s.b.SuggestGasTipCap(ctx)
 	if err != nil {
 		return nil, err
 	}
-
-	return &estimatedGas, nil
+	if head := s.b.CurrentHeader(); head.BaseFee != nil {
+		tipcap.Add(tipcap, head.BaseFee)
+	}
+	return (*hexutil.Big)(tipcap), nil
 }
 
 // MaxPriorityFeePerGas returns a suggestion for a gas tip cap for dynamic fee transactions.
@@ -1036,6 +1030,7 @@ func (s *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rpc.
 	result := make([]map[string]interface{}, len(receipts))
 	for i, receipt := range receipts {
 		result[i] = marshalReceipt(receipt, block.Hash(), block.NumberU64(), signer, txs[i], i, s.b.ChainConfig())
+		setReceiptSolanaMetadata(s.b, result[i], txs[i].Hash())
 	}
 
 	return result, nil
@@ -1107,6 +1102,11 @@ type BlockOverrides struct {
 	Random      *common.Hash
 	BaseFee     *hexutil.Big
 	BlobBaseFee *hexutil.Big
+
+	// Solana context the call is simulated in, defaulting to the latest known
+	// at the block
+	SolanaSlot      *hexutil.Uint64
+	SolanaTimestamp *hexutil.Uint64
 }
 
 // Apply overrides the given header fields into the given block context.
@@ -1140,10 +1140,27 @@ func (diff *BlockOverrides) Apply(blockCtx *vm.BlockContext) {
 	}
 }
 
+// ApplySolana overrides the Solana slot and timestamp of the given transaction
+// context.
+func (diff *BlockOverrides) ApplySolana(txCtx *vm.TxContext) {
+	if diff == nil {
+		return
+	}
+	if diff.SolanaSlot != nil {
+		slot := uint64(*diff.SolanaSlot)
+		txCtx.SolanaBlockNumber = &slot
+	}
+	if diff.SolanaTimestamp != nil {
+		timestamp := int64(*diff.SolanaTimestamp)
+		txCtx.SolanaTimestamp = &timestamp
+	}
+}
+
 // ChainContextBackend provides methods required to implement ChainContext.
 type ChainContextBackend interface {
 	Engine() consensus.Engine
 	HeaderByNumber(context.Context, rpc.BlockNumber) (*types.Header, error)
+	ChainDb() ethdb.Database
 }
 
 // ChainContext is an implementation of core.ChainContext. It's main use-case
@@ -1176,6 +1193,10 @@ func (context *ChainContext) GetFootprintManager() *footprint.Manager {
 	return nil
 }
 
+func (context *ChainContext) GetSolanaBlockhash(slot uint64) common.Hash {
+	return rawdb.ReadSolanaBlockhash(context.b.ChainDb(), slot)
+}
+
 
 func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.StateDB, header *types.Header, overrides *StateOverride, blockOverrides *BlockOverrides, timeout time.Duration, globalGasCap uint64) (*core.ExecutionResult, error) {
 	if err := overrides.Apply(state); err != nil {
@@ -1203,6 +1224,8 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 		blockOverrides.Apply(&blockCtx)
 	}
 	evm := b.GetEVM(ctx, msg, state, header, &vm.Config{NoBaseFee: true}, &blockCtx)
+	core.ReadSolanaBlockContext(b.ChainDb(), header, &evm.TxContext)
+	blockOverrides.ApplySolana(&evm.TxContext)
 
 	// Wait for the context to be done and cancel the evm. Even if the
 	// EVM has finished, cancelling may be done (repeatedly)
@@ -1332,6 +1355,9 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 		State:      state,
 		ErrorRatio: estimateGasErrorRatio,
 	}
+	var solanaCtx vm.TxContext
+	core.ReadSolanaBlockContext(b.ChainDb(), header, &solanaCtx)
+	opts.SolanaBlockNumber, opts.SolanaTimestamp = solanaCtx.SolanaBlockNumber, solanaCtx.SolanaTimestamp
 	// Run the gas estimation andwrap any revertals into a custom return
 	call, err := args.ToMessage(gasCap, header.BaseFee)
 	if err != nil {
@@ -1376,63 +1402,45 @@ func (s *BlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, b
 		}
 	}
 
-	return estimateRomeGas(ctx, args)
+	gas, err := estimateRomeGas(ctx, s.b, args)
+	if err != nil && romeGasometer(s.b).Fallback(err) {
+		log.Warn("Gasometer unavailable, estimating gas locally", "err", err)
+		return DoEstimateGas(ctx, s.b, args, bNrOrHash, overrides, s.b.RPCGasCap())
+	}
+	return gas, err
 }
 
-// Fetch gas estimate from Rome gasometer
-func estimateRomeGas(ctx context.Context, args TransactionArgs) (hexutil.Uint64, error) {
+// estimateRomeGas fetches the gas estimate of a call from the Rome gasometer.
+func estimateRomeGas(ctx context.Context, b Backend, args TransactionArgs) (hexutil.Uint64, error) {
 	tracer := log.GetTracer()
-	_, span := tracer.Start(ctx, "estimateRomeGas",
+	ctx, span := tracer.Start(ctx, "estimateRomeGas",
 		trace.WithAttributes(
 			attribute.String("timestamp", time.Now().Format(time.RFC3339Nano)),
 		))
 	defer span.End()
-	gasometerUrl := os.Getenv("ROME_GASOMETER_URL")
-	if gasometerUrl == "" {
-		return 0, fmt.Errorf("ROME_GASOMETER_URL ennvar is not set")
-	}
-	client, err := rpc.Dial(gasometerUrl)
-	if err != nil {
-		log.Error("Failed to connect to the Ethereum client: %v", err)
-		return 0, fmt.Errorf("failed to connect to gasometer: %w", err)
-	}
-	defer client.Close()
 
-	var estimatedGas hexutil.Uint64
-	err = client.CallContext(ctx, &estimatedGas, "eth_estimateGas", args)
-	if err != nil {
-		return 0, err
-	}
-
-	return estimatedGas, nil
+	gas, err := romeGasometer(b).EstimateGas(ctx, args)
+	return hexutil.Uint64(gas), err
 }
 
-// Fetch gas estimate from Rome gasometer
-func emulateRomeTx(ctx context.Context, input hexutil.Bytes) error {
+// emulateRomeTx emulates a signed transaction via the Rome gasometer. If the
+// gasometer is unavailable and skipping emulations is enabled, the emulation is
+// skipped.
+func emulateRomeTx(ctx context.Context, b Backend, input hexutil.Bytes) error {
 	tracer := log.GetTracer()
-	_, span := tracer.Start(ctx, "emulateRomeTx",
+	ctx, span := tracer.Start(ctx, "emulateRomeTx",
 		trace.WithAttributes(
 			attribute.String("timestamp", time.Now().Format(time.RFC3339Nano)),
 		))
 	defer span.End()
-	gasometerUrl := os.Getenv("ROME_GASOMETER_URL")
-	if gasometerUrl == "" {
-		return fmt.Errorf("ROME_GASOMETER_URL ennvar is not set")
-	}
-	client, err := rpc.Dial(gasometerUrl)
-	if err != nil {
-		log.Error("Failed to connect to the Ethereum client: %v", err)
-		return fmt.Errorf("failed to connect to gasometer: %w", err)
-	}
-	defer client.Close()
 
-	var result interface{}
-	err = client.CallContext(ctx, &result, "rome_emulateTx", input)
-	if err != nil {
-		return fmt.Errorf("call to rome_emulateTx failed: %w", err)
+	gasometer := romeGasometer(b)
+	err := gasometer.EmulateTx(ctx, input)
+	if err != nil && gasometer.SkipEmulation(err) {
+		log.Warn("Gasometer unavailable, skipping transaction emulation", "err", err)
+		return nil
 	}
-
-	return nil
+	return err
 }
 
 // RPCMarshalHeader converts the given header to the RPC output .
@@ -1505,6 +1513,10 @@ func RPCMarshalBlock(ctx context.Context, block *types.Block, inclTx bool, fullT
 	if block.Header().WithdrawalsHash != nil {
 		fields["withdrawals"] = block.Withdrawals()
 	}
+	if slot, timestamp := blockSolanaMetadata(backend, block); slot != nil {
+		fields["solanaSlot"] = slot
+		fields["solanaTimestamp"] = timestamp
+	}
 	return fields, nil
 }
 
@@ -1560,6 +1572,10 @@ type RPCTransaction struct {
 	IsSystemTx *bool        `json:"isSystemTx,omitempty"`
 	// deposit-tx post-Canyon only
 	DepositReceiptVersion *hexutil.Uint64 `json:"depositReceiptVersion,omitempty"`
+
+	// Solana context the transaction was executed in, mined Rome txs only
+	SolanaSlot      *hexutil.Uint64 `json:"solanaSlot,omitempty"`
+	SolanaTimestamp *hexutil.Uint64 `json:"solanaTimestamp,omitempty"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
@@ -1693,7 +1709,9 @@ func newRPCTransactionFromBlockIndex(ctx context.Context, b *types.Block, index
 	}
 	tx := txs[index]
 	rcpt := depositTxReceipt(ctx, b.Hash(), index, backend, tx)
-	return newRPCTransaction(tx, b.Hash(), b.NumberU64(), b.Time(), index, b.BaseFee(), config, rcpt)
+	result := newRPCTransaction(tx, b.Hash(), b.NumberU64(), b.Time(), index, b.BaseFee(), config, rcpt)
+	result.setSolanaMetadata(backend)
+	return result
 }
 
 func depositTxReceipt(ctx context.Context, blockHash common.Hash, index uint64, backend Backend, tx *types.Transaction) *types.Receipt {
@@ -1941,7 +1959,9 @@ func (s *TransactionAPI) GetTransactionByHash(ctx context.Context, hash common.H
 			return nil, err
 		}
 		rcpt := depositTxReceipt(ctx, blockHash, index, s.b, tx)
-		return newRPCTransaction(tx, blockHash, blockNumber, header.Time, index, header.BaseFee, s.b.ChainConfig(), rcpt), nil
+		result := newRPCTransaction(tx, blockHash, blockNumber, header.Time, index, header.BaseFee, s.b.ChainConfig(), rcpt)
+		result.setSolanaMetadata(s.b)
+		return result, nil
 	}
 	// No finalized transaction, try to retrieve it from the pool
 	if tx := s.b.GetPoolTransaction(hash); tx != nil {
@@ -1992,7 +2012,9 @@ func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash common.
 
 	// Derive the sender.
 	signer := types.MakeSigner(s.b.ChainConfig(), header.Number, header.Time)
-	return marshalReceipt(receipt, blockHash, blockNumber, signer, tx, int(index), s.b.ChainConfig()), nil
+	fields := marshalReceipt(receipt, blockHash, blockNumber, signer, tx, int(index), s.b.ChainConfig())
+	setReceiptSolanaMetadata(s.b, fields, hash)
+	return fields, nil
 }
 
 // marshalReceipt marshals a transaction receipt into a JSON object.
@@ -2161,7 +2183,7 @@ func (s *TransactionAPI) SendRawTransaction(ctx context.Context, input hexutil.B
 		return common.Hash{}, err
 	}
 
-	err := emulateRomeTx(ctx, input)
+	err := emulateRomeTx(ctx, s.b, input)
 
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("rome emulate tx failed: %w", err)
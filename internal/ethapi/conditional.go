@@ -0,0 +1,132 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// errCodeConditionalRejected is returned if the conditional does not hold.
+	errCodeConditionalRejected = -32003
+
+	// errCodeConditionalLimited is returned if the conditional is too costly or
+	// the rate limit of conditional checks is exceeded.
+	errCodeConditionalLimited = -32005
+)
+
+var (
+	conditionalRequestsMeter    = metrics.NewRegisteredMeter("ethapi/conditional/requests", nil)
+	conditionalAcceptedMeter    = metrics.NewRegisteredMeter("ethapi/conditional/accepted", nil)
+	conditionalRejectedMeter    = metrics.NewRegisteredMeter("ethapi/conditional/rejected", nil)
+	conditionalRateLimitedMeter = metrics.NewRegisteredMeter("ethapi/conditional/ratelimited", nil)
+	conditionalCostHistogram    = metrics.NewRegisteredHistogram("ethapi/conditional/cost", nil, metrics.NewExpDecaySample(1028, 0.015))
+)
+
+// conditionalError is an API error reporting the rejection of a transaction
+// conditional.
+type conditionalError struct {
+	error
+	code int
+}
+
+// ErrorCode returns the JSON error code of the rejection.
+func (e *conditionalError) ErrorCode() int {
+	return e.code
+}
+
+// TransactionConditionalAPI exposes the submission of transactions which are
+// only to be included while a set of chain state constraints holds.
+type TransactionConditionalAPI struct {
+	b           Backend
+	costLimiter *rate.Limiter
+}
+
+// NewTransactionConditionalAPI creates a new conditional transaction API,
+// accepting up to costRateLimit conditional checks per second.
+func NewTransactionConditionalAPI(b Backend, costRateLimit int) *TransactionConditionalAPI {
+	// Allow bursts of a few maximum cost conditionals on top of the rate
+	return &TransactionConditionalAPI{
+		b:           b,
+		costLimiter: rate.NewLimiter(rate.Limit(costRateLimit), 3*params.TransactionConditionalMaxCost),
+	}
+}
+
+// SendRawTransactionConditional will add the signed transaction to the
+// transaction pool, to only be included while its known accounts storage and
+// block number and timestamp bounds hold. The sender is responsible for
+// signing the transaction and using the correct nonce.
+func (api *TransactionConditionalAPI) SendRawTransactionConditional(ctx context.Context, input hexutil.Bytes, cond types.TransactionConditional) (common.Hash, error) {
+	conditionalRequestsMeter.Mark(1)
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	// Bound the work requested before looking at any state
+	cost := cond.Cost()
+	conditionalCostHistogram.Update(int64(cost))
+	if cost > params.TransactionConditionalMaxCost {
+		conditionalRejectedMeter.Mark(1)
+		return common.Hash{}, &conditionalError{fmt.Errorf("conditional cost %d exceeds maximum %d", cost, params.TransactionConditionalMaxCost), errCodeConditionalLimited}
+	}
+	if err := cond.Validate(); err != nil {
+		conditionalRejectedMeter.Mark(1)
+		return common.Hash{}, &conditionalError{fmt.Errorf("invalid conditional: %w", err), errCodeConditionalRejected}
+	}
+	if !api.costLimiter.AllowN(time.Now(), max(cost, 1)) {
+		conditionalRateLimitedMeter.Mark(1)
+		return common.Hash{}, &conditionalError{fmt.Errorf("conditional cost %d rate limited", cost), errCodeConditionalLimited}
+	}
+	// Reject the conditionals that can no longer be met on top of the head
+	state, header, err := api.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return common.Hash{}, err
+	}
+	if err := cond.CheckExpiry(header); err != nil {
+		conditionalRejectedMeter.Mark(1)
+		return common.Hash{}, &conditionalError{err, errCodeConditionalRejected}
+	}
+	if err := state.CheckTransactionConditional(&cond); err != nil {
+		conditionalRejectedMeter.Mark(1)
+		return common.Hash{}, &conditionalError{err, errCodeConditionalRejected}
+	}
+	tx.SetConditional(&cond)
+
+	hash, err := SubmitTransaction(ctx, api.b, tx)
+	if errors.Is(err, txpool.ErrTxConditionalRejected) {
+		conditionalRejectedMeter.Mark(1)
+		return common.Hash{}, &conditionalError{err, errCodeConditionalRejected}
+	}
+	if err != nil {
+		return common.Hash{}, err
+	}
+	conditionalAcceptedMeter.Mark(1)
+	return hash, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/gasometer"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxEmulatedBatch is the maximum number of raw transactions accepted in a
+// single rome_sendRawTransactionsWithEmulation call.
+const maxEmulatedBatch = 100
+
+// RomeTransactionAPI exposes transaction submission methods reporting the
+// outcome of the Rome emulation of the transactions.
+type RomeTransactionAPI struct {
+	b Backend
+}
+
+// NewRomeTransactionAPI creates a new Rome transaction API.
+func NewRomeTransactionAPI(b Backend) *RomeTransactionAPI {
+	return &RomeTransactionAPI{b}
+}
+
+// EmulatedTransaction is the result of submitting a raw transaction after
+// emulating it. Hash is only set if the transaction was submitted to the pool.
+// Unemulated is set if the gasometer was unavailable and the transaction was
+// submitted without being emulated, in which case Emulation is empty.
+type EmulatedTransaction struct {
+	Hash         *common.Hash               `json:"hash,omitempty"`
+	Emulation    *gasometer.EmulationResult `json:"emulation,omitempty"`
+	Unemulated   bool                       `json:"unemulated,omitempty"`
+	RevertReason string                     `json:"revertReason,omitempty"`
+	Error        string                     `json:"error,omitempty"`
+}
+
+// SendRawTransactionWithEmulation emulates the signed transaction and, if the
+// emulation succeeds, submits it to the transaction pool. The emulation result
+// is returned alongside the transaction hash.
+func (api *RomeTransactionAPI) SendRawTransactionWithEmulation(ctx context.Context, input hexutil.Bytes) (*EmulatedTransaction, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return nil, err
+	}
+	return api.sendWithEmulation(ctx, tx, input), nil
+}
+
+// SendRawTransactionsWithEmulation emulates and submits a batch of signed
+// transactions in order, reporting the outcome of each one. A failing
+// transaction does not prevent the submission of the ones after it.
+func (api *RomeTransactionAPI) SendRawTransactionsWithEmulation(ctx context.Context, inputs []hexutil.Bytes) ([]*EmulatedTransaction, error) {
+	if len(inputs) > maxEmulatedBatch {
+		return nil, fmt.Errorf("too many transactions: have %d, max %d", len(inputs), maxEmulatedBatch)
+	}
+	results := make([]*EmulatedTransaction, len(inputs))
+	for i, input := range inputs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			results[i] = &EmulatedTransaction{Error: err.Error()}
+			continue
+		}
+		results[i] = api.sendWithEmulation(ctx, tx, input)
+	}
+	return results, nil
+}
+
+// sendWithEmulation emulates a decoded transaction through the gasometer and
+// submits it if the emulation passes, recording the emulated footprint for
+// comparison against the one of the execution.
+func (api *RomeTransactionAPI) sendWithEmulation(ctx context.Context, tx *types.Transaction, input hexutil.Bytes) *EmulatedTransaction {
+	res := new(EmulatedTransaction)
+
+	g := romeGasometer(api.b)
+	emulation, err := g.Emulate(ctx, input)
+	switch {
+	case err != nil && g.SkipEmulation(err):
+		log.Warn("Gasometer unavailable, skipping transaction emulation", "hash", tx.Hash(), "err", err)
+		res.Unemulated = true
+	case err != nil:
+		res.Error = fmt.Sprintf("rome emulate tx failed: %v", err)
+		return res
+	case emulation.Failed():
+		res.Emulation = emulation
+		res.Error = emulation.Error
+		if reason, errUnpack := abi.UnpackRevert(emulation.ReturnData); errUnpack == nil {
+			res.RevertReason = reason
+			res.Error = fmt.Sprintf("%v: %v", vm.ErrExecutionReverted, reason)
+		}
+		return res
+	default:
+		res.Emulation = emulation
+	}
+	hash, err := SubmitTransaction(ctx, api.b, tx)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Hash = &hash
+
+	if manager := api.b.FootprintManager(); manager != nil && emulation != nil {
+		manager.RecordEmulation(hash, emulation.Footprint, api.b.CurrentBlock().Number.Uint64())
+	}
+	return res
+}
@@ -0,0 +1,212 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/gasometer"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// LocalGasometer is an in-process stand-in for the Rome gasometer, serving gas
+// prices, estimates and emulations from local EVM execution on top of the
+// latest block. It allows running a Rome chain without the Rome services, e.g.
+// in developer mode and tests.
+type LocalGasometer struct {
+	b Backend
+}
+
+var _ gasometer.Gasometer = (*LocalGasometer)(nil)
+
+// NewLocalGasometer creates a gasometer executing requests on the backend.
+func NewLocalGasometer(b Backend) *LocalGasometer {
+	return &LocalGasometer{b: b}
+}
+
+// GasPrice returns the gas price suggested by the local gas price oracle.
+func (g *LocalGasometer) GasPrice(ctx context.Context) (*big.Int, error) {
+	tipcap, err := g.b.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if head := g.b.CurrentHeader(); head.BaseFee != nil {
+		tipcap.Add(tipcap, head.BaseFee)
+	}
+	return tipcap, nil
+}
+
+// EstimateGas estimates the gas needed by the call on top of the latest block.
+func (g *LocalGasometer) EstimateGas(ctx context.Context, args interface{}) (uint64, error) {
+	call, ok := args.(TransactionArgs)
+	if !ok {
+		return 0, fmt.Errorf("unsupported call arguments %T", args)
+	}
+	gas, err := DoEstimateGas(ctx, g.b, call, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), nil, g.b.RPCGasCap())
+	return uint64(gas), err
+}
+
+// EmulateTx executes the signed transaction on top of the latest block and
+// returns an error if it is stale or fails.
+func (g *LocalGasometer) EmulateTx(ctx context.Context, input hexutil.Bytes) error {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return err
+	}
+	state, header, err := g.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return err
+	}
+	from, err := types.Sender(types.MakeSigner(g.b.ChainConfig(), header.Number, header.Time), tx)
+	if err != nil {
+		return err
+	}
+	if nonce := state.GetNonce(from); nonce > tx.Nonce() {
+		return fmt.Errorf("%w: address %v, tx: %d state: %d", core.ErrNonceTooLow, from, tx.Nonce(), nonce)
+	}
+	var (
+		gas        = hexutil.Uint64(tx.Gas())
+		data       = hexutil.Bytes(tx.Data())
+		accessList = tx.AccessList()
+	)
+	args := TransactionArgs{
+		From:       &from,
+		To:         tx.To(),
+		Gas:        &gas,
+		Value:      (*hexutil.Big)(tx.Value()),
+		Input:      &data,
+		AccessList: &accessList,
+	}
+	result, err := doCall(ctx, g.b, args, state, header, nil, nil, g.b.RPCEVMTimeout(), g.b.RPCGasCap())
+	if err != nil {
+		return err
+	}
+	if len(result.Revert()) > 0 {
+		return newRevertError(result.Revert())
+	}
+	return result.Err
+}
+
+// Emulate executes the signed transaction on top of the latest block the way
+// a Rome block would: first to measure its gas use, then charged for it, to
+// derive the expected state footprint of the transaction.
+func (g *LocalGasometer) Emulate(ctx context.Context, input hexutil.Bytes) (*gasometer.EmulationResult, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return nil, err
+	}
+	statedb, header, err := g.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	config := g.b.ChainConfig()
+	msg, err := core.TransactionToMessage(tx, types.MakeSigner(config, header.Number, header.Time), header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	if nonce := statedb.GetNonce(msg.From); nonce > tx.Nonce() {
+		return nil, fmt.Errorf("%w: address %v, tx: %d state: %d", core.ErrNonceTooLow, msg.From, tx.Nonce(), nonce)
+	}
+	msg.SkipAccountChecks = true // Allow emulating transactions queued behind others
+
+	hasher := state.NewFootprintHasher(config.FootprintVersion(header.Time))
+	if hasher == nil {
+		return nil, fmt.Errorf("unsupported footprint version %d", config.FootprintVersion(header.Time))
+	}
+	var cancel context.CancelFunc
+	if timeout := g.b.RPCEVMTimeout(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	evm := g.b.GetEVM(ctx, msg, statedb, header, &vm.Config{NoBaseFee: true}, nil)
+	core.ReadSolanaBlockContext(g.b.ChainDb(), header, &evm.TxContext)
+	go func() {
+		<-ctx.Done()
+		evm.Cancel()
+	}()
+	statedb.SetTxContext(tx.Hash(), 0)
+
+	// Measure the gas consumed by the execution, then apply it for real with
+	// the gas charged, the way the transaction would be included
+	snap := statedb.Snapshot()
+	result, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(math.MaxUint64), 0, 0)
+	statedb.RevertToSnapshot(snap)
+	statedb.ResetTxFootprint()
+	if err != nil {
+		return nil, err
+	}
+	// Rome charges no intrinsic gas, stand in with the Ethereum base cost
+	gasUsed := params.TxGas + result.EVMGasUsed
+	if msg.To == nil {
+		gasUsed = params.TxGasContractCreation + result.EVMGasUsed
+	}
+	start := statedb.JournalLength()
+	evm.Reset(evm.TxContext, statedb)
+	if result, err = core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(math.MaxUint64), gasUsed, msg.GasPrice.Uint64()); err != nil {
+		return nil, err
+	}
+	if evm.Cancelled() {
+		return nil, fmt.Errorf("execution aborted (timeout = %v)", g.b.RPCEVMTimeout())
+	}
+	emulation := &gasometer.EmulationResult{
+		GasUsed:    hexutil.Uint64(gasUsed),
+		Footprint:  hasher.Fold(statedb.TxFootprintAccounts(start, hasher)).Hex(),
+		Logs:       make([]*gasometer.EmulatedLog, 0),
+		ReturnData: result.ReturnData,
+	}
+	if result.Err != nil {
+		emulation.Error = result.Err.Error()
+	}
+	for _, l := range statedb.GetLogs(tx.Hash(), header.Number.Uint64(), common.Hash{}) {
+		emulation.Logs = append(emulation.Logs, &gasometer.EmulatedLog{Address: l.Address, Topics: l.Topics, Data: l.Data})
+	}
+	return emulation, nil
+}
+
+// Fallback implements gasometer.Gasometer. Local execution has nothing to fall
+// back to.
+func (g *LocalGasometer) Fallback(err error) bool {
+	return false
+}
+
+// SkipEmulation implements gasometer.Gasometer. Local execution is always
+// available, emulations are never skipped.
+func (g *LocalGasometer) SkipEmulation(err error) bool {
+	return false
+}
+
+// romeGasometer returns the gasometer of the backend, executing locally if the
+// backend has none.
+func romeGasometer(b Backend) gasometer.Gasometer {
+	if g := b.Gasometer(); g != nil {
+		return g
+	}
+	return NewLocalGasometer(b)
+}
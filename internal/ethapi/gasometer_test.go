@@ -0,0 +1,137 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the local gasometer emulates transactions on the latest state.
+func TestLocalGasometerEmulateTx(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(2)
+		reverter = common.HexToAddress("0xdeadbeef")
+		genesis  = &core.Genesis{
+			Config:   params.TestChainConfig,
+			Coinbase: common.Address{0xc0},
+			Alloc: core.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+				accounts[1].addr: {Balance: big.NewInt(params.Ether), Nonce: 1},
+				reverter:         {Code: common.FromHex("0x60006000fd")}, // PUSH1 0 PUSH1 0 REVERT
+			},
+		}
+		signer    = types.LatestSigner(params.TestChainConfig)
+		gasometer = NewLocalGasometer(newTestBackend(t, 0, genesis, ethash.NewFaker(), nil))
+	)
+	var tests = []struct {
+		key  int
+		tx   *types.LegacyTx
+		want error
+	}{
+		{0, &types.LegacyTx{Nonce: 0, To: &accounts[1].addr, Value: big.NewInt(1000), Gas: params.TxGas, GasPrice: big.NewInt(params.InitialBaseFee)}, nil},
+		{0, &types.LegacyTx{Nonce: 5, To: &accounts[1].addr, Value: big.NewInt(1000), Gas: params.TxGas, GasPrice: big.NewInt(params.InitialBaseFee)}, nil},
+		{1, &types.LegacyTx{Nonce: 0, To: &accounts[0].addr, Value: big.NewInt(1000), Gas: params.TxGas, GasPrice: big.NewInt(params.InitialBaseFee)}, core.ErrNonceTooLow},
+		{0, &types.LegacyTx{Nonce: 0, To: &reverter, Gas: 100000, GasPrice: big.NewInt(params.InitialBaseFee)}, vm.ErrExecutionReverted},
+	}
+	for i, test := range tests {
+		tx, err := types.SignNewTx(accounts[test.key].key, signer, test.tx)
+		if err != nil {
+			t.Fatalf("test %d: failed to sign transaction: %v", i, err)
+		}
+		input, _ := tx.MarshalBinary()
+		if err := gasometer.EmulateTx(context.Background(), input); !errors.Is(err, test.want) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, test.want)
+		}
+	}
+	if gasometer.Fallback(errors.New("failure")) {
+		t.Error("local gasometer falls back")
+	}
+}
+
+// Tests that the local gasometer reports the gas use, footprint and logs of an
+// emulated transaction, and that reverted emulations are not submitted.
+func TestLocalGasometerEmulate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(1)
+		logger   = common.HexToAddress("0x1000")
+		reverter = common.HexToAddress("0x2000")
+		genesis  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+				logger:           {Code: common.FromHex("0x60006000a000")}, // PUSH1 0 PUSH1 0 LOG0 STOP
+				// CODECOPY an Error("boom") revert payload appended to the code and REVERT with it
+				reverter: {Code: common.FromHex("0x6064600c60003960646000fd" +
+					"08c379a0" +
+					"0000000000000000000000000000000000000000000000000000000000000020" +
+					"0000000000000000000000000000000000000000000000000000000000000004" +
+					"626f6f6d00000000000000000000000000000000000000000000000000000000")},
+			},
+		}
+		signer  = types.LatestSigner(params.TestChainConfig)
+		backend = newTestBackend(t, 0, genesis, ethash.NewFaker(), nil)
+	)
+	sign := func(to common.Address) hexutil.Bytes {
+		tx, err := types.SignNewTx(accounts[0].key, signer, &types.LegacyTx{Nonce: 0, To: &to, Gas: 100000, GasPrice: big.NewInt(params.InitialBaseFee)})
+		if err != nil {
+			t.Fatalf("failed to sign transaction: %v", err)
+		}
+		input, _ := tx.MarshalBinary()
+		return input
+	}
+	res, err := NewLocalGasometer(backend).Emulate(context.Background(), sign(logger))
+	if err != nil {
+		t.Fatalf("failed to emulate transaction: %v", err)
+	}
+	if res.Failed() {
+		t.Fatalf("emulation failed: %v", res.Error)
+	}
+	if res.GasUsed <= hexutil.Uint64(params.TxGas) {
+		t.Errorf("gas used too low: have %d, want above %d", res.GasUsed, params.TxGas)
+	}
+	if len(res.Logs) != 1 || res.Logs[0].Address != logger {
+		t.Errorf("unexpected logs: %+v", res.Logs)
+	}
+	if res.Footprint == "" {
+		t.Error("missing footprint")
+	}
+	sent, err := NewRomeTransactionAPI(backend).SendRawTransactionWithEmulation(context.Background(), sign(reverter))
+	if err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	if sent.Hash != nil {
+		t.Error("reverted transaction submitted")
+	}
+	if sent.RevertReason != "boom" {
+		t.Errorf("revert reason mismatch: have %q, want %q", sent.RevertReason, "boom")
+	}
+}
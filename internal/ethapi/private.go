@@ -0,0 +1,55 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// PrivateTransactionAPI exposes the submission of transactions which are kept
+// out of the public mempool.
+type PrivateTransactionAPI struct {
+	b Backend
+}
+
+// NewPrivateTransactionAPI creates a new private transaction API.
+func NewPrivateTransactionAPI(b Backend) *PrivateTransactionAPI {
+	return &PrivateTransactionAPI{b}
+}
+
+// SendPrivateRawTransaction will add the signed transaction to the private lane
+// of the transaction pool. The transaction is not gossiped to the network, only
+// the local miner includes it, and it is dropped if not included within the
+// configured number of blocks. The sender is responsible for signing the
+// transaction and using the correct nonce.
+func (api *PrivateTransactionAPI) SendPrivateRawTransaction(ctx context.Context, input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	// Blob transactions are announced by their own pool, which has no private lane
+	if tx.Type() == types.BlobTxType {
+		return common.Hash{}, errors.New("blob transactions can not be submitted privately")
+	}
+	tx.SetPrivate(true)
+	return SubmitTransaction(ctx, api.b, tx)
+}
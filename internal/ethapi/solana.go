@@ -0,0 +1,72 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// solanaMetadata returns the Solana slot and timestamp the transaction was
+// executed at, or nils if they are unknown.
+func solanaMetadata(b Backend, hash common.Hash) (*hexutil.Uint64, *hexutil.Uint64) {
+	db := b.ChainDb()
+	if db == nil {
+		return nil, nil
+	}
+	slot, timestamp, ok := rawdb.ReadSolanaTxMetadata(db, hash)
+	if !ok {
+		return nil, nil
+	}
+	ts := uint64(timestamp)
+	return (*hexutil.Uint64)(&slot), (*hexutil.Uint64)(&ts)
+}
+
+// blockSolanaMetadata returns the latest Solana slot and timestamp among the
+// transactions of the block, or nils if none of them are known.
+func blockSolanaMetadata(b Backend, block *types.Block) (*hexutil.Uint64, *hexutil.Uint64) {
+	var slot, timestamp *hexutil.Uint64
+	for _, tx := range block.Transactions() {
+		txSlot, txTimestamp := solanaMetadata(b, tx.Hash())
+		if txSlot == nil {
+			continue
+		}
+		if slot == nil || *txSlot > *slot {
+			slot = txSlot
+		}
+		if timestamp == nil || *txTimestamp > *timestamp {
+			timestamp = txTimestamp
+		}
+	}
+	return slot, timestamp
+}
+
+// setSolanaMetadata fills the Solana slot and timestamp of a mined transaction.
+func (tx *RPCTransaction) setSolanaMetadata(b Backend) {
+	tx.SolanaSlot, tx.SolanaTimestamp = solanaMetadata(b, tx.Hash)
+}
+
+// setReceiptSolanaMetadata adds the Solana slot and timestamp of the transaction
+// to its marshalled receipt, if known.
+func setReceiptSolanaMetadata(b Backend, fields map[string]interface{}, hash common.Hash) {
+	if slot, timestamp := solanaMetadata(b, hash); slot != nil {
+		fields["solanaSlot"] = slot
+		fields["solanaTimestamp"] = timestamp
+	}
+}
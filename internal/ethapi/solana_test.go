@@ -0,0 +1,102 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Tests that the Solana context of transactions and blocks is exposed.
+func TestSolanaMetadata(t *testing.T) {
+	t.Parallel()
+
+	var (
+		genesis = &core.Genesis{Config: params.TestChainConfig, Coinbase: common.Address{0xc0}}
+		backend = newTestBackend(t, 0, genesis, ethash.NewFaker(), nil)
+		txs     = []*types.Transaction{
+			types.NewTx(&types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(1)}),
+			types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1)}),
+			types.NewTx(&types.LegacyTx{Nonce: 2, GasPrice: big.NewInt(1)}),
+		}
+	)
+	rawdb.WriteSolanaTxMetadata(backend.db, txs[0].Hash(), 100, 1700000000)
+	rawdb.WriteSolanaTxMetadata(backend.db, txs[1].Hash(), 102, 1700000001)
+
+	slot, timestamp := solanaMetadata(backend, txs[0].Hash())
+	if slot == nil || uint64(*slot) != 100 || timestamp == nil || uint64(*timestamp) != 1700000000 {
+		t.Errorf("transaction metadata mismatch: have %v/%v, want 100/1700000000", slot, timestamp)
+	}
+	if slot, timestamp := solanaMetadata(backend, txs[2].Hash()); slot != nil || timestamp != nil {
+		t.Errorf("unknown transaction has metadata: %v/%v", slot, timestamp)
+	}
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, txs, nil, nil, trie.NewStackTrie(nil))
+	slot, timestamp = blockSolanaMetadata(backend, block)
+	if slot == nil || uint64(*slot) != 102 || timestamp == nil || uint64(*timestamp) != 1700000001 {
+		t.Errorf("block metadata mismatch: have %v/%v, want 102/1700000001", slot, timestamp)
+	}
+	if slot, timestamp := blockSolanaMetadata(backend, types.NewBlockWithHeader(&types.Header{})); slot != nil || timestamp != nil {
+		t.Errorf("empty block has metadata: %v/%v", slot, timestamp)
+	}
+	fields := map[string]interface{}{}
+	setReceiptSolanaMetadata(backend, fields, txs[1].Hash())
+	if fields["solanaSlot"] == nil || fields["solanaTimestamp"] == nil {
+		t.Errorf("receipt metadata missing: %v", fields)
+	}
+}
+
+// Tests that calls can override the Solana slot and timestamp seen by the EVM.
+func TestCallSolanaOverrides(t *testing.T) {
+	t.Parallel()
+
+	var (
+		contract = common.Address{0xcc}
+		genesis  = &core.Genesis{
+			Config:   params.TestChainConfig,
+			Coinbase: common.Address{0xc0},
+			Alloc: core.GenesisAlloc{
+				// NUMBER PUSH1 0 MSTORE TIMESTAMP PUSH1 32 MSTORE PUSH1 64 PUSH1 0 RETURN
+				contract: {Balance: big.NewInt(0), Code: common.FromHex("0x436000524260205260406000f3")},
+			},
+		}
+		api      = NewBlockChainAPI(newTestBackend(t, 0, genesis, ethash.NewFaker(), nil))
+		latest   = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		slot     = hexutil.Uint64(123)
+		ts       = hexutil.Uint64(1700000000)
+		override = &BlockOverrides{SolanaSlot: &slot, SolanaTimestamp: &ts}
+	)
+	res, err := api.Call(context.Background(), TransactionArgs{To: &contract}, &latest, nil, override)
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	want := append(common.BigToHash(big.NewInt(123)).Bytes(), common.BigToHash(big.NewInt(1700000000)).Bytes()...)
+	if !bytes.Equal(res, want) {
+		t.Errorf("result mismatch: have %x, want %x", res, want)
+	}
+}
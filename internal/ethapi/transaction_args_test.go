@@ -31,9 +31,11 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/footprint"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/gasometer"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/params"
@@ -405,6 +407,8 @@ func (b *backendMock) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent)
 	return nil
 }
 
-func (b *backendMock) Engine() consensus.Engine          { return nil }
-func (b *backendMock) HistoricalRPCService() *rpc.Client { return nil }
-func (b *backendMock) Genesis() *types.Block             { return nil }
+func (b *backendMock) Engine() consensus.Engine             { return nil }
+func (b *backendMock) HistoricalRPCService() *rpc.Client    { return nil }
+func (b *backendMock) Gasometer() gasometer.Gasometer       { return nil }
+func (b *backendMock) FootprintManager() *footprint.Manager { return nil }
+func (b *backendMock) Genesis() *types.Block                { return nil }
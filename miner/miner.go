@@ -61,6 +61,9 @@ type Config struct {
 	NewPayloadTimeout time.Duration // The maximum time allowance for creating a new payload
 
 	RollupComputePendingBlock bool // Compute the pending block from tx-pool, instead of copying the latest-block
+
+	TxOrdering            string // Policy ordering the pending transactions into blocks (price, fifo, fair)
+	TxOrderingSenderLimit int    // Maximum number of transactions per sender in a block for the fair ordering
 }
 
 // DefaultConfig contains default settings for miner.
@@ -74,6 +77,9 @@ var DefaultConfig = Config{
 	// run 3 rounds.
 	Recommit:          2 * time.Second,
 	NewPayloadTimeout: 2 * time.Second,
+
+	TxOrdering:            PriceTimeOrdering,
+	TxOrderingSenderLimit: 16,
 }
 
 // Miner creates blocks and searches for proof-of-work values.
@@ -248,7 +254,8 @@ func (miner *Miner) SubscribePendingLogs(ch chan<- []*types.Log) event.Subscript
 	return miner.worker.pendingLogsFeed.Subscribe(ch)
 }
 
-// BuildPayload builds the payload according to the provided parameters.
-func (miner *Miner) BuildPayload(args *BuildPayloadArgs) (*Payload, error) {
-	return miner.worker.buildPayload(args)
+// BuildPayload builds the payload according to the provided parameters. The
+// building of the payload is traced under the given context.
+func (miner *Miner) BuildPayload(ctx context.Context, args *BuildPayloadArgs) (*Payload, error) {
+	return miner.worker.buildPayload(ctx, args)
 }
@@ -51,97 +51,122 @@ func newTxWithMinerFee(tx *txpool.LazyTransaction, from common.Address, baseFee
 	}, nil
 }
 
-// txByPriceAndTime implements both the sort and the heap interface, making it useful
+// txByPolicy implements both the sort and the heap interface, making it useful
 // for all at once sorting as well as individually adding and removing elements.
-type txByPriceAndTime []*txWithMinerFee
-
-func (s txByPriceAndTime) Len() int { return len(s) }
-func (s txByPriceAndTime) Less(i, j int) bool {
-	// If the prices are equal, use the time the transaction was first seen for
-	// deterministic sorting
-	cmp := s[i].fees.Cmp(s[j].fees)
-	if cmp == 0 {
-		return s[i].tx.Time.Before(s[j].tx.Time)
-	}
-	return cmp > 0
+// The transactions are ordered by the comparator of the ordering policy.
+type txByPolicy struct {
+	txs    []*txWithMinerFee
+	policy TxOrderingPolicy
+}
+
+func (s *txByPolicy) Len() int { return len(s.txs) }
+func (s *txByPolicy) Less(i, j int) bool {
+	return s.policy.Less(s.txs[i].tx, s.txs[j].tx, s.txs[i].fees, s.txs[j].fees)
 }
-func (s txByPriceAndTime) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s *txByPolicy) Swap(i, j int) { s.txs[i], s.txs[j] = s.txs[j], s.txs[i] }
 
-func (s *txByPriceAndTime) Push(x interface{}) {
-	*s = append(*s, x.(*txWithMinerFee))
+func (s *txByPolicy) Push(x interface{}) {
+	s.txs = append(s.txs, x.(*txWithMinerFee))
 }
 
-func (s *txByPriceAndTime) Pop() interface{} {
-	old := *s
+func (s *txByPolicy) Pop() interface{} {
+	old := s.txs
 	n := len(old)
 	x := old[n-1]
 	old[n-1] = nil
-	*s = old[0 : n-1]
+	s.txs = old[0 : n-1]
 	return x
 }
 
-// transactionsByPriceAndNonce represents a set of transactions that can return
-// transactions in a profit-maximizing sorted order, while supporting removing
+// orderedTransactions represents a set of transactions that can return
+// transactions in the order of an ordering policy, while supporting removing
 // entire batches of transactions for non-executable accounts.
-type transactionsByPriceAndNonce struct {
-	txs     map[common.Address][]*txpool.LazyTransaction // Per account nonce-sorted list of transactions
-	heads   txByPriceAndTime                             // Next transaction for each unique account (price heap)
-	signer  types.Signer                                 // Signer for the set of transactions
-	baseFee *big.Int                                     // Current base fee
+type orderedTransactions struct {
+	txs      map[common.Address][]*txpool.LazyTransaction // Per account nonce-sorted list of transactions
+	heads    *txByPolicy                                  // Next transaction for each unique account (policy heap)
+	included map[common.Address]int                       // Number of transactions included per account
+	signer   types.Signer                                 // Signer for the set of transactions
+	baseFee  *big.Int                                     // Current base fee
+	perLimit int                                          // Maximum number of transactions per account, 0 if unlimited
 }
 
-// newTransactionsByPriceAndNonce creates a transaction set that can retrieve
-// price sorted transactions in a nonce-honouring way.
+// newOrderedTransactions creates a transaction set that can retrieve transactions
+// sorted by the given ordering policy in a nonce-honouring way.
 //
 // Note, the input map is reowned so the caller should not interact any more with
 // if after providing it to the constructor.
-func newTransactionsByPriceAndNonce(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int) *transactionsByPriceAndNonce {
-	// Initialize a price and received time based heap with the head transactions
-	heads := make(txByPriceAndTime, 0, len(txs))
+func newOrderedTransactions(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int, policy TxOrderingPolicy) *orderedTransactions {
+	// Initialize a policy ordered heap with the head transactions
+	heads := &txByPolicy{
+		txs:    make([]*txWithMinerFee, 0, len(txs)),
+		policy: policy,
+	}
 	for from, accTxs := range txs {
 		wrapped, err := newTxWithMinerFee(accTxs[0], from, baseFee)
 		if err != nil {
 			delete(txs, from)
 			continue
 		}
-		heads = append(heads, wrapped)
+		heads.txs = append(heads.txs, wrapped)
 		txs[from] = accTxs[1:]
 	}
-	heap.Init(&heads)
+	heap.Init(heads)
 
 	// Assemble and return the transaction set
-	return &transactionsByPriceAndNonce{
-		txs:     txs,
-		heads:   heads,
-		signer:  signer,
-		baseFee: baseFee,
+	return &orderedTransactions{
+		txs:      txs,
+		heads:    heads,
+		included: make(map[common.Address]int),
+		signer:   signer,
+		baseFee:  baseFee,
+		perLimit: policy.SenderLimit(),
 	}
 }
 
-// Peek returns the next transaction by price.
-func (t *transactionsByPriceAndNonce) Peek() *txpool.LazyTransaction {
-	if len(t.heads) == 0 {
+// Peek returns the next transaction by the ordering policy.
+func (t *orderedTransactions) Peek() *txpool.LazyTransaction {
+	if len(t.heads.txs) == 0 {
 		return nil
 	}
-	return t.heads[0].tx
+	return t.heads.txs[0].tx
+}
+
+// Shift replaces the current best head, which got included, with the next one
+// from the same account, unless the account exhausted its allowance of the
+// ordering policy.
+func (t *orderedTransactions) Shift() {
+	acc := t.heads.txs[0].from
+	if t.perLimit > 0 {
+		if t.included[acc]++; t.included[acc] >= t.perLimit {
+			heap.Pop(t.heads)
+			return
+		}
+	}
+	t.next()
+}
+
+// Skip replaces the current best head, which was not included, with the next
+// one from the same account, without consuming the allowance of the account.
+func (t *orderedTransactions) Skip() {
+	t.next()
 }
 
-// Shift replaces the current best head with the next one from the same account.
-func (t *transactionsByPriceAndNonce) Shift() {
-	acc := t.heads[0].from
+// next replaces the current best head with the next one from the same account.
+func (t *orderedTransactions) next() {
+	acc := t.heads.txs[0].from
 	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
 		if wrapped, err := newTxWithMinerFee(txs[0], acc, t.baseFee); err == nil {
-			t.heads[0], t.txs[acc] = wrapped, txs[1:]
-			heap.Fix(&t.heads, 0)
+			t.heads.txs[0], t.txs[acc] = wrapped, txs[1:]
+			heap.Fix(t.heads, 0)
 			return
 		}
 	}
-	heap.Pop(&t.heads)
+	heap.Pop(t.heads)
 }
 
 // Pop removes the best transaction, *not* replacing it with the next one from
 // the same account. This should be used when a transaction cannot be executed
 // and hence all subsequent ones should be discarded from the same account.
-func (t *transactionsByPriceAndNonce) Pop() {
-	heap.Pop(&t.heads)
+func (t *orderedTransactions) Pop() {
+	heap.Pop(t.heads)
 }
@@ -0,0 +1,121 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/txpool"
+)
+
+const (
+	// PriceTimeOrdering includes the transactions by descending effective miner
+	// tip, preferring the ones seen earlier among equally priced ones.
+	PriceTimeOrdering = "price"
+
+	// FIFOOrdering includes the transactions in the order they were first seen,
+	// regardless of their price.
+	FIFOOrdering = "fifo"
+
+	// FairOrdering includes the transactions by price-time priority, but caps
+	// the number of transactions included from each sender in a block.
+	FairOrdering = "fair"
+)
+
+// TxOrderingPolicy defines the order the pending transactions of different
+// accounts are included into the blocks built. The transactions of the same
+// account are always included in nonce order, the policy deciding between the
+// next transactions of each account.
+type TxOrderingPolicy interface {
+	// Name returns the identifier the policy is selected by.
+	Name() string
+
+	// Less reports whether the transaction a, paying the effective miner tip
+	// tipA, is to be included before the transaction b paying tipB.
+	Less(a, b *txpool.LazyTransaction, tipA, tipB *big.Int) bool
+
+	// SenderLimit returns the maximum number of transactions included from a
+	// single sender in a block, or zero if unlimited.
+	SenderLimit() int
+}
+
+// NewTxOrderingPolicy creates the built-in ordering policy with the given name.
+// The sender limit is only used by the fair ordering.
+func NewTxOrderingPolicy(name string, senderLimit int) (TxOrderingPolicy, error) {
+	switch name {
+	case "", PriceTimeOrdering:
+		return priceTimePolicy{}, nil
+	case FIFOOrdering:
+		return fifoPolicy{}, nil
+	case FairOrdering:
+		if senderLimit < 1 {
+			return nil, fmt.Errorf("invalid sender limit %d for %s ordering", senderLimit, name)
+		}
+		return fairPolicy{limit: senderLimit}, nil
+	default:
+		return nil, fmt.Errorf("unknown transaction ordering %q", name)
+	}
+}
+
+// priceTimePolicy orders the transactions by price-time priority.
+type priceTimePolicy struct{}
+
+func (priceTimePolicy) Name() string { return PriceTimeOrdering }
+
+func (priceTimePolicy) Less(a, b *txpool.LazyTransaction, tipA, tipB *big.Int) bool {
+	// If the prices are equal, use the time the transaction was first seen for
+	// deterministic sorting
+	if cmp := tipA.Cmp(tipB); cmp != 0 {
+		return cmp > 0
+	}
+	return earlier(a, b)
+}
+
+func (priceTimePolicy) SenderLimit() int { return 0 }
+
+// fifoPolicy orders the transactions by the time they were first seen.
+type fifoPolicy struct{}
+
+func (fifoPolicy) Name() string { return FIFOOrdering }
+
+func (fifoPolicy) Less(a, b *txpool.LazyTransaction, tipA, tipB *big.Int) bool {
+	return earlier(a, b)
+}
+
+func (fifoPolicy) SenderLimit() int { return 0 }
+
+// fairPolicy orders the transactions by price-time priority, capping the number
+// of transactions included per sender.
+type fairPolicy struct {
+	priceTimePolicy
+	limit int
+}
+
+func (fairPolicy) Name() string { return FairOrdering }
+
+func (p fairPolicy) SenderLimit() int { return p.limit }
+
+// earlier reports whether the transaction a was first seen before b, breaking
+// ties by hash for the ordering to be deterministic.
+func earlier(a, b *txpool.LazyTransaction) bool {
+	if !a.Time.Equal(b.Time) {
+		return a.Time.Before(b.Time)
+	}
+	return bytes.Compare(a.Hash[:], b.Hash[:]) < 0
+}
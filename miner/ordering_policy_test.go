@@ -0,0 +1,243 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// orderingTx is a transaction to be ordered, identified by its sender and nonce.
+type orderingTx struct {
+	sender int
+	nonce  uint64
+	price  int64
+	seen   int64
+}
+
+// orderTransactions signs the given transactions with the given number of keys
+// and returns the order they are included in by the policy, as indexes into
+// the input.
+func orderTransactions(t *testing.T, policy TxOrderingPolicy, senders int, input []orderingTx) []int {
+	t.Helper()
+
+	signer := types.HomesteadSigner{}
+	keys := make([]*ecdsa.PrivateKey, senders)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	var (
+		groups  = make(map[common.Address][]*txpool.LazyTransaction)
+		indexes = make(map[common.Hash]int)
+	)
+	for i, in := range input {
+		tx, err := types.SignTx(types.NewTransaction(in.nonce, common.Address{}, big.NewInt(100), 21000, big.NewInt(in.price), nil), signer, keys[in.sender])
+		if err != nil {
+			t.Fatalf("failed to sign transaction %d: %v", i, err)
+		}
+		tx.SetTime(time.Unix(in.seen, 0))
+
+		addr := crypto.PubkeyToAddress(keys[in.sender].PublicKey)
+		groups[addr] = append(groups[addr], &txpool.LazyTransaction{
+			Hash:      tx.Hash(),
+			Tx:        tx,
+			Time:      tx.Time(),
+			GasFeeCap: tx.GasFeeCap(),
+			GasTipCap: tx.GasTipCap(),
+			Gas:       tx.Gas(),
+		})
+		indexes[tx.Hash()] = i
+	}
+	var order []int
+	txset := newOrderedTransactions(signer, groups, nil, policy)
+	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
+		order = append(order, indexes[tx.Hash])
+		txset.Shift()
+	}
+	return order
+}
+
+func checkOrder(t *testing.T, have, want []int) {
+	t.Helper()
+
+	if len(have) != len(want) {
+		t.Fatalf("included transactions mismatch: have %v, want %v", have, want)
+	}
+	for i := range have {
+		if have[i] != want[i] {
+			t.Fatalf("transaction order mismatch: have %v, want %v", have, want)
+		}
+	}
+}
+
+// Tests that the price-time ordering prefers the better paying transactions,
+// and the earlier seen ones among equally priced transactions.
+func TestPriceTimeOrdering(t *testing.T) {
+	t.Parallel()
+
+	order := orderTransactions(t, priceTimePolicy{}, 3, []orderingTx{
+		{sender: 0, nonce: 0, price: 10, seen: 1},
+		{sender: 0, nonce: 1, price: 30, seen: 2},
+		{sender: 1, nonce: 0, price: 20, seen: 3},
+		{sender: 2, nonce: 0, price: 20, seen: 0},
+	})
+	checkOrder(t, order, []int{3, 2, 0, 1})
+}
+
+// Tests that the FIFO ordering includes the transactions in the order they were
+// seen regardless of their price, while still honouring the nonces.
+func TestFIFOOrdering(t *testing.T) {
+	t.Parallel()
+
+	order := orderTransactions(t, fifoPolicy{}, 3, []orderingTx{
+		{sender: 0, nonce: 0, price: 1, seen: 1},
+		{sender: 0, nonce: 1, price: 1, seen: 4},
+		{sender: 1, nonce: 0, price: 100, seen: 2},
+		{sender: 2, nonce: 0, price: 50, seen: 3},
+		{sender: 2, nonce: 1, price: 50, seen: 0},
+	})
+	checkOrder(t, order, []int{0, 2, 3, 4, 1})
+}
+
+// Tests that the fair ordering caps the number of transactions included from
+// each sender, leaving room for the others.
+func TestFairOrdering(t *testing.T) {
+	t.Parallel()
+
+	var input []orderingTx
+	for nonce := uint64(0); nonce < 5; nonce++ {
+		input = append(input, orderingTx{sender: 0, nonce: nonce, price: 100, seen: int64(nonce)})
+	}
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		input = append(input, orderingTx{sender: 1, nonce: nonce, price: 10, seen: int64(nonce)})
+	}
+	checkOrder(t, orderTransactions(t, fairPolicy{limit: 2}, 2, input), []int{0, 1, 5, 6})
+	checkOrder(t, orderTransactions(t, priceTimePolicy{}, 2, input), []int{0, 1, 2, 3, 4, 5, 6, 7})
+}
+
+// Tests that the transactions seen at the same time are ordered by their hash,
+// so that the ordering does not depend on the iteration order of the senders.
+func TestOrderingTieBreak(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []TxOrderingPolicy{priceTimePolicy{}, fifoPolicy{}} {
+		// The hashes are random, so check them against each other
+		var (
+			signer = types.HomesteadSigner{}
+			keys   = make([]*ecdsa.PrivateKey, 8)
+			groups = make(map[common.Address][]*txpool.LazyTransaction)
+		)
+		for i := range keys {
+			keys[i], _ = crypto.GenerateKey()
+			tx, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(100), 21000, big.NewInt(1), nil), signer, keys[i])
+			groups[crypto.PubkeyToAddress(keys[i].PublicKey)] = []*txpool.LazyTransaction{{
+				Hash:      tx.Hash(),
+				Tx:        tx,
+				GasFeeCap: tx.GasFeeCap(),
+				GasTipCap: tx.GasTipCap(),
+				Gas:       tx.Gas(),
+			}}
+		}
+		var prev *txpool.LazyTransaction
+		txset := newOrderedTransactions(signer, groups, nil, policy)
+		for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
+			if prev != nil && bytes.Compare(prev.Hash[:], tx.Hash[:]) > 0 {
+				t.Fatalf("%s ordering: tie not broken by hash: %x before %x", policy.Name(), prev.Hash, tx.Hash)
+			}
+			prev = tx
+			txset.Shift()
+		}
+	}
+}
+
+func TestNewTxOrderingPolicy(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		limit int
+		want  string
+		fail  bool
+	}{
+		{name: "", want: PriceTimeOrdering},
+		{name: PriceTimeOrdering, want: PriceTimeOrdering},
+		{name: FIFOOrdering, want: FIFOOrdering},
+		{name: FairOrdering, limit: 4, want: FairOrdering},
+		{name: FairOrdering, limit: 0, fail: true},
+		{name: "random", fail: true},
+	} {
+		policy, err := NewTxOrderingPolicy(tt.name, tt.limit)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("ordering %q (limit %d): expected error", tt.name, tt.limit)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ordering %q (limit %d): unexpected error: %v", tt.name, tt.limit, err)
+			continue
+		}
+		if policy.Name() != tt.want {
+			t.Errorf("ordering %q: policy mismatch: have %s, want %s", tt.name, policy.Name(), tt.want)
+		}
+		if policy.Name() == FairOrdering && policy.SenderLimit() != tt.limit {
+			t.Errorf("ordering %q: sender limit mismatch: have %d, want %d", tt.name, policy.SenderLimit(), tt.limit)
+		}
+	}
+}
+
+// Tests that the transactions skipped without being included do not consume the
+// allowance of their sender under the fair ordering.
+func TestFairOrderingSkip(t *testing.T) {
+	t.Parallel()
+
+	var (
+		signer = types.HomesteadSigner{}
+		key, _ = crypto.GenerateKey()
+		txs    []*txpool.LazyTransaction
+	)
+	for nonce := uint64(0); nonce < 4; nonce++ {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(100), 21000, big.NewInt(1), nil), signer, key)
+		txs = append(txs, &txpool.LazyTransaction{
+			Hash:      tx.Hash(),
+			Tx:        tx,
+			GasFeeCap: tx.GasFeeCap(),
+			GasTipCap: tx.GasTipCap(),
+			Gas:       tx.Gas(),
+		})
+	}
+	groups := map[common.Address][]*txpool.LazyTransaction{crypto.PubkeyToAddress(key.PublicKey): txs}
+	txset := newOrderedTransactions(signer, groups, nil, fairPolicy{limit: 2})
+
+	// Skip the first transaction, the next two are still allowed in
+	txset.Skip()
+	for i := 1; i <= 2; i++ {
+		if tx := txset.Peek(); tx == nil || tx.Hash != txs[i].Hash {
+			t.Fatalf("transaction %d not served: have %v", i, tx)
+		}
+		txset.Shift()
+	}
+	if tx := txset.Peek(); tx != nil {
+		t.Fatalf("sender allowance exceeded: have %x", tx.Hash)
+	}
+}
@@ -17,6 +17,7 @@
 package miner
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/binary"
 	"errors"
@@ -31,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // BuildPayloadArgs contains the provided parameters for building payload.
@@ -208,10 +210,15 @@ func (payload *Payload) ResolveFull() *engine.ExecutionPayloadEnvelope {
 	return payload.resolve(true)
 }
 
+// WaitFull blocks until the full block of the payload has been built. Payloads
+// built without the tx-pool are complete upon creation.
 func (payload *Payload) WaitFull() {
 	payload.lock.Lock()
 	defer payload.lock.Unlock()
-	payload.cond.Wait()
+
+	if payload.full == nil {
+		payload.cond.Wait()
+	}
 }
 
 func (payload *Payload) resolve(onlyFull bool) *engine.ExecutionPayloadEnvelope {
@@ -278,7 +285,11 @@ func (payload *Payload) stopBuilding() {
 }
 
 // buildPayload builds the payload according to the provided parameters.
-func (w *worker) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
+func (w *worker) buildPayload(ctx context.Context, args *BuildPayloadArgs) (*Payload, error) {
+	// Payload building outlives the request, retain its trace but not its
+	// cancellation
+	ctx = trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+
 	if args.NoTxPool { // don't start the background payload updating job if there is no tx pool to pull from
 		// Build the initial version with no transaction included. It should be fast
 		// enough to run. The empty payload can at least make sure there is something
@@ -300,6 +311,7 @@ func (w *worker) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
 			beaconRoot:          args.BeaconRoot,
 			solanaBlockNumbers:  args.SolanaBlockNumbers,
 			solanaTimestamps:    args.SolanaTimestamps,
+			ctx:                 ctx,
 		}
 		empty := w.getSealingBlock(emptyParams)
 		if empty.err != nil {
@@ -329,6 +341,7 @@ func (w *worker) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
 		beaconRoot:         args.BeaconRoot,
 		solanaBlockNumbers: args.SolanaBlockNumbers,
 		solanaTimestamps:   args.SolanaTimestamps,
+		ctx:                ctx,
 	}
 
 	// Since we skip building the empty block when using the tx pool, we need to explicitly
@@ -29,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -38,8 +39,11 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -84,6 +88,12 @@ var (
 	errBlockInterruptedByWrongGasUsed = errors.New("romeGasUsed has wrong dimension")
 )
 
+var (
+	// txConditionalRejectedMeter counts the conditional transactions skipped as
+	// their conditional does not hold against the block being built.
+	txConditionalRejectedMeter = metrics.NewRegisteredMeter("miner/transactionconditional/rejected", nil)
+)
+
 // environment is the worker's current environment and holds all
 // information of the sealing block generation.
 type environment struct {
@@ -103,6 +113,9 @@ type environment struct {
 
 	solanaBlockNumbers []*uint64
 	solanaTimestamps   []*int64
+
+	ctx               context.Context // Trace context the transactions are executed under
+	enforceFootprints bool            // Whether the footprint mismatch policy applies to the transactions
 }
 
 // copy creates a deep copy of environment.
@@ -118,6 +131,8 @@ func (env *environment) copy() *environment {
 		receipts:            copyReceipts(env.receipts),
 		solanaBlockNumbers:  env.solanaBlockNumbers,
 		solanaTimestamps:    env.solanaTimestamps,
+		ctx:                 env.ctx,
+		enforceFootprints:   env.enforceFootprints,
 	}
 	if env.gasPool != nil {
 		gasPool := *env.gasPool
@@ -247,6 +262,10 @@ type worker struct {
 	// payload in proof-of-stake stage.
 	recommit time.Duration
 
+	// ordering is the policy ordering the pending transactions of different
+	// accounts into the blocks built.
+	ordering TxOrderingPolicy
+
 	// External functions
 	isLocalBlock func(header *types.Header) bool // Function used to determine whether the specified block is mined by local miner.
 
@@ -304,6 +323,14 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 	}
 	worker.newpayloadTimeout = newpayloadTimeout
 
+	// Sanitize the transaction ordering policy.
+	ordering, err := NewTxOrderingPolicy(worker.config.TxOrdering, worker.config.TxOrderingSenderLimit)
+	if err != nil {
+		log.Warn("Sanitizing invalid transaction ordering to default", "provided", worker.config.TxOrdering, "updated", PriceTimeOrdering, "err", err)
+		ordering = priceTimePolicy{}
+	}
+	worker.ordering = ordering
+
 	worker.wg.Add(4)
 	go worker.mainLoop()
 	go worker.newWorkLoop(recommit)
@@ -597,7 +624,7 @@ func (w *worker) mainLoop() {
 						BlobGas:   tx.BlobGas(),
 					})
 				}
-				txset := newTransactionsByPriceAndNonce(w.current.signer, txs, w.current.header.BaseFee)
+				txset := newOrderedTransactions(w.current.signer, txs, w.current.header.BaseFee, w.ordering)
 				tcount := w.current.tcount
 				w.commitTransactions(w.current, txset, nil)
 
@@ -777,6 +804,7 @@ func (w *worker) makeEnv(parent *types.Header, header *types.Header, genParams *
 		gasUsed:           genParams.gasUsed,
 		solanaBlockNumbers: genParams.solanaBlockNumbers,
 		solanaTimestamps:   genParams.solanaTimestamps,
+		ctx:                context.Background(),
 	}
 	// Keep track of transactions which return errors so they can be removed
 	env.tcount = 0
@@ -852,7 +880,9 @@ func (w *worker) applyTransaction(env *environment, tx *types.Transaction, index
 		solanaTimestamp = env.solanaTimestamps[index]
 	}
 
-	receipt, err := core.ApplyTransactionWithSolana(w.chainConfig, w.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, *w.chain.GetVMConfig(), romeGasUsed, footPrint, romeGasPrice, solanaBlockNumber, solanaTimestamp)
+	vmConfig := *w.chain.GetVMConfig()
+	vmConfig.EnforceFootprints = env.enforceFootprints
+	receipt, err := core.ApplyTransactionWithSolana(env.ctx, w.chainConfig, w.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, vmConfig, romeGasUsed, footPrint, romeGasPrice, solanaBlockNumber, solanaTimestamp)
 
 	if err != nil {
 		env.state.RevertToSnapshot(snap)
@@ -861,7 +891,7 @@ func (w *worker) applyTransaction(env *environment, tx *types.Transaction, index
 	return receipt, err
 }
 
-func (w *worker) commitTransactions(env *environment, txs *transactionsByPriceAndNonce, interrupt *atomic.Int32) error {
+func (w *worker) commitTransactions(env *environment, txs *orderedTransactions, interrupt *atomic.Int32) error {
 	if env.gasPool == nil {
 		env.gasPool = new(core.GasPool).AddGas(math.MaxUint64)
 	}
@@ -914,6 +944,20 @@ func (w *worker) commitTransactions(env *environment, txs *transactionsByPriceAn
 			txs.Pop()
 			continue
 		}
+		// Check the inclusion constraints of conditional transactions against the
+		// block being built, including the state changes of the preceding txs.
+		if cond := tx.Conditional(); cond != nil {
+			err := env.header.CheckTransactionConditional(cond)
+			if err == nil {
+				err = env.state.CheckTransactionConditional(cond)
+			}
+			if err != nil {
+				log.Debug("Transaction conditional failed, account skipped", "hash", ltx.Hash, "err", err)
+				txConditionalRejectedMeter.Mark(1)
+				txs.Pop()
+				continue
+			}
+		}
 		// Start executing the transaction
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 
@@ -932,7 +976,7 @@ func (w *worker) commitTransactions(env *environment, txs *transactionsByPriceAn
 		case errors.Is(err, core.ErrNonceTooLow):
 			// New head notification data race between the transaction pool and miner, shift
 			log.Trace("Skipping transaction with low nonce", "hash", ltx.Hash, "sender", from, "nonce", tx.Nonce())
-			txs.Shift()
+			txs.Skip()
 
 		case errors.Is(err, nil):
 			// Everything ok, collect the logs and shift in the next transaction from the same account
@@ -985,6 +1029,8 @@ type generateParams struct {
 	gasLimit  *uint64            // Optional gas limit override
 	interrupt *atomic.Int32      // Optional interruption signal to pass down to worker.generateWork
 	isUpdate  bool               // Optional flag indicating that this is building a discardable update
+
+	ctx context.Context // Optional trace context of the request the block is built for
 }
 
 // validateParams validates the given parameters.
@@ -1033,7 +1079,7 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 	header := &types.Header{
 		ParentHash: parent.Hash(),
 		Number:     new(big.Int).Add(parent.Number, common.Big1),
-		GasLimit:   txpool.EffectiveGasLimit(w.chainConfig, parent.GasLimit),
+		GasLimit:   txpool.BlockGasLimit(w.chainConfig, parent, genParams.timestamp, genParams.gasLimit),
 		Time:       genParams.timestamp,
 		Coinbase:   genParams.coinbase,
 	}
@@ -1044,13 +1090,13 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 	header.MixDigest = common.Hash{}
 	// Set baseFee and GasLimit if we are on an EIP-1559 chain
 	if w.chainConfig.IsLondon(header.Number) {
-		header.BaseFee = big.NewInt(0)
+		header.BaseFee = eip1559.RomeBaseFee(w.chainConfig, header.Number)
 		if !w.chainConfig.IsLondon(parent.Number) {
 			parentGasLimit := parent.GasLimit * w.chainConfig.ElasticityMultiplier()
 			header.GasLimit = core.CalcGasLimit(parentGasLimit, w.config.GasCeil)
 		}
 	}
-	header.GasLimit = txpool.EffectiveGasLimit(w.chainConfig, parent.GasLimit)
+	header.GasLimit = txpool.BlockGasLimit(w.chainConfig, parent, genParams.timestamp, genParams.gasLimit)
 	// Apply EIP-4844, EIP-4788.
 	if w.chainConfig.IsCancun(header.Number, header.Time) {
 		var excessBlobGas uint64
@@ -1102,13 +1148,13 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) err
 
 	// Fill the block with all available pending transactions.
 	if len(localTxs) > 0 {
-		txs := newTransactionsByPriceAndNonce(env.signer, localTxs, env.header.BaseFee)
+		txs := newOrderedTransactions(env.signer, localTxs, env.header.BaseFee, w.ordering)
 		if err := w.commitTransactions(env, txs, interrupt); err != nil {
 			return err
 		}
 	}
 	if len(remoteTxs) > 0 {
-		txs := newTransactionsByPriceAndNonce(env.signer, remoteTxs, env.header.BaseFee)
+		txs := newOrderedTransactions(env.signer, remoteTxs, env.header.BaseFee, w.ordering)
 		if err := w.commitTransactions(env, txs, interrupt); err != nil {
 			return err
 		}
@@ -1118,11 +1164,27 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) err
 
 // generateWork generates a sealing block based on the given parameters.
 func (w *worker) generateWork(genParams *generateParams) *newPayloadResult {
+	ctx := genParams.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := log.GetTracer().Start(ctx, "generateWork",
+		trace.WithAttributes(
+			attribute.Int("forced_txs", len(genParams.txs)),
+			attribute.Bool("no_txs", genParams.noTxs),
+			attribute.Bool("update", genParams.isUpdate),
+		))
+	defer span.End()
+
 	work, err := w.prepareWork(genParams)
 	if err != nil {
 		return &newPayloadResult{err: err}
 	}
 	defer work.discard()
+	work.ctx = ctx
+
+	// Payloads are built for the engine API, the footprint mismatch policy applies
+	work.enforceFootprints = true
 	if work.gasPool == nil {
 		work.gasPool = new(core.GasPool).AddGas(work.header.GasLimit)
 	}
@@ -377,6 +377,9 @@ type ChainConfig struct {
 
 	// Optimism config, nil if not active
 	Optimism *OptimismConfig `json:"optimism,omitempty"`
+
+	// Rome config, nil if not active
+	Rome *RomeConfig `json:"rome,omitempty"`
 }
 
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
@@ -410,6 +413,42 @@ func (o *OptimismConfig) String() string {
 	return "optimism"
 }
 
+// RomeConfig is the Rome specific chain config.
+type RomeConfig struct {
+	// FootprintForks schedules upgrades of the state footprint scheme, ordered
+	// by activation time. Blocks before the first upgrade use version 1.
+	FootprintForks []RomeFootprintFork `json:"footprintForks,omitempty"`
+
+	// BlockhashTime switches BLOCKHASH from the synthetic keccak256(slot) hash to
+	// the Solana blockhashes delivered by the engine API (nil = no fork).
+	BlockhashTime *uint64 `json:"blockhashTime,omitempty"`
+
+	// GasLimit is the gas limit of blocks built past GasLimitTime without one
+	// set in the payload attributes (nil = inherit the gas limit of the parent
+	// block).
+	GasLimit *uint64 `json:"gasLimit,omitempty"`
+
+	// GasLimitTime switches the effective gas limit of blocks from the fixed
+	// proxy of the Rome-EVM block capacity to the gas limit in their headers,
+	// set from the payload attributes (nil = no fork).
+	GasLimitTime *uint64 `json:"gasLimitTime,omitempty"`
+}
+
+// LatestFootprintVersion is the latest state footprint scheme version known to
+// this node. Upgrades to later versions are rejected by the config checks.
+const LatestFootprintVersion = 1
+
+// RomeFootprintFork activates a state footprint scheme version at a timestamp.
+type RomeFootprintFork struct {
+	Version uint64 `json:"version"`
+	Time    uint64 `json:"time"`
+}
+
+// String implements the stringer interface, returning the rome config details.
+func (r *RomeConfig) String() string {
+	return "rome"
+}
+
 // Description returns a human-readable description of ChainConfig.
 func (c *ChainConfig) Description() string {
 	var banner string
@@ -513,6 +552,17 @@ func (c *ChainConfig) Description() string {
 	if c.InteropTime != nil {
 		banner += fmt.Sprintf(" - Interop:                     @%-10v\n", *c.InteropTime)
 	}
+	if c.Rome != nil {
+		for _, fork := range c.Rome.FootprintForks {
+			banner += fmt.Sprintf(" - Rome footprint v%-2d:          @%-10v\n", fork.Version, fork.Time)
+		}
+		if c.Rome.BlockhashTime != nil {
+			banner += fmt.Sprintf(" - Rome Solana blockhash:       @%-10v\n", *c.Rome.BlockhashTime)
+		}
+		if c.Rome.GasLimitTime != nil {
+			banner += fmt.Sprintf(" - Rome header gas limit:       @%-10v\n", *c.Rome.GasLimitTime)
+		}
+	}
 	return banner
 }
 
@@ -664,6 +714,65 @@ func (c *ChainConfig) IsOptimismPreBedrock(num *big.Int) bool {
 	return c.IsOptimism() && !c.IsBedrock(num)
 }
 
+// IsRomeBlockhash returns whether time is either equal to the Rome Solana
+// blockhash fork time or greater.
+func (c *ChainConfig) IsRomeBlockhash(time uint64) bool {
+	return isTimestampForked(c.romeBlockhashTime(), time)
+}
+
+// romeBlockhashTime returns the Rome Solana blockhash fork time, if scheduled.
+func (c *ChainConfig) romeBlockhashTime() *uint64 {
+	if c.Rome == nil {
+		return nil
+	}
+	return c.Rome.BlockhashTime
+}
+
+// IsRomeGasLimit returns whether time is either equal to the Rome header gas
+// limit fork time or greater.
+func (c *ChainConfig) IsRomeGasLimit(time uint64) bool {
+	return isTimestampForked(c.romeGasLimitTime(), time)
+}
+
+// romeGasLimitTime returns the Rome header gas limit fork time, if scheduled.
+func (c *ChainConfig) romeGasLimitTime() *uint64 {
+	if c.Rome == nil {
+		return nil
+	}
+	return c.Rome.GasLimitTime
+}
+
+// FootprintVersion returns the version of the state footprint scheme active
+// at the given block time.
+func (c *ChainConfig) FootprintVersion(time uint64) uint64 {
+	if fork := c.footprintFork(time); fork != nil {
+		return fork.Version
+	}
+	return 1
+}
+
+// footprintFork returns the last footprint scheme upgrade activated at the
+// given block time, or nil if the initial scheme is still active.
+func (c *ChainConfig) footprintFork(time uint64) *RomeFootprintFork {
+	if forks := c.footprintForks(time); len(forks) > 0 {
+		return &forks[len(forks)-1]
+	}
+	return nil
+}
+
+// footprintForks returns the footprint scheme upgrades activated at the given
+// block time, in activation order.
+func (c *ChainConfig) footprintForks(time uint64) []RomeFootprintFork {
+	if c.Rome == nil {
+		return nil
+	}
+	var n int
+	for n < len(c.Rome.FootprintForks) && c.Rome.FootprintForks[n].Time <= time {
+		n++
+	}
+	return c.Rome.FootprintForks[:n]
+}
+
 // CheckCompatible checks whether scheduled fork transitions have been imported
 // with a mismatching chain configuration.
 func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64, time uint64) *ConfigCompatError {
@@ -754,6 +863,22 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	if c.Rome != nil {
+		last := RomeFootprintFork{Version: 1}
+		for _, fork := range c.Rome.FootprintForks {
+			if fork.Version > LatestFootprintVersion {
+				return fmt.Errorf("unsupported footprint version %d, latest known is %d", fork.Version, LatestFootprintVersion)
+			}
+			if fork.Version <= last.Version {
+				return fmt.Errorf("unsupported footprint fork ordering: v%d scheduled after v%d", fork.Version, last.Version)
+			}
+			if fork.Time < last.Time {
+				return fmt.Errorf("unsupported footprint fork ordering: v%d enabled at timestamp %v, but v%d enabled at timestamp %v",
+					last.Version, last.Time, fork.Version, fork.Time)
+			}
+			last = fork
+		}
+	}
 	return nil
 }
 
@@ -825,6 +950,27 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.VerkleTime, newcfg.VerkleTime, headTimestamp) {
 		return newTimestampCompatError("Verkle fork timestamp", c.VerkleTime, newcfg.VerkleTime)
 	}
+	if isForkTimestampIncompatible(c.romeBlockhashTime(), newcfg.romeBlockhashTime(), headTimestamp) {
+		return newTimestampCompatError("Rome Solana blockhash fork timestamp", c.romeBlockhashTime(), newcfg.romeBlockhashTime())
+	}
+	if isForkTimestampIncompatible(c.romeGasLimitTime(), newcfg.romeGasLimitTime(), headTimestamp) {
+		return newTimestampCompatError("Rome header gas limit fork timestamp", c.romeGasLimitTime(), newcfg.romeGasLimitTime())
+	}
+	// Every footprint scheme upgrade activated by the head must be unchanged, not
+	// only the latest one, as earlier blocks were processed with the others
+	stored, updated := c.footprintForks(headTimestamp), newcfg.footprintForks(headTimestamp)
+	for i := 0; i < len(stored) || i < len(updated); i++ {
+		var storedTime, newTime *uint64
+		if i < len(stored) {
+			storedTime = &stored[i].Time
+		}
+		if i < len(updated) {
+			newTime = &updated[i].Time
+		}
+		if storedTime == nil || newTime == nil || stored[i] != updated[i] {
+			return newTimestampCompatError("Rome footprint fork timestamp", storedTime, newTime)
+		}
+	}
 	return nil
 }
 
@@ -983,6 +1129,7 @@ type Rules struct {
 	IsVerkle                                                bool
 	IsOptimismBedrock, IsOptimismRegolith                   bool
 	IsOptimismCanyon                                        bool
+	IsRomeBlockhash                                         bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -1012,5 +1159,7 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 		IsOptimismBedrock:  c.IsOptimismBedrock(num),
 		IsOptimismRegolith: c.IsOptimismRegolith(timestamp),
 		IsOptimismCanyon:   c.IsOptimismCanyon(timestamp),
+		// Rome
+		IsRomeBlockhash: c.IsRomeBlockhash(timestamp),
 	}
 }
@@ -156,3 +156,70 @@ func TestConfigRulesRegolith(t *testing.T) {
 		t.Errorf("expected %v to be regolith", stamp)
 	}
 }
+
+func TestFootprintVersion(t *testing.T) {
+	c := &ChainConfig{}
+	if v := c.FootprintVersion(100); v != 1 {
+		t.Fatalf("default footprint version: have %d, want 1", v)
+	}
+	c.Rome = &RomeConfig{FootprintForks: []RomeFootprintFork{{Version: 2, Time: 10}, {Version: 3, Time: 20}}}
+	for _, tt := range []struct{ time, version uint64 }{{0, 1}, {9, 1}, {10, 2}, {19, 2}, {20, 3}, {1000, 3}} {
+		if v := c.FootprintVersion(tt.time); v != tt.version {
+			t.Errorf("footprint version at %d: have %d, want %d", tt.time, v, tt.version)
+		}
+	}
+	// Upgrades to versions unknown to the node must be rejected
+	if err := c.CheckConfigForkOrder(); err == nil {
+		t.Fatal("unknown footprint versions accepted")
+	}
+	c.Rome.FootprintForks = []RomeFootprintFork{{Version: LatestFootprintVersion, Time: 10}}
+	if err := c.CheckConfigForkOrder(); err == nil {
+		t.Fatal("footprint fork to the initial version accepted")
+	}
+	c.Rome.FootprintForks = nil
+	if err := c.CheckConfigForkOrder(); err != nil {
+		t.Fatalf("valid footprint forks rejected: %v", err)
+	}
+	// Rescheduling an activated footprint fork must be incompatible
+	stored := &ChainConfig{Rome: &RomeConfig{FootprintForks: []RomeFootprintFork{{Version: 2, Time: 10}}}}
+	if err := stored.CheckCompatible(&ChainConfig{}, 0, 15); err == nil || err.RewindToTime != 9 {
+		t.Fatalf("unexpected compatibility error: %v", err)
+	}
+	if err := stored.CheckCompatible(&ChainConfig{}, 0, 5); err != nil {
+		t.Fatalf("unexpected compatibility error before fork: %v", err)
+	}
+	// Changing an activated footprint fork other than the latest one must be
+	// incompatible too
+	stored = &ChainConfig{Rome: &RomeConfig{FootprintForks: []RomeFootprintFork{{Version: 2, Time: 10}, {Version: 3, Time: 20}}}}
+	updated := &ChainConfig{Rome: &RomeConfig{FootprintForks: []RomeFootprintFork{{Version: 2, Time: 12}, {Version: 3, Time: 20}}}}
+	if err := stored.CheckCompatible(updated, 0, 25); err == nil || err.RewindToTime != 9 {
+		t.Fatalf("unexpected compatibility error: %v", err)
+	}
+	updated = &ChainConfig{Rome: &RomeConfig{FootprintForks: []RomeFootprintFork{{Version: 2, Time: 10}, {Version: 3, Time: 30}}}}
+	if err := stored.CheckCompatible(updated, 0, 25); err == nil || err.RewindToTime != 19 {
+		t.Fatalf("unexpected compatibility error: %v", err)
+	}
+	if err := stored.CheckCompatible(updated, 0, 15); err != nil {
+		t.Fatalf("unexpected compatibility error before changed fork: %v", err)
+	}
+}
+
+func TestRomeBlockhashFork(t *testing.T) {
+	c := &ChainConfig{}
+	if c.IsRomeBlockhash(100) {
+		t.Fatal("Solana blockhash fork active without Rome config")
+	}
+	c.Rome = &RomeConfig{BlockhashTime: newUint64(10)}
+	if c.IsRomeBlockhash(9) || !c.IsRomeBlockhash(10) {
+		t.Fatal("Solana blockhash fork activation mismatch")
+	}
+	if r := c.Rules(big.NewInt(0), true, 10); !r.IsRomeBlockhash {
+		t.Fatal("Solana blockhash fork missing from rules")
+	}
+	if err := c.CheckCompatible(&ChainConfig{}, 0, 15); err == nil || err.RewindToTime != 9 {
+		t.Fatalf("unexpected compatibility error: %v", err)
+	}
+	if err := c.CheckCompatible(&ChainConfig{}, 0, 5); err != nil {
+		t.Fatalf("unexpected compatibility error before fork: %v", err)
+	}
+}
@@ -23,6 +23,10 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func confirmStatusCode(t *testing.T, got, want int) {
@@ -241,3 +245,43 @@ func TestNewContextWithHeaders(t *testing.T) {
 		t.Error("call failed:", err)
 	}
 }
+
+type traceService struct{}
+
+func (traceService) TraceID(ctx context.Context) string {
+	return trace.SpanContextFromContext(ctx).TraceID().String()
+}
+
+// Tests that the trace context in the headers of a request is propagated into
+// the context of the method call.
+func TestHTTPTraceContextPropagation(t *testing.T) {
+	defer otel.SetTextMapPropagator(otel.GetTextMapPropagator())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	s := NewServer()
+	defer s.Stop()
+	s.RegisterName("test", traceService{})
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	c, err := DialHTTP(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	var id string
+	if err := c.Call(&id, "test_traceID"); err != nil {
+		t.Fatal(err)
+	}
+	if id != (trace.TraceID{}).String() {
+		t.Fatalf("unexpected trace without trace context: %s", id)
+	}
+	c.SetHeader("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err := c.Call(&id, "test_traceID"); err != nil {
+		t.Fatal(err)
+	}
+	if id != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("trace context not propagated: have %s", id)
+	}
+}
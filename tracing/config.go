@@ -0,0 +1,36 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracing
+
+// Config contains the configuration of the OpenTelemetry trace export.
+type Config struct {
+	Enabled     bool              `toml:",omitempty"`
+	Endpoint    string            `toml:",omitempty"` // OTLP gRPC collector address (host:port)
+	ServiceName string            `toml:",omitempty"` // Service name the spans are reported under
+	SampleRatio float64           `toml:",omitempty"` // Fraction of root traces to sample
+	Insecure    bool              `toml:",omitempty"` // Disable TLS towards the collector
+	CACert      string            `toml:",omitempty"` // CA certificate to verify the collector with, system roots if empty
+	Headers     map[string]string `toml:",omitempty"` // Headers sent along each export request
+}
+
+// DefaultConfig is the default config for tracing used in go-ethereum.
+var DefaultConfig = Config{
+	Enabled:     false,
+	Endpoint:    "localhost:4317",
+	ServiceName: "op-geth",
+	SampleRatio: 1,
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package tracing implements the export of OpenTelemetry traces to a collector.
+//
+// Spans are recorded through log.GetTracer and discarded until the service is
+// started. Trace context received in the headers of RPC requests is propagated
+// into the spans of the request, so traces started by upstream services, such
+// as the Rome indexer, continue through block building, payload import and
+// transaction execution.
+package tracing
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"google.golang.org/grpc/credentials"
+)
+
+// shutdownTimeout is the maximum time to spend flushing pending spans to the
+// collector on shutdown.
+const shutdownTimeout = 5 * time.Second
+
+// Service is a node lifecycle exporting the spans recorded by the node to an
+// OpenTelemetry collector.
+type Service struct {
+	config   Config
+	version  string
+	provider *sdktrace.TracerProvider
+}
+
+// New creates the trace export service and registers it with the node.
+func New(stack *node.Node, config *Config) (*Service, error) {
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return nil, fmt.Errorf("invalid trace sample ratio %v, must be within [0, 1]", config.SampleRatio)
+	}
+	if config.Endpoint == "" {
+		return nil, errors.New("no trace collector endpoint configured")
+	}
+	s := &Service{config: *config, version: stack.Config().Version}
+	stack.RegisterLifecycle(s)
+	return s, nil
+}
+
+// Start implements node.Lifecycle, installing the tracer provider and trace
+// context propagator globally. The connection to the collector is established
+// in the background, spans are queued until it is available.
+func (s *Service) Start() error {
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(s.config.Endpoint)}
+	if s.config.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	} else {
+		creds := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+		if s.config.CACert != "" {
+			var err error
+			if creds, err = credentials.NewClientTLSFromFile(s.config.CACert, ""); err != nil {
+				return fmt.Errorf("failed to load trace collector CA certificate: %w", err)
+			}
+		}
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(creds))
+	}
+	if len(s.config.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(s.config.Headers))
+	}
+	exporter, err := otlptrace.New(context.Background(), otlptracegrpc.NewClient(opts...))
+	if err != nil {
+		return fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+	s.provider = newProvider(&s.config, s.version, sdktrace.NewBatchSpanProcessor(
+		exporter,
+		sdktrace.WithMaxQueueSize(16000),
+		sdktrace.WithMaxExportBatchSize(1024),
+		sdktrace.WithBatchTimeout(2*time.Second),
+	))
+	install(s.provider)
+
+	log.Info("Started trace export", "endpoint", s.config.Endpoint, "ratio", s.config.SampleRatio, "tls", !s.config.Insecure)
+	return nil
+}
+
+// Stop implements node.Lifecycle, flushing the pending spans to the collector.
+func (s *Service) Stop() error {
+	if s.provider == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := s.provider.Shutdown(ctx); err != nil {
+		log.Warn("Failed to flush pending spans", "err", err)
+	}
+	log.Info("Stopped trace export")
+	return nil
+}
+
+// newProvider creates a tracer provider feeding the spans of sampled traces to
+// the given processor. Traces are sampled by the configured ratio, unless the
+// remote parent of the span carries a sampling decision already.
+func newProvider(config *Config, version string, processor sdktrace.SpanProcessor) *sdktrace.TracerProvider {
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String(config.ServiceName),
+		semconv.ServiceVersionKey.String(version),
+	)
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithResource(res),
+		sdktrace.WithSpanProcessor(processor),
+	)
+}
+
+// install sets the tracer provider spans are recorded with and enables the
+// propagation of W3C trace context and baggage.
+func install(provider *sdktrace.TracerProvider) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/node"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+)
+
+// Tests that invalid configs are rejected when creating the service.
+func TestNewInvalidConfig(t *testing.T) {
+	stack, err := node.New(&node.Config{})
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	defer stack.Close()
+
+	for _, config := range []Config{
+		{Endpoint: "localhost:4317", SampleRatio: -0.1},
+		{Endpoint: "localhost:4317", SampleRatio: 1.1},
+		{SampleRatio: 1},
+	} {
+		if _, err := New(stack, &config); err == nil {
+			t.Errorf("config %+v: expected error", config)
+		}
+	}
+	if _, err := New(stack, &DefaultConfig); err != nil {
+		t.Errorf("default config rejected: %v", err)
+	}
+}
+
+// Tests that root spans are sampled by the configured ratio, while spans
+// continuing a remote trace follow the sampling decision of the caller.
+func TestSampling(t *testing.T) {
+	headers := propagation.HeaderCarrier{}
+	headers.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	remote := propagation.TraceContext{}.Extract(context.Background(), headers)
+
+	tests := []struct {
+		ratio    float64
+		ctx      context.Context
+		recorded bool
+	}{
+		{ratio: 1, ctx: context.Background(), recorded: true},
+		{ratio: 0, ctx: context.Background(), recorded: false},
+		{ratio: 0, ctx: remote, recorded: true},
+	}
+	for i, tt := range tests {
+		recorder := tracetest.NewSpanRecorder()
+		config := DefaultConfig
+		config.SampleRatio = tt.ratio
+		provider := newProvider(&config, "v1.0.0", recorder)
+
+		_, span := provider.Tracer("test").Start(tt.ctx, "span")
+		span.End()
+
+		spans := recorder.Ended()
+		if recorded := len(spans) == 1; recorded != tt.recorded {
+			t.Fatalf("test %d: span recorded %v, want %v", i, recorded, tt.recorded)
+		}
+		if !tt.recorded {
+			continue
+		}
+		if tt.ctx == remote && spans[0].SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("test %d: remote trace not continued, trace id %s", i, spans[0].SpanContext().TraceID())
+		}
+		var name string
+		for _, attr := range spans[0].Resource().Attributes() {
+			if attr.Key == semconv.ServiceNameKey {
+				name = attr.Value.AsString()
+			}
+		}
+		if name != config.ServiceName {
+			t.Errorf("test %d: service name mismatch: have %q, want %q", i, name, config.ServiceName)
+		}
+	}
+}
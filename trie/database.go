@@ -263,6 +263,18 @@ func (db *Database) Recoverable(root common.Hash) (bool, error) {
 	return pdb.Recoverable(root), nil
 }
 
+// HistoricReader returns a reader serving the flat state of a historical state,
+// which is no longer available in the database, from the retained state
+// histories, reading at most limit of them (0 = unlimited). It's only supported
+// by path-based database and will return an error for others.
+func (db *Database) HistoricReader(root common.Hash, limit uint64) (*pathdb.HistoricReader, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return nil, errors.New("not supported")
+	}
+	return pdb.HistoricReader(root, &trieLoader{db: db}, limit)
+}
+
 // Disable deactivates the database and invalidates all available state layers
 // as stale to prevent access to the persistent state, which is in the syncing
 // stage.
@@ -86,10 +86,11 @@ type layer interface {
 
 // Config contains the settings for database.
 type Config struct {
-	StateHistory   uint64 // Number of recent blocks to maintain state history for
-	CleanCacheSize int    // Maximum memory allowance (in bytes) for caching clean nodes
-	DirtyCacheSize int    // Maximum memory allowance (in bytes) for caching dirty nodes
-	ReadOnly       bool   // Flag whether the database is opened in read only mode.
+	StateHistory    uint64 // Number of recent blocks to maintain state history for
+	HistoryLookback uint64 // Number of state histories searched to serve historical state (0 = all retained)
+	CleanCacheSize  int    // Maximum memory allowance (in bytes) for caching clean nodes
+	DirtyCacheSize  int    // Maximum memory allowance (in bytes) for caching dirty nodes
+	ReadOnly        bool   // Flag whether the database is opened in read only mode.
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -105,9 +106,10 @@ func (c *Config) sanitize() *Config {
 
 // Defaults contains default settings for Ethereum mainnet.
 var Defaults = &Config{
-	StateHistory:   params.FullImmutabilityThreshold,
-	CleanCacheSize: defaultCleanSize,
-	DirtyCacheSize: DefaultBufferSize,
+	StateHistory:    params.FullImmutabilityThreshold,
+	HistoryLookback: params.FullImmutabilityThreshold,
+	CleanCacheSize:  defaultCleanSize,
+	DirtyCacheSize:  DefaultBufferSize,
 }
 
 // ReadOnly is the config in order to open database in read only mode.
@@ -46,6 +46,23 @@ var (
 	// a destination without associated state history available.
 	errStateUnrecoverable = errors.New("state is unrecoverable")
 
+	// errStateHistoryPruned is returned if historical state is requested
+	// whose state histories are no longer retained.
+	errStateHistoryPruned = errors.New("state history pruned")
+
+	// errIncompleteHistory is returned if historical storage is requested
+	// which was wiped by a large contract destruction without being recorded
+	// in the state history.
+	errIncompleteHistory = errors.New("incomplete state history")
+
+	// errHistoricLookback is returned if historical state is requested which
+	// lies further below the disk layer than the state histories searched.
+	errHistoricLookback = errors.New("historical state beyond lookback")
+
+	// errHistoricReadLimit is returned if historical state is requested after
+	// its reader exhausted the state histories it is allowed to read.
+	errHistoricReadLimit = errors.New("historical state read limit exceeded")
+
 	// errUnexpectedNode is returned if the requested node with specified path is
 	// not hash matched with expectation.
 	errUnexpectedNode = errors.New("unexpected node")
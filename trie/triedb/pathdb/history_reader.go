@@ -0,0 +1,280 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>
+
+package pathdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie/triestate"
+)
+
+// HistoricReader serves the flat state of a historical state, which is no
+// longer available in the layer tree, from the persisted state histories.
+//
+// Each state history records the original values of the states mutated in
+// the associated state transition. The value of a state at the historical
+// state with id n is therefore the original value recorded by the first
+// history in range [n+1, disklayer.ID] mutating it. The states which are not
+// mutated within this range are unchanged since, and are resolved from the
+// tries of the disk layer.
+//
+// The lookups are linear in the number of state histories after the requested
+// state, as no index of the mutations in the histories is maintained. They are
+// therefore bounded to the states at most the configured lookback of histories
+// below the disk layer, and the total number of histories read by a reader can
+// be capped by its user.
+type HistoricReader struct {
+	db     *Database
+	id     uint64               // State id of the historical state
+	loader triestate.TrieLoader // Loader of the disk layer tries for the unmutated states
+
+	limit uint64        // Maximum number of state histories to read, 0 for unlimited
+	reads atomic.Uint64 // Number of state histories read so far
+}
+
+// HistoricReader constructs a reader of the historical state with the given
+// root. The state must be a canonical state persisted below the disk layer,
+// with all the state histories after it still retained. The reader fails the
+// lookups once it has read more than limit state histories (0 = unlimited).
+func (db *Database) HistoricReader(root common.Hash, loader triestate.TrieLoader, limit uint64) (*HistoricReader, error) {
+	if db.freezer == nil {
+		return nil, errors.New("state history is not available")
+	}
+	root = types.TrieRootHash(root)
+	id := rawdb.ReadStateID(db.diskdb, root)
+	if id == nil {
+		return nil, fmt.Errorf("state %#x is not available", root)
+	}
+	dl := db.tree.bottom()
+	if *id > dl.stateID() {
+		return nil, fmt.Errorf("state %#x is not historical", root)
+	}
+	r := &HistoricReader{db: db, id: *id, loader: loader, limit: limit}
+	if err := r.checkTail(); err != nil {
+		return nil, err
+	}
+	if err := r.checkLookback(dl); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// checkLookback ensures that the state histories to search on top of the given
+// disk layer are within the lookback.
+func (r *HistoricReader) checkLookback(dl *diskLayer) error {
+	lookback := r.db.config.HistoryLookback
+	if lookback != 0 && dl.stateID()-r.id > lookback {
+		return fmt.Errorf("%w: state id %d, disk layer %d, lookback %d", errHistoricLookback, r.id, dl.stateID(), lookback)
+	}
+	return nil
+}
+
+// checkTail ensures that the state histories required by the reader have not
+// been pruned.
+func (r *HistoricReader) checkTail() error {
+	tail, err := r.db.freezer.Tail()
+	if err != nil {
+		return err
+	}
+	if r.id < tail {
+		return fmt.Errorf("%w: state id %d, oldest available %d", errStateHistoryPruned, r.id, tail)
+	}
+	return nil
+}
+
+// Account retrieves the account with the given address in the historical
+// state, encoded in the slim RLP format. Nil is returned if the account was
+// not present.
+func (r *HistoricReader) Account(addr common.Address) ([]byte, error) {
+	defer func(start time.Time) { historicalAccountReadTimer.UpdateSince(start) }(time.Now())
+
+	return r.resolve(func(id uint64) ([]byte, bool, error) {
+		return r.historyAccount(id, addr)
+	}, func(dl *diskLayer) ([]byte, error) {
+		acct, err := r.diskAccount(dl, addr)
+		if acct == nil || err != nil {
+			return nil, err
+		}
+		return types.SlimAccountRLP(*acct), nil
+	})
+}
+
+// Storage retrieves the storage slot with the given slot hash of the account in
+// the historical state, encoded in the RLP format. Nil is returned if the slot
+// was not present.
+func (r *HistoricReader) Storage(addr common.Address, slot common.Hash) ([]byte, error) {
+	defer func(start time.Time) { historicalStorageReadTimer.UpdateSince(start) }(time.Now())
+
+	return r.resolve(func(id uint64) ([]byte, bool, error) {
+		return r.historyStorage(id, addr, slot)
+	}, func(dl *diskLayer) ([]byte, error) {
+		acct, err := r.diskAccount(dl, addr)
+		if acct == nil || err != nil || acct.Root == types.EmptyRootHash {
+			return nil, err
+		}
+		tr, err := r.loader.OpenStorageTrie(dl.rootHash(), crypto.Keccak256Hash(addr.Bytes()), acct.Root)
+		if err != nil {
+			return nil, err
+		}
+		return tr.Get(slot.Bytes())
+	})
+}
+
+// resolve looks up a state in the state histories after the historical state,
+// falling back to the disk layer if none of them mutated it.
+//
+// The disk layer may progress while the lookup is in flight. A stale disk
+// layer refuses to serve the fallback, in which case the lookup continues with
+// the histories persisted in the meantime against the new disk layer.
+func (r *HistoricReader) resolve(history func(id uint64) ([]byte, bool, error), disk func(dl *diskLayer) ([]byte, error)) ([]byte, error) {
+	if err := r.checkTail(); err != nil {
+		return nil, err
+	}
+	next := r.id + 1
+	for {
+		dl := r.db.tree.bottom()
+		if err := r.checkLookback(dl); err != nil {
+			return nil, err
+		}
+		for ; next <= dl.stateID(); next++ {
+			if reads := r.reads.Add(1); r.limit != 0 && reads > r.limit {
+				return nil, fmt.Errorf("%w: limit %d", errHistoricReadLimit, r.limit)
+			}
+			blob, found, err := history(next)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				return blob, nil
+			}
+		}
+		blob, err := disk(dl)
+		if err != nil && dl.isStale() {
+			continue
+		}
+		return blob, err
+	}
+}
+
+// diskAccount retrieves the account with the given address from the account
+// trie of the disk layer.
+func (r *HistoricReader) diskAccount(dl *diskLayer, addr common.Address) (*types.StateAccount, error) {
+	tr, err := r.loader.OpenTrie(dl.rootHash())
+	if err != nil {
+		return nil, err
+	}
+	blob, err := tr.Get(crypto.Keccak256(addr.Bytes()))
+	if len(blob) == 0 || err != nil {
+		return nil, err
+	}
+	acct := new(types.StateAccount)
+	if err := rlp.DecodeBytes(blob, acct); err != nil {
+		return nil, err
+	}
+	return acct, nil
+}
+
+// historyAccountIndex locates the account with the given address in the state
+// history with the given id, leveraging the ordering of the account indexes.
+func (r *HistoricReader) historyAccountIndex(id uint64, addr common.Address) (*accountIndex, error) {
+	indexes := rawdb.ReadStateAccountIndex(r.db.freezer, id)
+	if len(indexes) == 0 || len(indexes)%accountIndexSize != 0 {
+		return nil, fmt.Errorf("state history %d is not available", id)
+	}
+	n := sort.Search(len(indexes)/accountIndexSize, func(i int) bool {
+		return bytes.Compare(indexes[i*accountIndexSize:i*accountIndexSize+common.AddressLength], addr.Bytes()) >= 0
+	})
+	if n == len(indexes)/accountIndexSize {
+		return nil, nil
+	}
+	var index accountIndex
+	index.decode(indexes[n*accountIndexSize : (n+1)*accountIndexSize])
+	if index.address != addr {
+		return nil, nil
+	}
+	return &index, nil
+}
+
+// historyAccount retrieves the original value of the account with the given
+// address from the state history with the given id, if it was mutated.
+func (r *HistoricReader) historyAccount(id uint64, addr common.Address) ([]byte, bool, error) {
+	index, err := r.historyAccountIndex(id, addr)
+	if index == nil || err != nil {
+		return nil, false, err
+	}
+	data := rawdb.ReadStateAccountHistory(r.db.freezer, id)
+	if uint32(len(data)) < index.offset+uint32(index.length) {
+		return nil, false, fmt.Errorf("state history %d account data is corrupted", id)
+	}
+	if index.length == 0 {
+		return nil, true, nil
+	}
+	return common.CopyBytes(data[index.offset : index.offset+uint32(index.length)]), true, nil
+}
+
+// historyStorage retrieves the original value of the storage slot with the
+// given slot hash from the state history with the given id, if it was mutated.
+func (r *HistoricReader) historyStorage(id uint64, addr common.Address, slot common.Hash) ([]byte, bool, error) {
+	index, err := r.historyAccountIndex(id, addr)
+	if index == nil || err != nil {
+		return nil, false, err
+	}
+	indexes := rawdb.ReadStateStorageIndex(r.db.freezer, id)
+	if uint32(len(indexes)) < (index.storageOffset+index.storageSlots)*uint32(slotIndexSize) {
+		return nil, false, fmt.Errorf("state history %d storage index is corrupted", id)
+	}
+	indexes = indexes[index.storageOffset*uint32(slotIndexSize) : (index.storageOffset+index.storageSlots)*uint32(slotIndexSize)]
+
+	n := sort.Search(int(index.storageSlots), func(i int) bool {
+		return bytes.Compare(indexes[i*slotIndexSize:i*slotIndexSize+common.HashLength], slot.Bytes()) >= 0
+	})
+	if n == int(index.storageSlots) || !bytes.Equal(indexes[n*slotIndexSize:n*slotIndexSize+common.HashLength], slot.Bytes()) {
+		// The slot is not mutated, unless the storage of the account was
+		// wiped without being recorded because of its size.
+		var m meta
+		if err := m.decode(rawdb.ReadStateHistoryMeta(r.db.freezer, id)); err != nil {
+			return nil, false, err
+		}
+		for _, incomplete := range m.incomplete {
+			if incomplete == addr {
+				return nil, false, fmt.Errorf("%w: state history %d, account %#x", errIncompleteHistory, id, addr)
+			}
+		}
+		return nil, false, nil
+	}
+	var sIndex slotIndex
+	sIndex.decode(indexes[n*slotIndexSize : (n+1)*slotIndexSize])
+
+	data := rawdb.ReadStateStorageHistory(r.db.freezer, id)
+	if uint32(len(data)) < sIndex.offset+uint32(sIndex.length) {
+		return nil, false, fmt.Errorf("state history %d storage data is corrupted", id)
+	}
+	if sIndex.length == 0 {
+		return nil, true, nil
+	}
+	return common.CopyBytes(data[sIndex.offset : sIndex.offset+uint32(sIndex.length)]), true, nil
+}
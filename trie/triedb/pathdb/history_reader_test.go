@@ -0,0 +1,167 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>
+
+package pathdb
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// checkHistoricState verifies the historical state with the given root against
+// the state snapshot taken by the tester.
+func (t *tester) checkHistoricState(root common.Hash) error {
+	disk := t.db.tree.bottom().rootHash()
+	reader, err := t.db.HistoricReader(root, newHashLoader(t.snapAccounts[disk], t.snapStorages[disk]), 0)
+	if err != nil {
+		return err
+	}
+	// All accounts ever created are checked, including the ones not present
+	// in the historical state.
+	for addrHash, addr := range t.preimages {
+		want := t.snapAccounts[root][addrHash]
+		blob, err := reader.Account(addr)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(blob, want) {
+			return errors.New("account is mismatched")
+		}
+		for slot, want := range t.snapStorages[root][addrHash] {
+			blob, err := reader.Storage(addr, slot)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(blob, want) {
+				return errors.New("storage is mismatched")
+			}
+		}
+	}
+	return nil
+}
+
+func TestHistoricReader(t *testing.T) {
+	tester := newTester(t, 0)
+	defer tester.release()
+
+	bottom := tester.bottomIndex()
+	for i := 0; i < bottom; i += 7 {
+		if err := tester.checkHistoricState(tester.roots[i]); err != nil {
+			t.Fatalf("Invalid historical state %d: %v", i, err)
+		}
+	}
+	// The disk layer itself is served through the fallback only
+	if err := tester.checkHistoricState(tester.roots[bottom]); err != nil {
+		t.Fatalf("Invalid disk layer state: %v", err)
+	}
+	// Unknown and non-persisted states can't be served
+	if _, err := tester.db.HistoricReader(common.Hash{0x1}, nil, 0); err == nil {
+		t.Fatal("Expected error for unknown state")
+	}
+	if _, err := tester.db.HistoricReader(tester.lastHash(), nil, 0); err == nil {
+		t.Fatal("Expected error for state above the disk layer")
+	}
+}
+
+func TestHistoricReaderPruned(t *testing.T) {
+	limit := uint64(10)
+	tester := newTester(t, limit)
+	defer tester.release()
+
+	// The oldest state served is the one whose subsequent state histories
+	// are all retained. The lookups of the pruned ones are dropped along.
+	bottom := tester.bottomIndex()
+	if err := tester.checkHistoricState(tester.roots[bottom-int(limit)+1]); err != nil {
+		t.Fatalf("Invalid oldest historical state: %v", err)
+	}
+	if _, err := tester.db.HistoricReader(tester.roots[bottom-int(limit)], nil, 0); err == nil {
+		t.Fatal("Expected error for pruned state")
+	}
+}
+
+func TestHistoricReaderLookback(t *testing.T) {
+	tester := newTester(t, 0)
+	defer tester.release()
+
+	lookback := 10
+	tester.db.config.HistoryLookback = uint64(lookback)
+
+	// The oldest state served is the one searching exactly the lookback of
+	// state histories on top of the disk layer.
+	bottom := tester.bottomIndex()
+	if err := tester.checkHistoricState(tester.roots[bottom-lookback]); err != nil {
+		t.Fatalf("Invalid oldest historical state: %v", err)
+	}
+	if _, err := tester.db.HistoricReader(tester.roots[bottom-lookback-1], nil, 0); !errors.Is(err, errHistoricLookback) {
+		t.Fatalf("Unexpected error for state beyond lookback: %v", err)
+	}
+}
+
+func TestHistoricReaderReadLimit(t *testing.T) {
+	tester := newTester(t, 0)
+	defer tester.release()
+
+	var (
+		disk   = tester.db.tree.bottom().rootHash()
+		loader = newHashLoader(tester.snapAccounts[disk], tester.snapStorages[disk])
+		index  = tester.bottomIndex() - 10
+		addr   = common.Address{0xde, 0xad}
+	)
+	// A missing account searches all the state histories on top of the state,
+	// the ones searched by all lookups of the reader count against its limit.
+	reader, err := tester.db.HistoricReader(tester.roots[index], loader, 10)
+	if err != nil {
+		t.Fatalf("Failed to open historical state: %v", err)
+	}
+	if blob, err := reader.Account(addr); blob != nil || err != nil {
+		t.Fatalf("Unexpected account, want nil, got %x (%v)", blob, err)
+	}
+	if _, err := reader.Account(addr); !errors.Is(err, errHistoricReadLimit) {
+		t.Fatalf("Unexpected error beyond read limit: %v", err)
+	}
+	reader, err = tester.db.HistoricReader(tester.roots[index], loader, 9)
+	if err != nil {
+		t.Fatalf("Failed to open historical state: %v", err)
+	}
+	if _, err := reader.Account(addr); !errors.Is(err, errHistoricReadLimit) {
+		t.Fatalf("Unexpected error beyond read limit: %v", err)
+	}
+}
+
+func TestHistoricReaderMissing(t *testing.T) {
+	tester := newTester(t, 0)
+	defer tester.release()
+
+	var (
+		disk   = tester.db.tree.bottom().rootHash()
+		loader = newHashLoader(tester.snapAccounts[disk], tester.snapStorages[disk])
+	)
+	reader, err := tester.db.HistoricReader(tester.roots[0], loader, 0)
+	if err != nil {
+		t.Fatalf("Failed to open historical state: %v", err)
+	}
+	addr := common.Address{0xde, 0xad}
+	if blob, err := reader.Account(addr); blob != nil || err != nil {
+		t.Fatalf("Unexpected account, want nil, got %x (%v)", blob, err)
+	}
+	if blob, err := reader.Storage(addr, crypto.Keccak256Hash(addr.Bytes())); blob != nil || err != nil {
+		t.Fatalf("Unexpected storage, want nil, got %x (%v)", blob, err)
+	}
+}
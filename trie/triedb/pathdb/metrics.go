@@ -47,4 +47,7 @@ var (
 	historyBuildTimeMeter  = metrics.NewRegisteredTimer("pathdb/history/time", nil)
 	historyDataBytesMeter  = metrics.NewRegisteredMeter("pathdb/history/bytes/data", nil)
 	historyIndexBytesMeter = metrics.NewRegisteredMeter("pathdb/history/bytes/index", nil)
+
+	historicalAccountReadTimer = metrics.NewRegisteredTimer("pathdb/history/read/account", nil)
+	historicalStorageReadTimer = metrics.NewRegisteredTimer("pathdb/history/read/storage", nil)
 )